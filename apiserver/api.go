@@ -1,28 +1,95 @@
 package apiserver
 
 import (
-	"log"
+	"context"
+	"errors"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gorilla/mux"
+
+	"github.com/smartforce-io/atc/githubservice/eventrouter"
+	"github.com/smartforce-io/atc/logger"
+	"github.com/smartforce-io/atc/metrics"
 )
 
+// DefaultShutdownTimeout is how long Start waits for the running requests
+// on SIGINT or SIGTERM.
+const DefaultShutdownTimeout = 10 * time.Second
+
 type AtcApiServer struct {
 	router *mux.Router
+	// events routes the GitHub webhooks, defaultEvents if nil
+	events *eventrouter.Router
+	// ready is the check of /readyz, the GitHub App check if nil
+	ready func(ctx context.Context) error
+}
+
+// ServerConfig configures the HTTP server of Start. TLS is used when both
+// TLSCertFile and TLSKeyFile are set.
+type ServerConfig struct {
+	Addr            string
+	WebhookSecret   string
+	TLSCertFile     string
+	TLSKeyFile      string
+	ShutdownTimeout time.Duration
 }
 
 func Instance() *AtcApiServer {
 	return &AtcApiServer{
-		mux.NewRouter().StrictSlash(true),
+		router: mux.NewRouter().StrictSlash(true),
+		events: newEventRouter(),
 	}
 }
 
-func (api *AtcApiServer) Start(host string) {
-	api.router.HandleFunc("/api/webhook", api.webhook).Methods("POST")
+func (api *AtcApiServer) Start(config ServerConfig) {
+	if config.Addr == "" {
+		logger.Errorf("ATC API Server didn't run!")
+		return
+	}
+	if config.WebhookSecret == "" {
+		logger.Warnf("webhook secret is empty, signatures of webhooks aren't checked")
+	}
+	api.router.Handle("/api/webhook", api.webhookHandler(config.WebhookSecret)).Methods("POST")
+	api.router.HandleFunc("/api/gitlab/webhook", api.gitlabWebhook).Methods("POST")
+	api.router.HandleFunc("/api/bitbucket/webhook", api.bitbucketWebhook).Methods("POST")
+	api.router.Handle("/metrics", metrics.Handler()).Methods("GET")
+	api.router.HandleFunc("/healthz", api.healthz).Methods("GET")
+	api.router.HandleFunc("/readyz", api.readyz).Methods("GET")
 
-	if host != "" {
-		log.Println("Listening HTTP for", host)
-		log.Fatal(http.ListenAndServe(host, api.router))
+	server := &http.Server{Addr: config.Addr, Handler: api.router}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		if config.TLSCertFile != "" && config.TLSKeyFile != "" {
+			logger.Infof("Listening HTTPS for %s", config.Addr)
+			serveErr <- server.ListenAndServeTLS(config.TLSCertFile, config.TLSKeyFile)
+			return
+		}
+		logger.Infof("Listening HTTP for %s", config.Addr)
+		serveErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		logger.Errorf("ATC API Server error: %v", err)
+		os.Exit(1)
+	case <-ctx.Done():
+	}
+
+	timeout := config.ShutdownTimeout
+	if timeout == 0 {
+		timeout = DefaultShutdownTimeout
+	}
+	logger.Infof("Shutting down ATC API Server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Errorf("ATC API Server shutdown error: %v", err)
 	}
-	log.Print("ATC API Server didn't run!")
 }
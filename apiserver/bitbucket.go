@@ -0,0 +1,51 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/google/go-github/v39/github"
+
+	bbprovider "github.com/smartforce-io/atc/bitbucketservice/provider"
+	"github.com/smartforce-io/atc/envvars"
+	"github.com/smartforce-io/atc/githubservice/push"
+	"github.com/smartforce-io/atc/logger"
+)
+
+// bitbucketWebhook handles the push events of a Bitbucket Cloud repository
+// or workspace webhook. Bitbucket signs the payload with the webhook secret
+// the same way GitHub does, it has to be ATC_BITBUCKET_WEBHOOK_SECRET.
+func (api *AtcApiServer) bitbucketWebhook(w http.ResponseWriter, r *http.Request) {
+	secret := os.Getenv(envvars.BitbucketWebhookSecret)
+	if secret == "" {
+		http.Error(w, "wrong webhook signature", http.StatusUnauthorized)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "can't read a webhook payload", http.StatusBadRequest)
+		return
+	}
+	if err := github.ValidateSignature(r.Header.Get("X-Hub-Signature"), body, []byte(secret)); err != nil {
+		http.Error(w, "wrong webhook signature", http.StatusUnauthorized)
+		return
+	}
+	if event := r.Header.Get("X-Event-Key"); event != "repo:push" {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("This webhook is undefined yet."))
+		return
+	}
+
+	e := &bbprovider.PushEvent{}
+	if err := json.Unmarshal(body, e); err != nil {
+		logger.Errorf("bitbucket webhook json.Unmarshal Error: %v", err)
+		http.Error(w, "can't parse a webhook payload", http.StatusBadRequest)
+		return
+	}
+	client := bbprovider.NewClient(os.Getenv(envvars.BitbucketURL), os.Getenv(envvars.BitbucketUsername),
+		os.Getenv(envvars.BitbucketToken))
+	go push.BitbucketActionPush(e, client)
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,43 @@
+package apiserver
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/smartforce-io/atc/envvars"
+	"github.com/smartforce-io/atc/githubservice/push"
+	glprovider "github.com/smartforce-io/atc/gitlabservice/provider"
+	"github.com/smartforce-io/atc/logger"
+)
+
+// gitlabWebhook handles the push events of a GitLab project or group
+// webhook. The webhook secret token has to match ATC_GITLAB_WEBHOOK_SECRET.
+func (api *AtcApiServer) gitlabWebhook(w http.ResponseWriter, r *http.Request) {
+	secret := os.Getenv(envvars.GitlabWebhookSecret)
+	if secret == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Gitlab-Token")), []byte(secret)) != 1 {
+		http.Error(w, "wrong webhook token", http.StatusUnauthorized)
+		return
+	}
+	if event := r.Header.Get("X-Gitlab-Event"); event != "Push Hook" {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("This webhook is undefined yet."))
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	e := &glprovider.PushEvent{}
+	if err := json.Unmarshal(body, e); err != nil {
+		logger.Errorf("gitlab webhook json.Unmarshal Error: %v", err)
+		http.Error(w, "can't parse a webhook payload", http.StatusBadRequest)
+		return
+	}
+	if strings.HasPrefix(e.Ref, "refs/heads/") {
+		client := glprovider.NewClient(os.Getenv(envvars.GitlabURL), os.Getenv(envvars.GitlabToken), false)
+		go push.GitlabActionPush(e, client)
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,40 @@
+package apiserver
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/smartforce-io/atc/githubservice/accesstoken"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/logger"
+)
+
+// readyTimeout bounds the checks of /readyz.
+const readyTimeout = 5 * time.Second
+
+// healthz answers the liveness probe: the server is up.
+func (api *AtcApiServer) healthz(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
+
+// readyz answers the readiness probe: the private key of the app loads, its
+// JWT can be minted and the GitHub API accepts it.
+func (api *AtcApiServer) readyz(w http.ResponseWriter, r *http.Request) {
+	check := api.ready
+	if check == nil {
+		check = func(ctx context.Context) error {
+			return accesstoken.CheckApp(ctx, &provider.GithubClientProvider{})
+		}
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), readyTimeout)
+	defer cancel()
+	if err := check(ctx); err != nil {
+		logger.Warnf("readiness check error: %v", err)
+		http.Error(w, "not ready: "+err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
@@ -0,0 +1,41 @@
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHealthz(t *testing.T) {
+	resp := httptest.NewRecorder()
+	(&AtcApiServer{}).healthz(resp, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if resp.Code != http.StatusOK || resp.Body.String() != "ok" {
+		t.Errorf("expected 200 ok, got %d %q", resp.Code, resp.Body.String())
+	}
+}
+
+func TestReadyz(t *testing.T) {
+	var tests = []struct {
+		err          error
+		expectedCode int
+		expectedBody string
+	}{
+		{nil, http.StatusOK, "ok"},
+		{errors.New("app jwt: pem data is empty"), http.StatusServiceUnavailable, "not ready: app jwt: pem data is empty\n"},
+	}
+	for _, test := range tests {
+		api := &AtcApiServer{ready: func(ctx context.Context) error {
+			if _, ok := ctx.Deadline(); !ok {
+				t.Errorf("readiness check without a deadline")
+			}
+			return test.err
+		}}
+		resp := httptest.NewRecorder()
+		api.readyz(resp, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		if resp.Code != test.expectedCode || resp.Body.String() != test.expectedBody {
+			t.Errorf("expected %d %q, got %d %q", test.expectedCode, test.expectedBody, resp.Code, resp.Body.String())
+		}
+	}
+}
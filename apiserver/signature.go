@@ -0,0 +1,35 @@
+package apiserver
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/google/go-github/v39/github"
+
+	"github.com/smartforce-io/atc/logger"
+)
+
+// NewWebhookHandler returns the GitHub App webhook handler. The
+// X-Hub-Signature-256 header of every delivery is checked against the
+// webhook secret of the app, an empty secret turns the check off.
+func NewWebhookHandler(secret string) http.Handler {
+	return (&AtcApiServer{}).webhookHandler(secret)
+}
+
+// webhookHandler is NewWebhookHandler routing the events with api.
+func (api *AtcApiServer) webhookHandler(secret string) http.Handler {
+	if secret == "" {
+		return http.HandlerFunc(api.webhook)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, err := github.ValidatePayload(r, []byte(secret))
+		if err != nil {
+			logger.Warnf("webhook signature error: %v", err)
+			http.Error(w, "wrong webhook signature", http.StatusUnauthorized)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(payload))
+		api.webhook(w, r)
+	})
+}
@@ -0,0 +1,49 @@
+package apiserver
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestWebhookHandlerSignature(t *testing.T) {
+	body := `{"ref": "refs/heads/main"}`
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(body))
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	var tests = []struct {
+		secret             string
+		signature          string
+		expectedStatus     string
+		expectedStatusCode int
+	}{
+		{"secret", signature, "success", http.StatusOK},
+		{"secret", "sha256=00", "wrong webhook signature\n", http.StatusUnauthorized},
+		{"secret", "", "wrong webhook signature\n", http.StatusUnauthorized},
+		{"other", signature, "wrong webhook signature\n", http.StatusUnauthorized},
+		{"", "", "success", http.StatusOK},
+	}
+
+	for _, test := range tests {
+		req := &http.Request{
+			Body:   io.NopCloser(bytes.NewBufferString(body)),
+			Header: make(http.Header),
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-GitHub-Event", "create")
+		if test.signature != "" {
+			req.Header.Set("X-Hub-Signature-256", test.signature)
+		}
+		resp := &maskResponseWriter{header: make(http.Header)}
+		NewWebhookHandler(test.secret).ServeHTTP(resp, req)
+		if resp.status != test.expectedStatus || resp.statusCode != test.expectedStatusCode {
+			t.Errorf("secret %q, signature %q: expected %d %q, got %d %q", test.secret, test.signature,
+				test.expectedStatusCode, test.expectedStatus, resp.statusCode, resp.status)
+		}
+	}
+}
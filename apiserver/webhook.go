@@ -1,17 +1,21 @@
 package apiserver
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
-	"log"
 	"net/http"
 	"regexp"
 	"strings"
 
 	"github.com/google/go-github/v39/github"
 
+	"github.com/smartforce-io/atc/githubservice/accesstoken"
+	"github.com/smartforce-io/atc/githubservice/eventrouter"
 	"github.com/smartforce-io/atc/githubservice/provider"
 	"github.com/smartforce-io/atc/githubservice/push"
+	"github.com/smartforce-io/atc/logger"
 )
 
 type Webhook struct {
@@ -23,50 +27,116 @@ type Installation struct {
 	Id int64 `json:"id"`
 }
 
-func (api *AtcApiServer) webhook(w http.ResponseWriter, r *http.Request) {
-	switch event := r.Header.Get("X-GitHub-Event"); event {
-	case "marketplace_purchase":
-		w.WriteHeader(http.StatusOK)
-		body, _ := io.ReadAll(r.Body)
-		log.Printf("markeplace purchase event: \n %s \n", body)
+// defaultEvents routes the GitHub webhooks of a server without its own
+// router.
+var defaultEvents = newEventRouter()
 
-	case "create":
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("success"))
+// newEventRouter returns a router with the handlers of the GitHub App
+// webhook events.
+func newEventRouter() *eventrouter.Router {
+	router := eventrouter.New()
+	router.Handle("marketplace_purchase", marketplacePurchaseEvent)
+	router.Handle("create", ignoreEvent)
+	router.Handle("delete", ignoreEvent)
+	router.Handle("installation", installationEvent)
+	router.Handle("push", pushEvent)
+	router.Handle("pull_request", pullRequestEvent)
+	return router
+}
 
-	case "delete":
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("success"))
+// HandleEvent registers handler for the GitHub webhook event, replacing the
+// built-in one.
+func (api *AtcApiServer) HandleEvent(event string, handler eventrouter.Handler) {
+	api.events.Handle(event, handler)
+}
 
-	case "push":
-		body, _ := io.ReadAll(r.Body)
-		body = removeOrgFromWebhookRequest(body)
-
-		p := &github.WebHookPayload{}
-		if err := json.Unmarshal(body, p); err != nil {
-			log.Printf("webhook json.Unmarshal Error: %v", err)
-			http.Error(w, "can't parse a webhook payload", http.StatusInternalServerError)
-			return
-		}
-		if p.Installation == nil || p.Installation.ID == nil {
-			log.Printf("p webhook doesn't contain installation info: %v", p)
-			http.Error(w, "p webhook doesn't contain installation info", http.StatusBadRequest)
-			return
-		}
-		if strings.HasPrefix(p.GetRef(), "refs/heads/") {
-			go push.ActionPush(p, &provider.GithubClientProvider{}) //it's not clear who is resposible for DI
-		}
+func (api *AtcApiServer) webhook(w http.ResponseWriter, r *http.Request) {
+	events := api.events
+	if events == nil {
+		events = defaultEvents
+	}
+	body, _ := io.ReadAll(r.Body)
+	// the request context is canceled once the response is written
+	ctx := logger.NewContext(context.Background(), logger.With("delivery_id", r.Header.Get("X-GitHub-Delivery")))
+	err := events.Dispatch(ctx, r.Header.Get("X-GitHub-Event"), body)
+	var statusErr *eventrouter.StatusError
+	switch {
+	case err == nil:
 		w.WriteHeader(http.StatusOK)
-	default:
+		w.Write([]byte("success"))
+	case errors.Is(err, eventrouter.ErrUnhandledEvent):
 		w.WriteHeader(http.StatusNotFound)
 		w.Write([]byte("This webhook is undefined yet."))
+	case errors.As(err, &statusErr):
+		http.Error(w, statusErr.Message, statusErr.StatusCode)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func badRequest(message string) error {
+	return &eventrouter.StatusError{StatusCode: http.StatusBadRequest, Message: message}
+}
+
+func ignoreEvent(ctx context.Context, payload []byte) error {
+	return nil
+}
+
+func marketplacePurchaseEvent(ctx context.Context, payload []byte) error {
+	logger.FromContext(ctx).Debugf("markeplace purchase event: \n %s \n", payload)
+	return nil
+}
+
+func installationEvent(ctx context.Context, payload []byte) error {
+	e := &github.InstallationEvent{}
+	if err := json.Unmarshal(payload, e); err != nil {
+		logger.FromContext(ctx).Errorf("installation event json.Unmarshal Error: %v", err)
+		return errors.New("can't parse an installation payload")
+	}
+	if err := accesstoken.HandleInstallationEvent(e); err != nil {
+		logger.FromContext(ctx).Errorf("installation event error: %v", err)
+		return badRequest(err.Error())
+	}
+	return nil
+}
+
+func pushEvent(ctx context.Context, payload []byte) error {
+	p := &github.WebHookPayload{}
+	if err := json.Unmarshal(removeOrgFromWebhookRequest(payload), p); err != nil {
+		logger.FromContext(ctx).Errorf("webhook json.Unmarshal Error: %v", err)
+		return errors.New("can't parse a webhook payload")
+	}
+	if p.Installation == nil || p.Installation.ID == nil {
+		logger.FromContext(ctx).Warnf("p webhook doesn't contain installation info: %v", p)
+		return badRequest("p webhook doesn't contain installation info")
+	}
+	if strings.HasPrefix(p.GetRef(), "refs/heads/") {
+		go push.ActionPushContext(ctx, p, &provider.GithubClientProvider{}) //it's not clear who is resposible for DI
+	}
+	return nil
+}
+
+func pullRequestEvent(ctx context.Context, payload []byte) error {
+	e := &github.PullRequestEvent{}
+	if err := json.Unmarshal(payload, e); err != nil {
+		logger.FromContext(ctx).Errorf("pull request event json.Unmarshal Error: %v", err)
+		return errors.New("can't parse a pull request payload")
+	}
+	if e.GetInstallation().GetID() == 0 {
+		return badRequest("pull request webhook doesn't contain installation info")
+	}
+	if e.GetAction() == "closed" {
+		go push.PullRequestMerged(ctx, e, &provider.GithubClientProvider{})
+	} else {
+		go push.PullRequestCheck(ctx, e, &provider.GithubClientProvider{})
 	}
+	return nil
 }
 
 func removeOrgFromWebhookRequest(body []byte) []byte {
 	reg, err := regexp.Compile(`,"organization":"[^\t\n\f\r\"]+"`)
 	if err != nil {
-		log.Printf("err compile regexp: %v", err)
+		logger.Errorf("err compile regexp: %v", err)
 	}
 	return []byte(reg.ReplaceAllString(string(body), ""))
 }
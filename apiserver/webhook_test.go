@@ -2,6 +2,11 @@ package apiserver
 
 import (
 	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"io"
 	"net/http"
 	"testing"
@@ -35,7 +40,11 @@ func TestWebhook(t *testing.T) {
 		expectedStatus     string
 		expectedStatusCode int
 	}{
-		{"push", `{"installation": {"id": 8}}`, "", http.StatusOK},
+		{"push", `{"installation": {"id": 8}}`, "success", http.StatusOK},
+		{"installation", `{"action": "suspend", "installation": {"id": 8}}`, "success", http.StatusOK},
+		{"installation", `{"action": "deleted"}`, "installation event doesn't contain installation info\n", http.StatusBadRequest},
+		{"pull_request", `{"action": "closed", "installation": {"id": 8}}`, "success", http.StatusOK},
+		{"pull_request", `{"action": "opened"}`, "pull request webhook doesn't contain installation info\n", http.StatusBadRequest},
 		{"def", "", "This webhook is undefined yet.", http.StatusNotFound},
 		{"", "", "This webhook is undefined yet.", http.StatusNotFound},
 	}
@@ -46,7 +55,7 @@ func TestWebhook(t *testing.T) {
 			Body:   io.NopCloser(bytes.NewBufferString(string(jsonStr))),
 			Header: make(http.Header),
 		}
-		resp := &maskResponseWriter{}
+		resp := &maskResponseWriter{header: make(http.Header)}
 		req.Header.Set("X-GitHub-Event", test.event)
 		act.webhook(resp, req)
 		if resp.status != test.expectedStatus {
@@ -69,3 +78,130 @@ func TestRemoveOrganization(t *testing.T) {
 	}
 
 }
+
+func TestGitlabWebhook(t *testing.T) {
+	t.Setenv("ATC_GITLAB_WEBHOOK_SECRET", "secret")
+	act := &AtcApiServer{}
+
+	var tests = []struct {
+		token              string
+		event              string
+		strForBody         string
+		expectedStatusCode int
+	}{
+		{"wrong", "Push Hook", `{"ref": "refs/tags/v1"}`, http.StatusUnauthorized},
+		{"", "Push Hook", `{"ref": "refs/tags/v1"}`, http.StatusUnauthorized},
+		{"secret", "Tag Push Hook", `{"ref": "refs/tags/v1"}`, http.StatusNotFound},
+		{"secret", "Push Hook", `{`, http.StatusBadRequest},
+		{"secret", "Push Hook", `{"ref": "refs/tags/v1"}`, http.StatusOK},
+	}
+
+	for _, test := range tests {
+		req := &http.Request{
+			Body:   io.NopCloser(bytes.NewBufferString(test.strForBody)),
+			Header: make(http.Header),
+		}
+		resp := &maskResponseWriter{header: make(http.Header)}
+		req.Header.Set("X-Gitlab-Token", test.token)
+		req.Header.Set("X-Gitlab-Event", test.event)
+		act.gitlabWebhook(resp, req)
+		if resp.statusCode != test.expectedStatusCode {
+			t.Errorf("token %q, event %q: expected status %d, got %d", test.token, test.event, test.expectedStatusCode, resp.statusCode)
+		}
+	}
+}
+
+func TestBitbucketWebhook(t *testing.T) {
+	t.Setenv("ATC_BITBUCKET_WEBHOOK_SECRET", "secret")
+	act := &AtcApiServer{}
+	sign := func(body string) string {
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write([]byte(body))
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	var tests = []struct {
+		signature          string
+		event              string
+		body               string
+		expectedStatusCode int
+	}{
+		{"sha256=00", "repo:push", `{}`, http.StatusUnauthorized},
+		{"", "repo:push", `{}`, http.StatusUnauthorized},
+		{sign(`{}`), "pullrequest:created", `{}`, http.StatusNotFound},
+		{sign(`{`), "repo:push", `{`, http.StatusBadRequest},
+		{sign(`{"push": {"changes": []}}`), "repo:push", `{"push": {"changes": []}}`, http.StatusOK},
+	}
+
+	for _, test := range tests {
+		req := &http.Request{
+			Body:   io.NopCloser(bytes.NewBufferString(test.body)),
+			Header: make(http.Header),
+		}
+		resp := &maskResponseWriter{header: make(http.Header)}
+		req.Header.Set("X-Hub-Signature", test.signature)
+		req.Header.Set("X-Event-Key", test.event)
+		act.bitbucketWebhook(resp, req)
+		if resp.statusCode != test.expectedStatusCode {
+			t.Errorf("signature %q, event %q: expected status %d, got %d", test.signature, test.event, test.expectedStatusCode, resp.statusCode)
+		}
+	}
+}
+
+func TestWebhookRedelivery(t *testing.T) {
+	api := Instance()
+	calls := 0
+	api.HandleEvent("push", func(ctx context.Context, body []byte) error {
+		calls++
+		if calls == 1 {
+			return errors.New("GitHub is down")
+		}
+		return nil
+	})
+
+	// a redelivery of a failed delivery is handled again
+	for _, expectedStatus := range []int{http.StatusInternalServerError, http.StatusOK} {
+		req := &http.Request{
+			Body:   io.NopCloser(bytes.NewBufferString(`{}`)),
+			Header: make(http.Header),
+		}
+		req.Header.Set("X-GitHub-Event", "push")
+		req.Header.Set("X-GitHub-Delivery", "72d3162e-cc78-11e3-81ab-4c9367dc0958")
+		resp := &maskResponseWriter{header: make(http.Header)}
+		api.webhook(resp, req)
+		if resp.statusCode != expectedStatus {
+			t.Errorf("call %d: expected status %d, got %d %q", calls, expectedStatus, resp.statusCode, resp.status)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestHandleEvent(t *testing.T) {
+	api := Instance()
+	var payload string
+	api.HandleEvent("release", func(ctx context.Context, body []byte) error {
+		payload = string(body)
+		return nil
+	})
+
+	req := &http.Request{
+		Body:   io.NopCloser(bytes.NewBufferString(`{"action": "published"}`)),
+		Header: make(http.Header),
+	}
+	req.Header.Set("X-GitHub-Event", "release")
+	resp := &maskResponseWriter{header: make(http.Header)}
+	api.webhookHandler("").ServeHTTP(resp, req)
+	if resp.statusCode != http.StatusOK || payload != `{"action": "published"}` {
+		t.Errorf("release event isn't handled: %d %q, payload %q", resp.statusCode, resp.status, payload)
+	}
+
+	// the other servers keep the default handlers
+	req.Body = io.NopCloser(bytes.NewBufferString(`{}`))
+	resp = &maskResponseWriter{header: make(http.Header)}
+	NewWebhookHandler("").ServeHTTP(resp, req)
+	if resp.statusCode != http.StatusNotFound {
+		t.Errorf("expected release to be unhandled, got %d", resp.statusCode)
+	}
+}
@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	ghprovider "github.com/smartforce-io/atc/githubservice/provider"
+)
+
+const DefaultBaseURL = "https://api.bitbucket.org/2.0"
+
+// Client is a minimal Bitbucket Cloud REST API 2.0 client covering what ATC
+// needs: reading files, reading the main branch and creating tags.
+type Client struct {
+	BaseURL string
+	Token   string
+	// Username turns Token into an app password sent with basic auth,
+	// otherwise Token is an access token sent as a bearer token.
+	Username   string
+	HTTPClient *http.Client
+}
+
+type Tag struct {
+	Name   string `json:"name"`
+	Target Target `json:"target"`
+}
+
+type Target struct {
+	Hash string `json:"hash"`
+}
+
+func NewClient(baseURL, username, token string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{
+		BaseURL:    baseURL,
+		Token:      token,
+		Username:   username,
+		HTTPClient: http.DefaultClient,
+	}
+}
+
+func (c *Client) do(method, path string, body interface{}) ([]byte, error) {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, c.BaseURL+path, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Username != "" {
+		req.SetBasicAuth(c.Username, c.Token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%w: %s %s: %d %s", ghprovider.ErrHttpStatusCode, method, path, resp.StatusCode, respBody)
+	}
+	return respBody, nil
+}
+
+// escapePath escapes every segment of a slash separated path.
+func escapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// repoPath is the API path of a repository, fullName is "workspace/repo_slug".
+func repoPath(fullName string) string {
+	return "/repositories/" + escapePath(fullName)
+}
+
+// GetFile returns the raw content of path at the commit ref.
+func (c *Client) GetFile(fullName, path, ref string) (string, error) {
+	body, err := c.do(http.MethodGet, repoPath(fullName)+"/src/"+url.PathEscape(ref)+"/"+escapePath(path), nil)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// GetMainBranch returns the name of the main branch of the repository.
+func (c *Client) GetMainBranch(fullName string) (string, error) {
+	body, err := c.do(http.MethodGet, repoPath(fullName), nil)
+	if err != nil {
+		return "", err
+	}
+	repository := &struct {
+		MainBranch struct {
+			Name string `json:"name"`
+		} `json:"mainbranch"`
+	}{}
+	if err := json.Unmarshal(body, repository); err != nil {
+		return "", err
+	}
+	return repository.MainBranch.Name, nil
+}
+
+// CreateTag creates an annotated tag when message isn't empty, otherwise a lightweight one.
+func (c *Client) CreateTag(fullName, name, hash, message string) (*Tag, error) {
+	req := map[string]interface{}{
+		"name":   name,
+		"target": Target{Hash: hash},
+	}
+	if message != "" {
+		req["message"] = message
+	}
+	body, err := c.do(http.MethodPost, repoPath(fullName)+"/refs/tags", req)
+	if err != nil {
+		return nil, err
+	}
+	tag := &Tag{}
+	if err := json.Unmarshal(body, tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
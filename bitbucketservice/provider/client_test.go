@@ -0,0 +1,79 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ghprovider "github.com/smartforce-io/atc/githubservice/provider"
+)
+
+func TestBbContentProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.EscapedPath() != "/repositories/team/atc/src/abc/app/pom.xml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("<version>5</version>"))
+	}))
+	defer server.Close()
+
+	cp := &BbContentProvider{Client: NewClient(server.URL, "", "token"), FullName: "team/atc", Ref: "abc"}
+	content, err := cp.GetContents("app/pom.xml")
+	if err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if content != "<version>5</version>" {
+		t.Errorf("wrong content! Got %q", content)
+	}
+
+	_, err = cp.GetContents("pom.xml")
+	if !errors.Is(err, ghprovider.ErrHttpStatusCode) {
+		t.Errorf("err:%v  !=  ErrHttpStatusCode", err)
+	}
+}
+
+func TestCreateTag(t *testing.T) {
+	var got struct {
+		Name    string `json:"name"`
+		Target  Target `json:"target"`
+		Message string `json:"message"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); r.Method != http.MethodPost || !ok || username != "dev" || password != "app-password" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"name": "v5", "target": {"hash": "abc"}}`))
+	}))
+	defer server.Close()
+
+	tag, err := NewClient(server.URL, "dev", "app-password").CreateTag("team/atc", "v5", "abc", "v5")
+	if err != nil {
+		t.Errorf("Unexpected error %v", err)
+		return
+	}
+	if tag.Name != "v5" || got.Name != "v5" || got.Target.Hash != "abc" || got.Message != "v5" {
+		t.Errorf("wrong tag request: %+v, response: %+v", got, tag)
+	}
+}
+
+func TestGetMainBranch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"full_name": "team/atc", "mainbranch": {"type": "branch", "name": "master"}}`))
+	}))
+	defer server.Close()
+
+	branch, err := NewClient(server.URL, "", "token").GetMainBranch("team/atc")
+	if err != nil || branch != "master" {
+		t.Errorf("expected master, got %q, %v", branch, err)
+	}
+}
@@ -0,0 +1,14 @@
+package provider
+
+// BbContentProvider reads files of a Bitbucket repository at the commit Ref
+// and satisfies the githubservice provider.ContentProvider interface, so all
+// version fetchers work with Bitbucket as well.
+type BbContentProvider struct {
+	Client   *Client
+	FullName string
+	Ref      string
+}
+
+func (bbcp *BbContentProvider) GetContents(path string) (string, error) {
+	return bbcp.Client.GetFile(bbcp.FullName, path, bbcp.Ref)
+}
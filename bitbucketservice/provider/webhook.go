@@ -0,0 +1,34 @@
+package provider
+
+// PushEvent is the part of a Bitbucket "repo:push" payload ATC needs.
+type PushEvent struct {
+	Actor      Actor      `json:"actor"`
+	Repository Repository `json:"repository"`
+	Push       Push       `json:"push"`
+}
+
+type Actor struct {
+	Nickname string `json:"nickname"`
+}
+
+type Repository struct {
+	FullName string `json:"full_name"`
+}
+
+type Push struct {
+	Changes []Change `json:"changes"`
+}
+
+// Change is a pushed ref. Old is nil for a new branch, New is nil for a
+// deleted one.
+type Change struct {
+	Old *Ref `json:"old"`
+	New *Ref `json:"new"`
+}
+
+// Ref is a branch or tag, Type is "branch" or "tag".
+type Ref struct {
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Target Target `json:"target"`
+}
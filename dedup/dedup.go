@@ -0,0 +1,23 @@
+// Package dedup remembers created tags, so redelivered webhooks and
+// force-pushed histories don't tag or comment twice.
+package dedup
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	DefaultCapacity = 10000
+	DefaultTTL      = 24 * time.Hour
+)
+
+// Store records keys for a while. Deployments with several ATC instances
+// need a shared backend like RedisStore.
+type Store interface {
+	// Seen records key and reports whether it was recorded already.
+	Seen(ctx context.Context, key string) (bool, error)
+	// Forget removes key, e.g. when the work it guards failed and may be
+	// retried.
+	Forget(ctx context.Context, key string) error
+}
@@ -0,0 +1,71 @@
+package dedup
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	key     string
+	expires time.Time
+}
+
+// MemoryStore is an in-memory LRU store of at most capacity keys, each kept
+// for ttl. A ttl of 0 keeps keys until they're evicted.
+type MemoryStore struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	order    *list.List
+	entries  map[string]*list.Element
+	now      func() time.Time
+}
+
+func NewMemoryStore(capacity int, ttl time.Duration) *MemoryStore {
+	return &MemoryStore{
+		capacity: capacity,
+		ttl:      ttl,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+		now:      time.Now,
+	}
+}
+
+func (store *MemoryStore) Seen(ctx context.Context, key string) (bool, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	now := store.now()
+	var expires time.Time
+	if store.ttl > 0 {
+		expires = now.Add(store.ttl)
+	}
+	if element, ok := store.entries[key]; ok {
+		entry := element.Value.(*memoryEntry)
+		if entry.expires.IsZero() || now.Before(entry.expires) {
+			store.order.MoveToFront(element)
+			return true, nil
+		}
+		entry.expires = expires
+		store.order.MoveToFront(element)
+		return false, nil
+	}
+	store.entries[key] = store.order.PushFront(&memoryEntry{key: key, expires: expires})
+	for store.capacity > 0 && store.order.Len() > store.capacity {
+		oldest := store.order.Back()
+		store.order.Remove(oldest)
+		delete(store.entries, oldest.Value.(*memoryEntry).key)
+	}
+	return false, nil
+}
+
+func (store *MemoryStore) Forget(ctx context.Context, key string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if element, ok := store.entries[key]; ok {
+		store.order.Remove(element)
+		delete(store.entries, key)
+	}
+	return nil
+}
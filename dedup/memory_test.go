@@ -0,0 +1,41 @@
+package dedup
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore(2, time.Hour)
+	store.now = func() time.Time { return now }
+
+	var steps = []struct {
+		key      string
+		advance  time.Duration
+		expected bool
+	}{
+		{"a", 0, false},
+		{"a", 0, true},
+		{"b", 0, false},
+		{"a", 0, true},  // a is the most recent now
+		{"c", 0, false}, // evicts b
+		{"b", 0, false},
+		{"a", 0, false}, // evicted by b
+		{"b", 2 * time.Hour, false},
+		{"b", 0, true},
+	}
+	for i, step := range steps {
+		now = now.Add(step.advance)
+		if seen, err := store.Seen(ctx, step.key); err != nil || seen != step.expected {
+			t.Errorf("step %d, key %q: expected %v, got %v, %v", i, step.key, step.expected, seen, err)
+		}
+	}
+
+	store.Forget(ctx, "b")
+	if seen, _ := store.Seen(ctx, "b"); seen {
+		t.Errorf("forgotten key is seen")
+	}
+}
@@ -0,0 +1,39 @@
+package dedup
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore keeps the keys in Redis with SET NX, so several ATC instances
+// share them.
+type RedisStore struct {
+	Client *redis.Client
+	// Prefix is put before every key, e.g. "atc:".
+	Prefix string
+	TTL    time.Duration
+}
+
+// NewRedisStore connects to a redis://[user:password@]host:port[/db] or
+// rediss:// URL.
+func NewRedisStore(redisURL string, ttl time.Duration) (*RedisStore, error) {
+	options, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, err
+	}
+	return &RedisStore{Client: redis.NewClient(options), Prefix: "atc:", TTL: ttl}, nil
+}
+
+func (store *RedisStore) Seen(ctx context.Context, key string) (bool, error) {
+	set, err := store.Client.SetNX(ctx, store.Prefix+key, 1, store.TTL).Result()
+	if err != nil {
+		return false, err
+	}
+	return !set, nil
+}
+
+func (store *RedisStore) Forget(ctx context.Context, key string) error {
+	return store.Client.Del(ctx, store.Prefix+key).Err()
+}
@@ -4,4 +4,43 @@ const (
 	PemData         = "ATC_PEM_DATA"
 	PemPathVariable = "ATC_PEM_PATH"
 	AppId           = "ATC_APP_ID"
+	JiraAPIToken    = "ATC_JIRA_API_TOKEN"
+	// JiraBaseURLs are the comma separated JIRA base URLs JiraAPIToken is
+	// sent to, .atc.yaml files can't send it anywhere else.
+	JiraBaseURLs = "ATC_JIRA_BASE_URLS"
+	NotifyToken  = "ATC_NOTIFY_TOKEN"
+	// NotifyURLs are the comma separated URLs, including the URLs below them,
+	// NotifyToken is sent to.
+	NotifyURLs = "ATC_NOTIFY_URLS"
+	LogLevel   = "ATC_LOG_LEVEL"
+	// LogFormat is "json" for JSON log lines, text otherwise.
+	LogFormat = "ATC_LOG_FORMAT"
+	// WebhookSecret is the webhook secret of the GitHub App, TLSCertFile and
+	// TLSKeyFile turn on HTTPS for the webhook server.
+	WebhookSecret = "ATC_WEBHOOK_SECRET"
+	TLSCertFile   = "ATC_TLS_CERT_FILE"
+	TLSKeyFile    = "ATC_TLS_KEY_FILE"
+	// GitlabToken, GitlabURL and GitlabWebhookSecret configure the GitLab
+	// webhook: the API token, the API URL of self-managed instances and the
+	// secret token of the webhook.
+	GitlabToken         = "ATC_GITLAB_TOKEN"
+	GitlabURL           = "ATC_GITLAB_URL"
+	GitlabWebhookSecret = "ATC_GITLAB_WEBHOOK_SECRET"
+	// BitbucketToken is an access token, or an app password of
+	// BitbucketUsername, for the Bitbucket Cloud webhook. BitbucketURL
+	// overrides the API URL and BitbucketWebhookSecret is the webhook secret.
+	BitbucketToken         = "ATC_BITBUCKET_TOKEN"
+	BitbucketUsername      = "ATC_BITBUCKET_USERNAME"
+	BitbucketURL           = "ATC_BITBUCKET_URL"
+	BitbucketWebhookSecret = "ATC_BITBUCKET_WEBHOOK_SECRET"
+	// TagSigningKey is an armored GPG or an OpenSSH private key annotated
+	// tags are signed with, TagSigningKeyPassphrase decrypts it.
+	TagSigningKey           = "ATC_TAG_SIGNING_KEY"
+	TagSigningKeyPassphrase = "ATC_TAG_SIGNING_KEY_PASSPHRASE"
+	// RedisURL is a redis://[user:password@]host:port[/db] URL of the store
+	// of created tags, they're kept in memory otherwise.
+	RedisURL = "ATC_REDIS_URL"
+	// RetryQueueFile is the JSON file the tags waiting for a retry are kept
+	// in across restarts, they're only kept in memory if it's empty.
+	RetryQueueFile = "ATC_RETRY_QUEUE_FILE"
 )
@@ -3,21 +3,143 @@ package accesstoken
 import (
 	"context"
 	"errors"
-	"log"
+	"fmt"
 	"net/http"
 	"os"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v39/github"
 
 	"github.com/smartforce-io/atc/githubservice/provider"
 
 	"github.com/smartforce-io/atc/envvars"
 	"github.com/smartforce-io/atc/githubservice/jwt"
+	"github.com/smartforce-io/atc/logger"
+)
+
+const (
+	InstallationActionSuspend = "suspend"
+	InstallationActionDeleted = "deleted"
 )
 
 var (
 	errWrongCreateAccessTokenStatus = errors.New("wrong access status during create access token for installation (not 201)")
+	errNoInstallationInEvent        = errors.New("installation event doesn't contain installation info")
 )
 
+// tokenExpiryMargin is how long before its expiry a cached token is
+// replaced, so a push doesn't get a token that expires while it's used.
+const tokenExpiryMargin = 5 * time.Minute
+
+type cachedToken struct {
+	token     string
+	expiresAt time.Time
+}
+
+// tokenCall is a running request for an installation token, the pushes
+// of the installation arriving meanwhile wait for its result.
+type tokenCall struct {
+	done  chan struct{}
+	token string
+	err   error
+}
+
+var (
+	tokensMu sync.Mutex
+	tokens   = map[int64]cachedToken{}
+	calls    = map[int64]*tokenCall{}
+)
+
+func getCachedToken(id int64) (string, bool) {
+	tokensMu.Lock()
+	defer tokensMu.Unlock()
+	t, ok := tokens[id]
+	if !ok || !time.Now().Add(tokenExpiryMargin).Before(t.expiresAt) {
+		return "", false
+	}
+	return t.token, true
+}
+
+func cacheToken(id int64, token string, expiresAt time.Time) {
+	tokensMu.Lock()
+	defer tokensMu.Unlock()
+	tokens[id] = cachedToken{token: token, expiresAt: expiresAt}
+}
+
+func forgetToken(id int64) {
+	tokensMu.Lock()
+	defer tokensMu.Unlock()
+	delete(tokens, id)
+}
+
+// GetAccessToken returns an installation token of the app, cached until
+// shortly before it expires. Concurrent calls for an installation share one
+// request to GitHub.
 func GetAccessToken(id int64, clientProvider provider.ClientProvider) (string, error) {
+	if token, ok := getCachedToken(id); ok {
+		return token, nil
+	}
+
+	tokensMu.Lock()
+	if call, ok := calls[id]; ok {
+		tokensMu.Unlock()
+		<-call.done
+		return call.token, call.err
+	}
+	call := &tokenCall{done: make(chan struct{})}
+	calls[id] = call
+	tokensMu.Unlock()
+
+	var expiresAt time.Time
+	call.token, expiresAt, call.err = createAccessToken(id, clientProvider)
+
+	if call.err == nil {
+		cacheToken(id, call.token, expiresAt)
+	}
+	tokensMu.Lock()
+	delete(calls, id)
+	tokensMu.Unlock()
+	close(call.done)
+	return call.token, call.err
+}
+
+func createAccessToken(id int64, clientProvider provider.ClientProvider) (string, time.Time, error) {
+	j, err := appJwt()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	ctx := context.Background()
+	client := clientProvider.Get(j, ctx)
+	inst, resp, err := client.Apps.CreateInstallationToken(ctx, id, nil)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return "", time.Time{}, errWrongCreateAccessTokenStatus
+	}
+
+	return inst.GetToken(), inst.GetExpiresAt(), nil
+}
+
+// GetRepositoryInstallationID returns the ID of the installation of the app
+// on the repository owner/repo.
+func GetRepositoryInstallationID(ctx context.Context, owner, repo string, clientProvider provider.ClientProvider) (int64, error) {
+	j, err := appJwt()
+	if err != nil {
+		return 0, fmt.Errorf("app jwt: %w", err)
+	}
+	inst, _, err := clientProvider.Get(j, ctx).Apps.FindRepositoryInstallation(ctx, owner, repo)
+	if err != nil {
+		return 0, err
+	}
+	return inst.GetID(), nil
+}
+
+// appJwt mints the JWT of the app with the private key of ATC_PEM_DATA or
+// the file of ATC_PEM_PATH.
+func appJwt() (string, error) {
 	var pemData []byte
 	var err error
 	pemEnv := os.Getenv(envvars.PemData)
@@ -30,27 +152,46 @@ func GetAccessToken(id int64, clientProvider provider.ClientProvider) (string, e
 		if err != nil {
 			return "", err
 		}
-		log.Printf("ATC uses pem from file: %q", pemPath)
+		logger.Debugf("ATC uses pem from file: %q", pemPath)
 
 	} else {
 		pemData = []byte(pemEnv)
-		log.Print("ATC uses pem data from environment variable")
+		logger.Debugf("ATC uses pem data from environment variable")
 	}
+	return jwt.GetJwt(pemData)
+}
 
-	j, err := jwt.GetJwt(pemData)
-	if err != nil {
-		return "", err
-	}
+// CheckApp reports whether the private key of the app loads, its JWT can be
+// minted and the GitHub API accepts it.
+func CheckApp(ctx context.Context, clientProvider provider.ClientProvider) error {
+	_, err := GetApp(ctx, clientProvider)
+	return err
+}
 
-	ctx := context.Background()
-	client := clientProvider.Get(j, ctx)
-	inst, resp, err := client.Apps.CreateInstallationToken(ctx, id, nil)
+// GetApp returns the app ATC authenticates as, e.g. for its slug.
+func GetApp(ctx context.Context, clientProvider provider.ClientProvider) (*github.App, error) {
+	j, err := appJwt()
 	if err != nil {
-		return "", err
+		return nil, fmt.Errorf("app jwt: %w", err)
 	}
-	if resp.StatusCode != http.StatusCreated {
-		return "", errWrongCreateAccessTokenStatus
+	app, _, err := clientProvider.Get(j, ctx).Apps.Get(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("github api: %w", err)
 	}
+	return app, nil
+}
 
-	return inst.GetToken(), nil
+// HandleInstallationEvent drops the cached token of an installation that was
+// suspended or deleted, so the next push doesn't reuse a revoked token.
+func HandleInstallationEvent(event *github.InstallationEvent) error {
+	if event.GetInstallation() == nil || event.GetInstallation().ID == nil {
+		return errNoInstallationInEvent
+	}
+	id := event.GetInstallation().GetID()
+	switch action := event.GetAction(); action {
+	case InstallationActionSuspend, InstallationActionDeleted:
+		forgetToken(id)
+		logger.Infof("installation %d: %s, cached access token removed", id, action)
+	}
+	return nil
 }
@@ -1,11 +1,21 @@
 package accesstoken
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/google/go-github/v39/github"
 
 	"github.com/smartforce-io/atc/envvars"
+	"github.com/smartforce-io/atc/githubservice/jwt"
 	"github.com/smartforce-io/atc/githubservice/provider"
 )
 
@@ -109,3 +119,113 @@ func TestErrorGetPemFromPemPathVariable(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestHandleInstallationEventRemovesCachedToken(t *testing.T) {
+	var tests = []struct {
+		action      string
+		keepsCached bool
+	}{
+		{"suspend", false},
+		{"deleted", false},
+		{"unsuspend", true},
+	}
+	for _, test := range tests {
+		cacheToken(42, "cached", time.Now().Add(time.Hour))
+		action := test.action
+		id := int64(42)
+		err := HandleInstallationEvent(&github.InstallationEvent{Action: &action, Installation: &github.Installation{ID: &id}})
+		if err != nil {
+			t.Errorf("Unexpected error %v", err)
+		}
+		if _, ok := getCachedToken(42); ok != test.keepsCached {
+			t.Errorf("action %q: token cached = %v, expected %v", test.action, ok, test.keepsCached)
+		}
+	}
+	forgetToken(42)
+}
+
+func TestGetAccessTokenUsesCache(t *testing.T) {
+	cacheToken(11, "cached", time.Now().Add(time.Hour))
+	defer forgetToken(11)
+
+	token, err := GetAccessToken(11, provider.DefaultMockClientProvider())
+	if err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if token != "cached" {
+		t.Errorf("Unexpected token, expected %s, got %s", "cached", token)
+	}
+}
+
+func TestGetAccessTokenSingleFlight(t *testing.T) {
+	os.Setenv(envvars.PemData, testRsaKey)
+	defer forgetToken(12)
+
+	var requests int32
+	release := make(chan struct{})
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+	mockClientProviderPtr.OverrideResponseFn("GET_TOKEN", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		atomic.AddInt32(&requests, 1)
+		<-release
+		expiresAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+		return provider.NewTestResponse(201, fmt.Sprintf(`{"token": "minted", "expires_at": %q}`, expiresAt))
+	})
+
+	var wg sync.WaitGroup
+	tokens := make([]string, 10)
+	for i := range tokens {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tokens[i], _ = GetAccessToken(12, mockClientProviderPtr)
+		}(i)
+	}
+	// let the goroutines join the running request
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("expected 1 token request, got %d", n)
+	}
+	for i, token := range tokens {
+		if token != "minted" {
+			t.Errorf("call %d: expected %q, got %q", i, "minted", token)
+		}
+	}
+	if token, ok := getCachedToken(12); !ok || token != "minted" {
+		t.Errorf("token isn't cached: %q, %v", token, ok)
+	}
+}
+
+func TestCachedTokenExpiryMargin(t *testing.T) {
+	defer forgetToken(13)
+	cacheToken(13, "expiring", time.Now().Add(tokenExpiryMargin/2))
+	if _, ok := getCachedToken(13); ok {
+		t.Errorf("token expiring within %v is used", tokenExpiryMargin)
+	}
+}
+
+func TestCheckApp(t *testing.T) {
+	os.Setenv(envvars.PemData, testRsaKey)
+	defer os.Unsetenv(envvars.PemData)
+	ctx := context.Background()
+
+	if err := CheckApp(ctx, provider.DefaultMockClientProvider()); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+	mockClientProviderPtr.OverrideResponseFn("GET_APP", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		return provider.NewTestResponse(http.StatusUnauthorized, `{"message": "A JSON web token could not be decoded"}`)
+	})
+	if err := CheckApp(ctx, mockClientProviderPtr); err == nil || !strings.HasPrefix(err.Error(), "github api: ") {
+		t.Errorf("expected a github api error, got %v", err)
+	}
+
+	os.Unsetenv(envvars.PemData)
+	os.Unsetenv(envvars.PemPathVariable)
+	if err := CheckApp(ctx, provider.DefaultMockClientProvider()); !errors.Is(err, jwt.ErrNoPemEnv) {
+		t.Errorf("expected %v, got %v", jwt.ErrNoPemEnv, err)
+	}
+}
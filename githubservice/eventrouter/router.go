@@ -0,0 +1,73 @@
+// Package eventrouter dispatches GitHub webhook events to the handlers
+// registered for their names, e.g. "push" or "pull_request".
+package eventrouter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Handler handles the payload of a GitHub webhook event. GitHub expects the
+// response within 10 seconds, so tagging runs in a goroutine. ctx carries
+// the logger of the delivery and isn't canceled with the request.
+type Handler func(ctx context.Context, payload []byte) error
+
+// ErrUnhandledEvent is returned by Dispatch for an event without handler.
+var ErrUnhandledEvent = errors.New("event isn't handled")
+
+// StatusError is a handler error answered with StatusCode, e.g.
+// http.StatusBadRequest for a payload without installation. Other errors
+// are internal server errors.
+type StatusError struct {
+	StatusCode int
+	Message    string
+}
+
+func (err *StatusError) Error() string {
+	return err.Message
+}
+
+// Router maps GitHub event names to their handlers. It's safe for
+// concurrent use.
+type Router struct {
+	mu       sync.RWMutex
+	handlers map[string]Handler
+}
+
+func New() *Router {
+	return &Router{handlers: map[string]Handler{}}
+}
+
+// Handle registers handler for event, replacing the handler registered
+// before.
+func (router *Router) Handle(event string, handler Handler) {
+	router.mu.Lock()
+	defer router.mu.Unlock()
+	router.handlers[event] = handler
+}
+
+// Events returns the names of the handled events, sorted.
+func (router *Router) Events() []string {
+	router.mu.RLock()
+	defer router.mu.RUnlock()
+	events := make([]string, 0, len(router.handlers))
+	for event := range router.handlers {
+		events = append(events, event)
+	}
+	sort.Strings(events)
+	return events
+}
+
+// Dispatch runs the handler of event with payload.
+func (router *Router) Dispatch(ctx context.Context, event string, payload []byte) error {
+	router.mu.RLock()
+	handler, ok := router.handlers[event]
+	router.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnhandledEvent, event)
+	}
+	return handler(ctx, payload)
+}
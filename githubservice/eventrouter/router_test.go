@@ -0,0 +1,42 @@
+package eventrouter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestRouter(t *testing.T) {
+	router := New()
+	var handled []string
+	router.Handle("push", func(ctx context.Context, payload []byte) error {
+		handled = append(handled, "push "+string(payload))
+		return nil
+	})
+	router.Handle("release", func(ctx context.Context, payload []byte) error {
+		return &StatusError{StatusCode: http.StatusBadRequest, Message: "no release"}
+	})
+	router.Handle("push", func(ctx context.Context, payload []byte) error {
+		handled = append(handled, "replaced push "+string(payload))
+		return nil
+	})
+
+	if err := router.Dispatch(context.Background(), "push", []byte("{}")); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(handled, []string{"replaced push {}"}) {
+		t.Errorf("wrong handlers run: %q", handled)
+	}
+	var statusErr *StatusError
+	if err := router.Dispatch(context.Background(), "release", nil); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected a bad request error, got %v", err)
+	}
+	if err := router.Dispatch(context.Background(), "create", nil); !errors.Is(err, ErrUnhandledEvent) {
+		t.Errorf("expected %v, got %v", ErrUnhandledEvent, err)
+	}
+	if events := router.Events(); !reflect.DeepEqual(events, []string{"push", "release"}) {
+		t.Errorf("wrong events %q", events)
+	}
+}
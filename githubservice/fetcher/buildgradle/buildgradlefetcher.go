@@ -1,48 +1,144 @@
 package buildgradle
 
 import (
-	"log"
 	"regexp"
+	"strconv"
 
 	"github.com/smartforce-io/atc/githubservice/provider"
 	"github.com/smartforce-io/atc/githubservice/settings"
 
 	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/logger"
 )
 
 type BuildGradle struct {
 	Version string `gradle:"version"`
 }
 
+// Fetcher reads the project `version` of a Gradle build script, or the
+// versionName of an Android one. The Groovy and Kotlin DSL are the same to
+// it.
 type Fetcher struct {
+	// DefaultPaths are tried in order by GetVersionUsingDefaultPath,
+	// defaultPaths if nil.
+	DefaultPaths []string
 }
 
+// defaultPaths are tried in order by GetVersionUsingDefaultPath.
+var defaultPaths = []string{"app/build.gradle"}
+
+// NewKotlinFetcher returns the Fetcher of a build.gradle.kts in the root or
+// the app module.
+func NewKotlinFetcher() *Fetcher {
+	return &Fetcher{DefaultPaths: []string{"build.gradle.kts", "app/build.gradle.kts"}}
+}
+
+var (
+	projectVersionRegex = regexp.MustCompile(`(?m)^[\t ]*version\s*=?\s*["']([^"']+)["']`)
+	versionNameRegex    = regexp.MustCompile(`versionName\s*=?\s*["']([^"']+)["']`)
+	versionCodeRegex    = regexp.MustCompile(`versionCode\s*=?\s*(\d+)`)
+)
+
 var unmarshalBuildGradle = func(content []byte, buildGradlePtr *BuildGradle) error {
 	regex, err := regexp.Compile(`defaultConfig {[^{}]*([^{}]*{[\s\S]*}[^{}]*)*[^{}]*\n[\t ]*versionName "(.+)"`)
 	if err != nil {
-		log.Printf("regexp compile err: %v", err)
+		logger.Errorf("regexp compile err: %v", err)
 		return err
 	}
 	res := regex.FindStringSubmatch(string(content))
 	if len(res) < 2 {
-		return fetcher.ErrNoVers
+		// productFlavors and other blocks may declare versionName outside defaultConfig
+		res = versionNameRegex.FindStringSubmatch(string(content))
+		if len(res) < 2 {
+			return fetcher.ErrNoVers
+		}
+		buildGradlePtr.Version = res[1]
+		return nil
 	}
 	buildGradlePtr.Version = res[2]
 	return nil
 }
 
-func (buildGradleFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
-	content, err := ghContentProvider.GetContents(settings.Path)
+var unmarshalProjectVersion = func(content []byte, buildGradlePtr *BuildGradle) error {
+	res := projectVersionRegex.FindStringSubmatch(string(content))
+	if len(res) < 2 {
+		return fetcher.ErrNoVers
+	}
+	buildGradlePtr.Version = res[1]
+	return nil
+}
+
+var unmarshalVersionCode = func(content []byte, buildGradlePtr *BuildGradle) error {
+	res := versionCodeRegex.FindStringSubmatch(string(content))
+	if len(res) < 2 {
+		return fetcher.ErrNoVers
+	}
+	code, err := strconv.Atoi(res[1])
+	if err != nil {
+		return err
+	}
+	// normalize the integer so "007" and "7" are compared as the same versionCode
+	buildGradlePtr.Version = strconv.Itoa(code)
+	return nil
+}
+
+func (buildGradleFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, atcSettings settings.AtcSettings) (string, error) {
+	content, err := ghContentProvider.GetContents(atcSettings.Path)
 	if err != nil {
 		return "", err
 	}
 	gradle := &BuildGradle{}
-	if err := unmarshalBuildGradle([]byte(content), gradle); err != nil {
+	switch atcSettings.VersionField {
+	case settings.VersionFieldVersionName:
+		err = unmarshalBuildGradle([]byte(content), gradle)
+	case settings.VersionFieldVersionCode:
+		err = unmarshalVersionCode([]byte(content), gradle)
+	default:
+		if err = unmarshalProjectVersion([]byte(content), gradle); err != nil {
+			err = unmarshalBuildGradle([]byte(content), gradle)
+		}
+	}
+	if err != nil {
 		return "", err
 	}
 	return gradle.Version, nil
 }
 
 func (buildGradleFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
-	return buildGradleFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: "app/build.gradle"})
+	paths := buildGradleFetcher.DefaultPaths
+	if paths == nil {
+		paths = defaultPaths
+	}
+	var err error
+	for _, defaultPath := range paths {
+		var version string
+		if version, err = buildGradleFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: defaultPath}); err == nil {
+			return version, nil
+		}
+	}
+	return "", err
+}
+
+// ProjectVersion returns the project `version = "..."` of a build script,
+// "" if it isn't set to a string there.
+func ProjectVersion(content []byte) string {
+	gradle := &BuildGradle{}
+	if unmarshalProjectVersion(content, gradle) != nil {
+		return ""
+	}
+	return gradle.Version
+}
+
+// AndroidVersions returns the versionName and versionCode of an Android
+// build.gradle, "" for a field that isn't there.
+func AndroidVersions(content []byte) (versionName, versionCode string) {
+	gradle := &BuildGradle{}
+	if unmarshalBuildGradle(content, gradle) == nil {
+		versionName = gradle.Version
+	}
+	gradle = &BuildGradle{}
+	if unmarshalVersionCode(content, gradle) == nil {
+		versionCode = gradle.Version
+	}
+	return versionName, versionCode
 }
@@ -122,7 +122,7 @@ func TestUnmarshalErrorBuildGradle(t *testing.T) {
 
 func TestErrorGetVersionGradle(t *testing.T) {
 	noContentErr := errors.New("can't get content")
-	cp := provider.MockContentProvider{"", noContentErr}
+	cp := provider.MockContentProvider{Content: "", Err: noContentErr}
 	bgf := &Fetcher{}
 	//test error get contents
 	_, err := bgf.GetVersion(&cp, settings.AtcSettings{Path: "gradle"})
@@ -135,3 +135,94 @@ func TestErrorGetVersionGradle(t *testing.T) {
 		t.Errorf("err:%s  !=  noContentErr:%s", err, noContentErr)
 	}
 }
+
+func TestBuildGradleVersionField(t *testing.T) {
+	var tests = []struct {
+		content      string
+		versionField string
+		version      string
+	}{
+		{basicBuildGradle, "", "1.7.1"},
+		{basicBuildGradle, "versionName", "1.7.1"},
+		{basicBuildGradle, "versionCode", "1"},
+		{`version = '2.0.1'
+android {
+    defaultConfig {
+        versionCode 007
+        versionName "1.7.1"
+    }
+}`, "version", "2.0.1"},
+		{`version = '2.0.1'
+android {
+    defaultConfig {
+        versionCode 007
+        versionName "1.7.1"
+    }
+}`, "versionCode", "7"},
+		{`android {
+    productFlavors {
+        free {
+            versionName '3.1-free'
+        }
+    }
+}`, "versionName", "3.1-free"},
+	}
+	for _, test := range tests {
+		cp := provider.MockContentProvider{Content: test.content}
+		vers, err := (&Fetcher{}).GetVersion(&cp, settings.AtcSettings{Path: "build.gradle", VersionField: test.versionField})
+		if err != nil {
+			t.Errorf("Unexpected error %v", err)
+		}
+		if vers != test.version {
+			t.Errorf("version_field %q: expected %q, got %q", test.versionField, test.version, vers)
+		}
+	}
+}
+
+func TestAndroidVersions(t *testing.T) {
+	var tests = []struct {
+		content     string
+		versionName string
+		versionCode string
+	}{
+		{"android {\n    defaultConfig {\n        versionCode 042\n        versionName \"1.7.1\"\n    }\n}", "1.7.1", "42"},
+		{"android {\n    defaultConfig {\n        versionName \"1.7.1\"\n    }\n}", "1.7.1", ""},
+		{"version = '2.0.1'", "", ""},
+	}
+	for _, test := range tests {
+		versionName, versionCode := AndroidVersions([]byte(test.content))
+		if versionName != test.versionName || versionCode != test.versionCode {
+			t.Errorf("expected %q, %q, got %q, %q", test.versionName, test.versionCode, versionName, versionCode)
+		}
+	}
+}
+
+var kotlinBuildGradle = `plugins {
+    id("com.android.application")
+}
+
+android {
+    defaultConfig {
+        applicationId = "io.smartforce.atc"
+        versionCode = 12
+        versionName = "2.0.1"
+    }
+}
+`
+
+func TestKotlinFetcher(t *testing.T) {
+	cp := provider.MockContentProvider{Content: kotlinBuildGradle}
+	vers, err := NewKotlinFetcher().GetVersionUsingDefaultPath(&cp)
+	if err != nil || vers != "2.0.1" {
+		t.Errorf("expected %q, got %q (err %v)", "2.0.1", vers, err)
+	}
+	vers, err = NewKotlinFetcher().GetVersion(&cp, settings.AtcSettings{Path: "app/build.gradle.kts", VersionField: settings.VersionFieldVersionCode})
+	if err != nil || vers != "12" {
+		t.Errorf("expected %q, got %q (err %v)", "12", vers, err)
+	}
+	cp = provider.MockContentProvider{Content: "group = \"io.smartforce\"\nversion = \"0.3.0\"\n"}
+	vers, err = NewKotlinFetcher().GetVersionUsingDefaultPath(&cp)
+	if err != nil || vers != "0.3.0" {
+		t.Errorf("expected %q, got %q (err %v)", "0.3.0", vers, err)
+	}
+}
@@ -0,0 +1,52 @@
+package bundlerlock
+
+import (
+	"regexp"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+)
+
+type BundlerLock struct {
+	Version string
+}
+
+// Fetcher reads the resolved version of the gem AtcSettings.GemName from a
+// Bundler Gemfile.lock spec line like `    myapp (1.2.3)`.
+type Fetcher struct {
+}
+
+var unmarshalBundlerLock = func(content []byte, gemName string, bundlerLockPtr *BundlerLock) error {
+	// the version has to start with a digit, so dependency lines like
+	// `      myapp (= 1.2.3)` are skipped
+	specRegex := regexp.MustCompile(`(?m)^\s+` + regexp.QuoteMeta(gemName) + ` \(([0-9][^)\s]*)\)\s*$`)
+	res := specRegex.FindSubmatch(content)
+	if len(res) != 2 {
+		return fetcher.ErrNoVers
+	}
+	bundlerLockPtr.Version = string(res[1])
+	return nil
+}
+
+func (bundlerLockFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	if settings.GemName == "" {
+		return "", fetcher.ErrNoVers
+	}
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	bundlerLock := &BundlerLock{}
+	if err := unmarshalBundlerLock([]byte(content), settings.GemName, bundlerLock); err != nil {
+		return "", err
+	}
+	return bundlerLock.Version, nil
+}
+
+// GetVersionUsingDefaultPath always fails, the gem to read has to be
+// configured.
+func (bundlerLockFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	return "", fetcher.ErrNoVers
+}
@@ -0,0 +1,62 @@
+package bundlerlock
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+var basicGemfileLock = `PATH
+  remote: .
+  specs:
+    myapp (1.2.3)
+      rails (~> 7.0)
+
+GEM
+  remote: https://rubygems.org/
+  specs:
+    nokogiri (1.13.10-x86_64-linux)
+      racc (~> 1.4)
+    rails (7.0.4)
+      actioncable (= 7.0.4)
+    rails-html-sanitizer (1.4.4)
+    actioncable (7.0.4)
+
+PLATFORMS
+  x86_64-linux
+
+DEPENDENCIES
+  myapp!
+  rails (= 7.0.4)
+
+BUNDLED WITH
+   2.4.3
+`
+
+func TestBundlerLockFetcher(t *testing.T) {
+	var tests = []struct {
+		gemName string
+		version string
+		err     error
+	}{
+		{"myapp", "1.2.3", nil},
+		{"rails", "7.0.4", nil},
+		{"actioncable", "7.0.4", nil},
+		{"nokogiri", "1.13.10-x86_64-linux", nil},
+		{"rails-html", "", fetcher.ErrNoVers},
+		{"racc", "", fetcher.ErrNoVers},
+		{"", "", fetcher.ErrNoVers},
+	}
+	cp := provider.MockContentProvider{Content: basicGemfileLock}
+	for _, test := range tests {
+		vers, err := (&Fetcher{}).GetVersion(&cp, settings.AtcSettings{Path: "Gemfile.lock", GemName: test.gemName})
+		if err != test.err {
+			t.Errorf("gem %q: expected err %v, got %v", test.gemName, test.err, err)
+		}
+		if vers != test.version {
+			t.Errorf("gem %q: expected %q, got %q", test.gemName, test.version, vers)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package cabal
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+)
+
+type Cabal struct {
+	Version string
+}
+
+// Fetcher reads the `version:` field of the package stanza of a Haskell
+// .cabal file.
+type Fetcher struct {
+}
+
+var (
+	versionFieldRegex  = regexp.MustCompile(`(?im)^version:\s*(.+)$`)
+	versionNumberRegex = regexp.MustCompile(`\d+(?:\.\d+)*`)
+)
+
+var unmarshalCabal = func(content []byte, cabalPtr *Cabal) error {
+	res := versionFieldRegex.FindSubmatch(content)
+	if len(res) != 2 {
+		return fetcher.ErrNoVers
+	}
+	// a range like ">= 1.2 && < 2" is compared by its first version
+	version := versionNumberRegex.Find(res[1])
+	if version == nil {
+		return fetcher.ErrNoVers
+	}
+	cabalPtr.Version = string(version)
+	return nil
+}
+
+func (cabalFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	cabal := &Cabal{}
+	if err := unmarshalCabal([]byte(content), cabal); err != nil {
+		return "", err
+	}
+	return cabal.Version, nil
+}
+
+// GetVersionUsingDefaultPath reads the first .cabal file in the repository
+// root, its name is the package name.
+func (cabalFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	treeProvider, ok := ghContentProvider.(provider.TreeProvider)
+	if !ok {
+		return "", provider.ErrNoTreeProvider
+	}
+	files, err := treeProvider.ListFiles()
+	if err != nil {
+		return "", err
+	}
+	for _, file := range files {
+		if !strings.Contains(file, "/") && strings.HasSuffix(file, ".cabal") {
+			return cabalFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: file})
+		}
+	}
+	return "", fetcher.ErrNoVers
+}
@@ -0,0 +1,61 @@
+package cabal
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+)
+
+var basicCabal = `cabal-version:      >= 1.10
+name:               atc
+version:            0.2.1.0
+synopsis:           Automated Tag Creator
+build-type:         Simple
+
+library
+  exposed-modules:  Atc
+  build-depends:    base >= 4.7 && < 5
+`
+
+func TestUnmarshalCabal(t *testing.T) {
+	var tests = []struct {
+		content string
+		version string
+		err     error
+	}{
+		{basicCabal, "0.2.1.0", nil},
+		{"Name: atc\nVersion: 1.2\n", "1.2", nil},
+		{"name: atc\nversion: >= 1.2 && < 2\n", "1.2", nil},
+		{"cabal-version: 2.4\nname: atc\n", "", fetcher.ErrNoVers},
+	}
+	for _, test := range tests {
+		cabal := &Cabal{}
+		err := unmarshalCabal([]byte(test.content), cabal)
+		if err != test.err {
+			t.Errorf("expected err %v, got %v", test.err, err)
+		}
+		if cabal.Version != test.version {
+			t.Errorf("expected %q, got %q", test.version, cabal.Version)
+		}
+	}
+}
+
+func TestCabalFetcherDefaultPath(t *testing.T) {
+	cp := provider.MockFilesContentProvider{
+		"README.md":       "# atc",
+		"test/test.cabal": "version: 9.9",
+		"atc.cabal":       basicCabal,
+	}
+	vers, err := (&Fetcher{}).GetVersionUsingDefaultPath(cp)
+	if err != nil || vers != "0.2.1.0" {
+		t.Errorf("expected %q, got %q, %v", "0.2.1.0", vers, err)
+	}
+
+	if _, err := (&Fetcher{}).GetVersionUsingDefaultPath(provider.MockFilesContentProvider{"README.md": ""}); err != fetcher.ErrNoVers {
+		t.Errorf("expected err %v, got %v", fetcher.ErrNoVers, err)
+	}
+	if _, err := (&Fetcher{}).GetVersionUsingDefaultPath(&provider.MockContentProvider{}); err != provider.ErrNoTreeProvider {
+		t.Errorf("expected err %v, got %v", provider.ErrNoTreeProvider, err)
+	}
+}
@@ -0,0 +1,102 @@
+package cargotoml
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+)
+
+type CargoToml struct {
+	// Version is the `[package] version`, empty if it's inherited.
+	Version string
+	// WorkspaceVersion is `[workspace.package] version` of a workspace root.
+	WorkspaceVersion string
+	// InheritsVersion is set by `version.workspace = true`.
+	InheritsVersion bool
+}
+
+// Fetcher reads the `[package] version` of a Rust Cargo.toml. A version
+// inherited with `version.workspace = true` is read from the
+// `[workspace.package]` of the closest Cargo.toml above it with one.
+type Fetcher struct {
+}
+
+var (
+	tableRegex           = regexp.MustCompile(`^\s*\[([^\[\]]+)\]`)
+	versionRegex         = regexp.MustCompile(`^\s*version\s*=\s*"([^"]+)"`)
+	workspaceDottedRegex = regexp.MustCompile(`^\s*version\.workspace\s*=\s*true\b`)
+	workspaceInlineRegex = regexp.MustCompile(`^\s*version\s*=\s*\{\s*workspace\s*=\s*true\s*\}`)
+)
+
+var unmarshalCargoToml = func(content []byte, cargoTomlPtr *CargoToml) error {
+	table := ""
+	for _, line := range strings.Split(string(content), "\n") {
+		if res := tableRegex.FindStringSubmatch(line); res != nil {
+			table = strings.TrimSpace(res[1])
+			continue
+		}
+		switch table {
+		case "package":
+			if res := versionRegex.FindStringSubmatch(line); res != nil {
+				cargoTomlPtr.Version = res[1]
+			} else if workspaceDottedRegex.MatchString(line) || workspaceInlineRegex.MatchString(line) {
+				cargoTomlPtr.InheritsVersion = true
+			}
+		case "workspace.package":
+			if res := versionRegex.FindStringSubmatch(line); res != nil {
+				cargoTomlPtr.WorkspaceVersion = res[1]
+			}
+		}
+	}
+	return nil
+}
+
+func readCargoToml(ghContentProvider provider.ContentProvider, manifestPath string) (*CargoToml, error) {
+	content, err := ghContentProvider.GetContents(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	cargo := &CargoToml{}
+	if err := unmarshalCargoToml([]byte(content), cargo); err != nil {
+		return nil, err
+	}
+	return cargo, nil
+}
+
+func (cargoTomlFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	cargo, err := readCargoToml(ghContentProvider, settings.Path)
+	if err != nil {
+		return "", err
+	}
+	if cargo.Version != "" {
+		return cargo.Version, nil
+	}
+	if !cargo.InheritsVersion {
+		return "", fetcher.ErrNoVers
+	}
+	// the workspace root can be the same manifest
+	if cargo.WorkspaceVersion != "" {
+		return cargo.WorkspaceVersion, nil
+	}
+	dir := path.Dir(settings.Path)
+	for dir != "." && dir != "/" {
+		dir = path.Dir(dir)
+		root, err := readCargoToml(ghContentProvider, path.Join(dir, "Cargo.toml"))
+		if err != nil {
+			continue // there is no manifest in every directory
+		}
+		if root.WorkspaceVersion != "" {
+			return root.WorkspaceVersion, nil
+		}
+	}
+	return "", fetcher.ErrNoVers
+}
+
+func (cargoTomlFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	return cargoTomlFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: "Cargo.toml"})
+}
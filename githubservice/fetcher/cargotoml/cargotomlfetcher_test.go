@@ -0,0 +1,64 @@
+package cargotoml
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+var basicCargoToml = `[package]
+name = "atc-example"
+version = "0.8.2"
+edition = "2021"
+
+[dependencies]
+serde = { version = "1.0", features = ["derive"] }
+tokio = "1"
+`
+
+var workspaceCargoToml = `[workspace]
+members = ["crates/*"]
+
+[workspace.package]
+version = "2.1.0"
+edition = "2021"
+
+[workspace.dependencies]
+serde = { version = "1.0" }
+`
+
+func TestCargoTomlFetcher(t *testing.T) {
+	var tests = []struct {
+		files   provider.MockFilesContentProvider
+		path    string
+		version string
+		err     error
+	}{
+		{provider.MockFilesContentProvider{"Cargo.toml": basicCargoToml}, "Cargo.toml", "0.8.2", nil},
+		{provider.MockFilesContentProvider{
+			"Cargo.toml":             workspaceCargoToml,
+			"crates/core/Cargo.toml": "[package]\nname = \"core\"\nversion.workspace = true\n",
+		}, "crates/core/Cargo.toml", "2.1.0", nil},
+		{provider.MockFilesContentProvider{
+			"Cargo.toml":            workspaceCargoToml,
+			"crates/cli/Cargo.toml": "[package]\nname = \"cli\"\nversion = { workspace = true }\n",
+		}, "crates/cli/Cargo.toml", "2.1.0", nil},
+		{provider.MockFilesContentProvider{
+			"Cargo.toml": workspaceCargoToml + "\n[package]\nname = \"root\"\nversion.workspace = true\n",
+		}, "Cargo.toml", "2.1.0", nil},
+		{provider.MockFilesContentProvider{"crates/core/Cargo.toml": "[package]\nversion.workspace = true\n"}, "crates/core/Cargo.toml", "", fetcher.ErrNoVers},
+		{provider.MockFilesContentProvider{"Cargo.toml": workspaceCargoToml}, "Cargo.toml", "", fetcher.ErrNoVers},
+		{provider.MockFilesContentProvider{"Cargo.toml": "[dependencies]\nversion = \"1.0\"\n"}, "Cargo.toml", "", fetcher.ErrNoVers},
+	}
+	for _, test := range tests {
+		vers, err := (&Fetcher{}).GetVersion(test.files, settings.AtcSettings{Path: test.path})
+		if err != test.err {
+			t.Errorf("%s: expected err %v, got %v", test.path, test.err, err)
+		}
+		if vers != test.version {
+			t.Errorf("%s: expected %q, got %q", test.path, test.version, vers)
+		}
+	}
+}
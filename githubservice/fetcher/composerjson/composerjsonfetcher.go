@@ -0,0 +1,42 @@
+package composerjson
+
+import (
+	"encoding/json"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+type ComposerJson struct {
+	Version string `json:"version"`
+}
+
+// Fetcher reads the "version" field of composer.json. Composer recommends
+// leaving it out for packages published from VCS, such files have no version.
+type Fetcher struct {
+}
+
+var unmarshalComposerJson = func(content []byte, composerJsonPtr *ComposerJson) error {
+	return json.Unmarshal(content, composerJsonPtr)
+}
+
+func (composerJsonFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	composerJson := &ComposerJson{}
+	if err := unmarshalComposerJson([]byte(content), composerJson); err != nil {
+		return "", err
+	}
+	if composerJson.Version == "" {
+		return "", fetcher.ErrNoVers
+	}
+	return composerJson.Version, nil
+}
+
+func (composerJsonFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	return composerJsonFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: "composer.json"})
+}
@@ -0,0 +1,41 @@
+package composerjson
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+func TestComposerJsonFetcher(t *testing.T) {
+	var tests = []struct {
+		content string
+		version string
+		err     error
+	}{
+		{`{"name": "acme/atc", "version": "1.2.3", "require": {"php": ">=8.1"}}`, "1.2.3", nil},
+		{`{"name": "acme/atc", "require": {"php": ">=8.1"}}`, "", fetcher.ErrNoVers},
+	}
+	for _, test := range tests {
+		cp := provider.MockContentProvider{Content: test.content}
+		vers, err := (&Fetcher{}).GetVersion(&cp, settings.AtcSettings{Path: "composer.json"})
+		if err != test.err {
+			t.Errorf("content %s: expected err %v, got %v", test.content, test.err, err)
+		}
+		if vers != test.version {
+			t.Errorf("content %s: expected %q, got %q", test.content, test.version, vers)
+		}
+	}
+}
+
+func TestComposerJsonFetcherErrors(t *testing.T) {
+	cp := provider.MockContentProvider{Content: `{"version": `}
+	if _, err := (&Fetcher{}).GetVersionUsingDefaultPath(&cp); err == nil {
+		t.Errorf("expected unmarshal error")
+	}
+	cp = provider.MockContentProvider{Err: provider.ErrGeneral}
+	if _, err := (&Fetcher{}).GetVersionUsingDefaultPath(&cp); err != provider.ErrGeneral {
+		t.Errorf("expected %v, got %v", provider.ErrGeneral, err)
+	}
+}
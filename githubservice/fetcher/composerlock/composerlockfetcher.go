@@ -0,0 +1,58 @@
+package composerlock
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+)
+
+type ComposerPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type ComposerLock struct {
+	Packages    []ComposerPackage `json:"packages"`
+	PackagesDev []ComposerPackage `json:"packages-dev"`
+}
+
+// Fetcher reads the installed version of the package AtcSettings.PackageName
+// from a Composer composer.lock.
+type Fetcher struct {
+}
+
+var unmarshalComposerLock = func(content []byte, composerLockPtr *ComposerLock) error {
+	return json.Unmarshal(content, composerLockPtr)
+}
+
+func (composerLockFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	if settings.PackageName == "" {
+		return "", fetcher.ErrNoVers
+	}
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	composerLock := &ComposerLock{}
+	if err := unmarshalComposerLock([]byte(content), composerLock); err != nil {
+		return "", err
+	}
+	for _, pkg := range append(composerLock.Packages, composerLock.PackagesDev...) {
+		// Composer package names are case-insensitive
+		if strings.EqualFold(pkg.Name, settings.PackageName) && pkg.Version != "" {
+			// tags of Composer packages usually have a "v" prefix
+			return strings.TrimPrefix(pkg.Version, "v"), nil
+		}
+	}
+	return "", fetcher.ErrNoVers
+}
+
+// GetVersionUsingDefaultPath always fails, the package to read has to be
+// configured.
+func (composerLockFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	return "", fetcher.ErrNoVers
+}
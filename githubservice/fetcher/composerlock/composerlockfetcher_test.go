@@ -0,0 +1,44 @@
+package composerlock
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+var basicComposerLock = `{
+    "content-hash": "e0ba4ea4a46f6e1b2e9e5b3d2b7e1a9f",
+    "packages": [
+        {"name": "acme/wp-core", "version": "v2.4.1", "type": "library"},
+        {"name": "monolog/monolog", "version": "3.3.1", "type": "library"}
+    ],
+    "packages-dev": [
+        {"name": "phpunit/phpunit", "version": "10.0.19", "type": "library"}
+    ]
+}`
+
+func TestComposerLockFetcher(t *testing.T) {
+	var tests = []struct {
+		packageName string
+		version     string
+		err         error
+	}{
+		{"acme/wp-core", "2.4.1", nil},
+		{"Monolog/Monolog", "3.3.1", nil},
+		{"phpunit/phpunit", "10.0.19", nil},
+		{"acme/missing", "", fetcher.ErrNoVers},
+		{"", "", fetcher.ErrNoVers},
+	}
+	cp := provider.MockContentProvider{Content: basicComposerLock}
+	for _, test := range tests {
+		vers, err := (&Fetcher{}).GetVersion(&cp, settings.AtcSettings{Path: "composer.lock", PackageName: test.packageName})
+		if err != test.err {
+			t.Errorf("package %q: expected err %v, got %v", test.packageName, test.err, err)
+		}
+		if vers != test.version {
+			t.Errorf("package %q: expected %q, got %q", test.packageName, test.version, vers)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package condameta
+
+import (
+	"regexp"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+)
+
+type CondaMeta struct {
+	Version string
+}
+
+// Fetcher reads the version of a conda recipe from meta.yaml, either from a
+// `{% set version = "..." %}` Jinja statement or a literal `version:` field.
+type Fetcher struct {
+}
+
+// defaultPaths are tried in order by GetVersionUsingDefaultPath.
+var defaultPaths = []string{"meta.yaml", "conda/meta.yaml", "recipe/meta.yaml"}
+
+var (
+	setVersionRegex     = regexp.MustCompile(`\{%-?\s*set\s+version\s*=\s*["']([^"']+)["']\s*-?%\}`)
+	literalVersionRegex = regexp.MustCompile(`(?m)^\s+version:\s*["']?([^"'\s{]+)["']?\s*$`)
+)
+
+var unmarshalCondaMeta = func(content []byte, condaMetaPtr *CondaMeta) error {
+	if res := setVersionRegex.FindSubmatch(content); len(res) == 2 {
+		condaMetaPtr.Version = string(res[1])
+		return nil
+	}
+	if res := literalVersionRegex.FindSubmatch(content); len(res) == 2 {
+		condaMetaPtr.Version = string(res[1])
+		return nil
+	}
+	return fetcher.ErrNoVers
+}
+
+func (condaMetaFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	condaMeta := &CondaMeta{}
+	if err := unmarshalCondaMeta([]byte(content), condaMeta); err != nil {
+		return "", err
+	}
+	return condaMeta.Version, nil
+}
+
+func (condaMetaFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	var err error
+	for _, defaultPath := range defaultPaths {
+		var version string
+		if version, err = condaMetaFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: defaultPath}); err == nil {
+			return version, nil
+		}
+	}
+	return "", err
+}
@@ -0,0 +1,59 @@
+package condameta
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+var basicMetaYaml = `{% set name = "atc" %}
+{% set version = "1.2.3" %}
+
+package:
+  name: {{ name|lower }}
+  version: {{ version }}
+
+source:
+  url: https://pypi.io/packages/source/a/atc/atc-{{ version }}.tar.gz
+`
+
+func TestUnmarshalCondaMeta(t *testing.T) {
+	var tests = []struct {
+		content string
+		version string
+		err     error
+	}{
+		{basicMetaYaml, "1.2.3", nil},
+		{`{%- set version = '2.0.0rc1' -%}`, "2.0.0rc1", nil},
+		{"package:\n  name: atc\n  version: \"3.1\"\n", "3.1", nil},
+		{"package:\n  name: atc\n  version: 3.2\n", "3.2", nil},
+		{"package:\n  name: atc\n  version: {{ version }}\n", "", fetcher.ErrNoVers},
+		{``, "", fetcher.ErrNoVers},
+	}
+	for _, test := range tests {
+		condaMeta := &CondaMeta{}
+		err := unmarshalCondaMeta([]byte(test.content), condaMeta)
+		if err != test.err {
+			t.Errorf("content %q: expected err %v, got %v", test.content, test.err, err)
+		}
+		if condaMeta.Version != test.version {
+			t.Errorf("content %q: expected %q, got %q", test.content, test.version, condaMeta.Version)
+		}
+	}
+}
+
+func TestCondaMetaFetcher(t *testing.T) {
+	cp := provider.MockContentProvider{Content: basicMetaYaml}
+	vers, err := (&Fetcher{}).GetVersion(&cp, settings.AtcSettings{Path: "conda/meta.yaml"})
+	if err != nil || vers != "1.2.3" {
+		t.Errorf("expected %q, got %q (err %v)", "1.2.3", vers, err)
+	}
+
+	files := provider.MockFilesContentProvider{"recipe/meta.yaml": basicMetaYaml}
+	vers, err = (&Fetcher{}).GetVersionUsingDefaultPath(files)
+	if err != nil || vers != "1.2.3" {
+		t.Errorf("expected %q, got %q (err %v)", "1.2.3", vers, err)
+	}
+}
@@ -2,6 +2,7 @@ package customregex
 
 import (
 	"errors"
+	"fmt"
 	"regexp"
 
 	"github.com/smartforce-io/atc/githubservice/provider"
@@ -30,22 +31,50 @@ var unmarshalCustomRegexConfig = func(content []byte, regexStr string, customReg
 	if len(res) == 1 {
 		return fetcher.ErrNoGroupInConf
 	}
-	customRegexConfigPtr.Version = res[1]
+	if i := regex.SubexpIndex("version"); i > 0 {
+		customRegexConfigPtr.Version = res[i]
+	} else {
+		customRegexConfigPtr.Version = res[1]
+	}
 	return nil
 }
 
-func (customRegexFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
-	content, err := ghContentProvider.GetContents(settings.Path)
-	if err != nil {
-		return "", err
-	}
+func getVersion(content, regexStr string) (string, error) {
 	customRegexConfig := &Config{}
-	if err := unmarshalCustomRegexConfig([]byte(content), settings.RegexStr, customRegexConfig); err != nil {
+	if err := unmarshalCustomRegexConfig([]byte(content), regexStr, customRegexConfig); err != nil {
 		return "", err
 	}
 	return customRegexConfig.Version, nil
 }
 
+// GetVersion reads the version from settings.Path or, when it's empty, from
+// the first of settings.CustomFiles that exists and has one.
+func (customRegexFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	if settings.Path != "" || len(settings.CustomFiles) == 0 {
+		content, err := ghContentProvider.GetContents(settings.Path)
+		if err != nil {
+			return "", err
+		}
+		return getVersion(content, settings.RegexStr)
+	}
+	var err error
+	for _, file := range settings.CustomFiles {
+		var content, version string
+		if content, err = ghContentProvider.GetContents(file.Path); err != nil {
+			err = fmt.Errorf("%s: %w", file.Path, err)
+			continue
+		}
+		if version, err = getVersion(content, file.RegexStr); err == nil {
+			return version, nil
+		}
+		err = fmt.Errorf("%s: %w", file.Path, err)
+		if !errors.Is(err, fetcher.ErrNoVers) {
+			return "", err
+		}
+	}
+	return "", err
+}
+
 func (customRegexFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
 	return "", errors.New("CustomRegexConfig doesn't have a default path")
 }
@@ -46,6 +46,7 @@ func TestUnmarshalUserConfig(t *testing.T) {
 		{`vers: 1
 		versionName "1.3"`, "vers: (.+)", "1.3"},
 		{`vers: "1.4-release"`, "vers: (.+)", "1.4-release"},
+		{`app 1.5 build 7`, `(\w+) (?P<version>[\d.]+)`, "1.5"},
 	}
 	for _, test := range tests {
 		customRegexConf := &Config{}
@@ -93,3 +94,26 @@ func TestErrorGetVersionUserConfig(t *testing.T) {
 		t.Errorf("err:%s  !=  defaultPathErr:%s", err, defaultPathErr)
 	}
 }
+
+func TestCustomFiles(t *testing.T) {
+	customFiles := []settings.CustomFile{
+		{Path: "release.ini", RegexStr: `(release) = (?P<version>\S+)`},
+		{Path: "VERSION.txt", RegexStr: `v(\S+)`},
+	}
+	var tests = []struct {
+		files   provider.MockFilesContentProvider
+		version string
+		err     error
+	}{
+		{provider.MockFilesContentProvider{"release.ini": "release = 2.1.0", "VERSION.txt": "v1.0.0"}, "2.1.0", nil},
+		{provider.MockFilesContentProvider{"VERSION.txt": "v1.0.0"}, "1.0.0", nil},
+		{provider.MockFilesContentProvider{"release.ini": "[app]", "VERSION.txt": "v1.0.0"}, "1.0.0", nil},
+		{provider.MockFilesContentProvider{"release.ini": "[app]"}, "", provider.ErrHttpStatusCode},
+	}
+	for _, test := range tests {
+		version, err := (&Fetcher{}).GetVersion(test.files, settings.AtcSettings{CustomFiles: customFiles})
+		if version != test.version || !errors.Is(err, test.err) {
+			t.Errorf("files %v: expected %q, %v, got %q, %v", test.files, test.version, test.err, version, err)
+		}
+	}
+}
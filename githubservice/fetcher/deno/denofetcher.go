@@ -0,0 +1,52 @@
+package deno
+
+import (
+	"encoding/json"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+type DenoJson struct {
+	Version   string          `json:"version"`
+	Workspace json.RawMessage `json:"workspace"`
+}
+
+type denoWorkspace struct {
+	Version string `json:"version"`
+}
+
+// Fetcher reads the version from deno.json. Besides the top-level "version"
+// of a package, Deno 2 workspace files may keep it under "workspace".
+type Fetcher struct {
+}
+
+var unmarshalDenoJson = func(content []byte, denoJsonPtr *DenoJson) error {
+	return json.Unmarshal(content, denoJsonPtr)
+}
+
+func (denoFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	denoJson := &DenoJson{}
+	if err := unmarshalDenoJson([]byte(content), denoJson); err != nil {
+		return "", err
+	}
+	if denoJson.Version != "" {
+		return denoJson.Version, nil
+	}
+	// "workspace" is usually a list of members, only an object can hold a version
+	workspace := &denoWorkspace{}
+	if err := json.Unmarshal(denoJson.Workspace, workspace); err != nil || workspace.Version == "" {
+		return "", fetcher.ErrNoVers
+	}
+	return workspace.Version, nil
+}
+
+func (denoFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	return denoFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: "deno.json"})
+}
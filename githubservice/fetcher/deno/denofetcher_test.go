@@ -0,0 +1,44 @@
+package deno
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+func TestDenoFetcher(t *testing.T) {
+	var tests = []struct {
+		content string
+		version string
+		err     error
+	}{
+		{`{"name": "@atc/lib", "version": "1.2.3", "exports": "./mod.ts"}`, "1.2.3", nil},
+		{`{"workspace": {"members": ["./add"], "version": "2.0.0"}}`, "2.0.0", nil},
+		{`{"version": "1.0.0", "workspace": {"version": "2.0.0"}}`, "1.0.0", nil},
+		{`{"workspace": ["./add", "./subtract"]}`, "", fetcher.ErrNoVers},
+		{`{"tasks": {"dev": "deno run main.ts"}}`, "", fetcher.ErrNoVers},
+	}
+	for _, test := range tests {
+		cp := provider.MockContentProvider{Content: test.content}
+		vers, err := (&Fetcher{}).GetVersion(&cp, settings.AtcSettings{Path: "deno.json"})
+		if err != test.err {
+			t.Errorf("content %s: expected err %v, got %v", test.content, test.err, err)
+		}
+		if vers != test.version {
+			t.Errorf("content %s: expected %q, got %q", test.content, test.version, vers)
+		}
+	}
+}
+
+func TestDenoFetcherErrors(t *testing.T) {
+	cp := provider.MockContentProvider{Content: `{"version": `}
+	if _, err := (&Fetcher{}).GetVersionUsingDefaultPath(&cp); err == nil {
+		t.Errorf("expected unmarshal error")
+	}
+	cp = provider.MockContentProvider{Err: provider.ErrGeneral}
+	if _, err := (&Fetcher{}).GetVersionUsingDefaultPath(&cp); err != provider.ErrGeneral {
+		t.Errorf("expected %v, got %v", provider.ErrGeneral, err)
+	}
+}
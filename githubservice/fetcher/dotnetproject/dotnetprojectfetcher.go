@@ -0,0 +1,138 @@
+package dotnetproject
+
+import (
+	"encoding/xml"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+)
+
+// extensions are the MSBuild project files of C#, F# and Visual Basic.
+var extensions = []string{".csproj", ".fsproj", ".vbproj"}
+
+// BuildPropsPath is the MSBuild file with the properties shared by the
+// projects of its directory and below.
+const BuildPropsPath = "Directory.Build.props"
+
+type PropertyGroup struct {
+	Version       string `xml:"Version"`
+	VersionPrefix string `xml:"VersionPrefix"`
+	VersionSuffix string `xml:"VersionSuffix"`
+}
+
+type Project struct {
+	PropertyGroups []PropertyGroup `xml:"PropertyGroup"`
+}
+
+// Fetcher reads the `<Version>` property of a .NET SDK-style project file,
+// or `<VersionPrefix>` with the optional `<VersionSuffix>` when there is
+// no `<Version>`. A project without them inherits the version of the
+// nearest Directory.Build.props, like MSBuild does.
+type Fetcher struct {
+	// DefaultPath is read by GetVersionUsingDefaultPath instead of the
+	// first project file in the root.
+	DefaultPath string
+}
+
+// NewBuildPropsFetcher returns the Fetcher of Directory.Build.props in the
+// repository root.
+func NewBuildPropsFetcher() *Fetcher {
+	return &Fetcher{DefaultPath: BuildPropsPath}
+}
+
+var unmarshalProject = func(content []byte, projectPtr *Project) error {
+	return xml.Unmarshal(content, projectPtr)
+}
+
+func projectVersion(project *Project) string {
+	var prefix, suffix string
+	for _, group := range project.PropertyGroups {
+		if version := strings.TrimSpace(group.Version); version != "" {
+			return version
+		}
+		if prefix == "" {
+			prefix = strings.TrimSpace(group.VersionPrefix)
+		}
+		if suffix == "" {
+			suffix = strings.TrimSpace(group.VersionSuffix)
+		}
+	}
+	if prefix != "" && suffix != "" {
+		return prefix + "-" + suffix
+	}
+	return prefix
+}
+
+func (projectFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	version, err := fileVersion(ghContentProvider, settings.Path)
+	if err != fetcher.ErrNoVers || path.Base(settings.Path) == BuildPropsPath {
+		return version, err
+	}
+	return buildPropsVersion(ghContentProvider, path.Dir(settings.Path))
+}
+
+func fileVersion(ghContentProvider provider.ContentProvider, filePath string) (string, error) {
+	content, err := ghContentProvider.GetContents(filePath)
+	if err != nil {
+		return "", err
+	}
+	return contentVersion(content)
+}
+
+func contentVersion(content string) (string, error) {
+	project := &Project{}
+	if err := unmarshalProject([]byte(content), project); err != nil {
+		return "", err
+	}
+	version := projectVersion(project)
+	if version == "" {
+		return "", fetcher.ErrNoVers
+	}
+	return version, nil
+}
+
+// buildPropsVersion returns the version of the Directory.Build.props in dir
+// or the nearest parent directory that has one.
+func buildPropsVersion(ghContentProvider provider.ContentProvider, dir string) (string, error) {
+	for {
+		if content, err := ghContentProvider.GetContents(path.Join(dir, BuildPropsPath)); err == nil {
+			return contentVersion(content)
+		}
+		if dir == "." || dir == "/" {
+			return "", fetcher.ErrNoVers
+		}
+		dir = path.Dir(dir)
+	}
+}
+
+// GetVersionUsingDefaultPath reads DefaultPath, or the first project file in
+// the repository root.
+func (projectFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	if projectFetcher.DefaultPath != "" {
+		return projectFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: projectFetcher.DefaultPath})
+	}
+	treeProvider, ok := ghContentProvider.(provider.TreeProvider)
+	if !ok {
+		return "", provider.ErrNoTreeProvider
+	}
+	files, err := treeProvider.ListFiles()
+	if err != nil {
+		return "", err
+	}
+	for _, file := range files {
+		if strings.Contains(file, "/") {
+			continue
+		}
+		for _, ext := range extensions {
+			if filepath.Ext(file) == ext {
+				return projectFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: file})
+			}
+		}
+	}
+	return "", fetcher.ErrNoVers
+}
@@ -0,0 +1,97 @@
+package dotnetproject
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+var basicCsproj = `<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <TargetFramework>net8.0</TargetFramework>
+  </PropertyGroup>
+  <PropertyGroup>
+    <Version>3.1.4</Version>
+  </PropertyGroup>
+  <ItemGroup>
+    <PackageReference Include="Newtonsoft.Json" Version="13.0.3" />
+  </ItemGroup>
+</Project>
+`
+
+func TestDotnetProjectFetcher(t *testing.T) {
+	var tests = []struct {
+		content string
+		version string
+		err     error
+	}{
+		{basicCsproj, "3.1.4", nil},
+		{"<Project><PropertyGroup><VersionPrefix>2.0.0</VersionPrefix><VersionSuffix>beta1</VersionSuffix></PropertyGroup></Project>", "2.0.0-beta1", nil},
+		{"<Project><PropertyGroup><VersionPrefix>2.0.0</VersionPrefix></PropertyGroup></Project>", "2.0.0", nil},
+		{"<Project><ItemGroup><PackageReference Include=\"x\" Version=\"1.0\" /></ItemGroup></Project>", "", fetcher.ErrNoVers},
+	}
+	for _, test := range tests {
+		cp := provider.MockContentProvider{Content: test.content}
+		vers, err := (&Fetcher{}).GetVersion(&cp, settings.AtcSettings{Path: "Atc.fsproj"})
+		if err != test.err {
+			t.Errorf("expected err %v, got %v", test.err, err)
+		}
+		if vers != test.version {
+			t.Errorf("expected %q, got %q", test.version, vers)
+		}
+	}
+}
+
+func TestDotnetProjectFetcherDefaultPath(t *testing.T) {
+	cp := provider.MockFilesContentProvider{
+		"README.md":              "# atc",
+		"tests/Atc.Tests.csproj": "<Project><PropertyGroup><Version>9.9.9</Version></PropertyGroup></Project>",
+		"Atc.vbproj":             basicCsproj,
+	}
+	vers, err := (&Fetcher{}).GetVersionUsingDefaultPath(cp)
+	if err != nil || vers != "3.1.4" {
+		t.Errorf("expected %q, got %q, %v", "3.1.4", vers, err)
+	}
+
+	if _, err := (&Fetcher{}).GetVersionUsingDefaultPath(provider.MockFilesContentProvider{"Atc.sln": ""}); err != fetcher.ErrNoVers {
+		t.Errorf("expected err %v, got %v", fetcher.ErrNoVers, err)
+	}
+}
+
+func TestDirectoryBuildProps(t *testing.T) {
+	props := "<Project><PropertyGroup><VersionPrefix>4.2.0</VersionPrefix></PropertyGroup></Project>"
+	cp := provider.MockFilesContentProvider{
+		"Directory.Build.props":         props,
+		"src/Atc/Atc.csproj":            "<Project Sdk=\"Microsoft.NET.Sdk\"><PropertyGroup><TargetFramework>net8.0</TargetFramework></PropertyGroup></Project>",
+		"src/Directory.Build.props":     "<Project><PropertyGroup><Version>4.3.0</Version></PropertyGroup></Project>",
+		"tools/Tool/Tool.csproj":        "<Project><PropertyGroup><OutputType>Exe</OutputType></PropertyGroup></Project>",
+		"samples/Sample/Sample.fsproj":  "<Project />",
+		"samples/Directory.Build.props": "<Project><PropertyGroup><Authors>atc</Authors></PropertyGroup></Project>",
+	}
+	var tests = []struct {
+		path    string
+		version string
+		err     error
+	}{
+		{"Directory.Build.props", "4.2.0", nil},
+		{"src/Atc/Atc.csproj", "4.3.0", nil},
+		{"tools/Tool/Tool.csproj", "4.2.0", nil},
+		{"samples/Sample/Sample.fsproj", "", fetcher.ErrNoVers},
+	}
+	for _, test := range tests {
+		vers, err := (&Fetcher{}).GetVersion(cp, settings.AtcSettings{Path: test.path})
+		if err != test.err || vers != test.version {
+			t.Errorf("%s: expected %q, %v, got %q, %v", test.path, test.version, test.err, vers, err)
+		}
+	}
+
+	vers, err := NewBuildPropsFetcher().GetVersionUsingDefaultPath(cp)
+	if err != nil || vers != "4.2.0" {
+		t.Errorf("expected %q, got %q, %v", "4.2.0", vers, err)
+	}
+	if _, err := NewBuildPropsFetcher().GetVersionUsingDefaultPath(provider.MockFilesContentProvider{}); err == nil {
+		t.Errorf("expected error without Directory.Build.props")
+	}
+}
@@ -0,0 +1,54 @@
+package duneproject
+
+import (
+	"regexp"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+)
+
+// readFromVCS is the dune-project version taken from the git tags.
+const readFromVCS = ":read_from_vcs"
+
+type DuneProject struct {
+	Version string
+}
+
+// Fetcher reads the version from the `(version 1.2.3)` stanza of an OCaml
+// dune-project.
+type Fetcher struct {
+}
+
+// the stanza has to start the line so commented out ones are skipped
+var versionStanzaRegex = regexp.MustCompile(`(?m)^\s*\(version\s+([^\s)]+)\)`)
+
+var unmarshalDuneProject = func(content []byte, duneProjectPtr *DuneProject) error {
+	res := versionStanzaRegex.FindSubmatch(content)
+	if len(res) != 2 {
+		return fetcher.ErrNoVers
+	}
+	duneProjectPtr.Version = string(res[1])
+	return nil
+}
+
+func (duneProjectFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	duneProject := &DuneProject{}
+	if err := unmarshalDuneProject([]byte(content), duneProject); err != nil {
+		return "", err
+	}
+	// the version comes from the tags, there's nothing to tag from the file
+	if duneProject.Version == readFromVCS {
+		return "", fetcher.ErrVersionUnchanged
+	}
+	return duneProject.Version, nil
+}
+
+func (duneProjectFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	return duneProjectFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: "dune-project"})
+}
@@ -0,0 +1,31 @@
+package duneproject
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+)
+
+func TestDuneProjectFetcher(t *testing.T) {
+	var tests = []struct {
+		content string
+		version string
+		err     error
+	}{
+		{"(lang dune 3.0)\n(name atc)\n(version 1.2.3)\n(generate_opam_files true)", "1.2.3", nil},
+		{"(lang dune 3.0)\n(version v0.16.0~preview)", "v0.16.0~preview", nil},
+		{"(lang dune 3.0)\n; (version 0.1)\n(name atc)", "", fetcher.ErrNoVers},
+		{"(lang dune 3.0)\n(version :read_from_vcs)", "", fetcher.ErrVersionUnchanged},
+	}
+	for _, test := range tests {
+		cp := provider.MockContentProvider{Content: test.content}
+		vers, err := (&Fetcher{}).GetVersionUsingDefaultPath(&cp)
+		if err != test.err {
+			t.Errorf("expected err %v, got %v", test.err, err)
+		}
+		if vers != test.version {
+			t.Errorf("expected %q, got %q", test.version, vers)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package gemspec
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+)
+
+type Gemspec struct {
+	Version string
+	// Constant is the constant the version is set to, e.g. "Atc::VERSION".
+	Constant string
+}
+
+// Fetcher reads the `spec.version = "1.2.3"` of a Ruby .gemspec. A version
+// set to a constant, e.g. `Atc::VERSION`, is read from the lib/**/version.rb
+// defining it.
+type Fetcher struct {
+	// VersionRb reads the `VERSION = "1.2.3"` constant of a version.rb
+	// instead of a .gemspec.
+	VersionRb bool
+}
+
+func NewVersionRbFetcher() *Fetcher {
+	return &Fetcher{VersionRb: true}
+}
+
+var (
+	specVersionRegex     = regexp.MustCompile(`(?m)^\s*\w+\.version\s*=\s*["']([^"']+)["']`)
+	specConstantRegex    = regexp.MustCompile(`(?m)^\s*\w+\.version\s*=\s*((?:[A-Z]\w*::)*VERSION)\b`)
+	versionConstantRegex = regexp.MustCompile(`(?m)^\s*VERSION\s*=\s*["']([^"']+)["']`)
+	wordBoundaryRegex    = regexp.MustCompile(`([a-z\d])([A-Z])|([A-Z]+)([A-Z][a-z])`)
+)
+
+var unmarshalGemspec = func(content []byte, gemspecPtr *Gemspec) error {
+	if res := specVersionRegex.FindSubmatch(content); len(res) == 2 {
+		gemspecPtr.Version = string(res[1])
+		return nil
+	}
+	if res := specConstantRegex.FindSubmatch(content); len(res) == 2 {
+		gemspecPtr.Constant = string(res[1])
+		return nil
+	}
+	return fetcher.ErrNoVers
+}
+
+// versionRbVersion returns the VERSION constant of a version.rb.
+func versionRbVersion(content string) (string, error) {
+	res := versionConstantRegex.FindStringSubmatch(content)
+	if len(res) != 2 {
+		return "", fetcher.ErrNoVers
+	}
+	return res[1], nil
+}
+
+func (gemspecFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	if gemspecFetcher.VersionRb {
+		return versionRbVersion(content)
+	}
+	gemspec := &Gemspec{}
+	if err := unmarshalGemspec([]byte(content), gemspec); err != nil {
+		return "", err
+	}
+	if gemspec.Version != "" {
+		return gemspec.Version, nil
+	}
+	return constantVersion(ghContentProvider, path.Dir(settings.Path), gemspec.Constant)
+}
+
+// constantVersion reads constant from the version.rb of its module next to
+// the gemspec in dir, e.g. lib/my_gem/version.rb for MyGem::VERSION, or
+// else from the first lib/**/version.rb defining a version.
+func constantVersion(ghContentProvider provider.ContentProvider, dir, constant string) (string, error) {
+	modules := strings.Split(constant, "::")
+	segments := []string{dir, "lib"}
+	for _, module := range modules[:len(modules)-1] {
+		segments = append(segments, snakeCase(module))
+	}
+	segments = append(segments, "version.rb")
+	if content, err := ghContentProvider.GetContents(path.Join(segments...)); err == nil {
+		return versionRbVersion(content)
+	}
+
+	treeProvider, ok := ghContentProvider.(provider.TreeProvider)
+	if !ok {
+		return "", fetcher.ErrNoVers
+	}
+	files, err := treeProvider.ListFiles()
+	if err != nil {
+		return "", err
+	}
+	libDir := path.Join(dir, "lib") + "/"
+	for _, file := range files {
+		if !strings.HasPrefix(file, libDir) || path.Base(file) != "version.rb" {
+			continue
+		}
+		content, err := ghContentProvider.GetContents(file)
+		if err != nil {
+			return "", err
+		}
+		if version, err := versionRbVersion(content); err == nil {
+			return version, nil
+		}
+	}
+	return "", fetcher.ErrNoVers
+}
+
+// snakeCase returns the file name of a Ruby module, e.g. "http_client" for
+// "HTTPClient".
+func snakeCase(module string) string {
+	return strings.ToLower(wordBoundaryRegex.ReplaceAllString(module, "${1}${3}_${2}${4}"))
+}
+
+// GetVersionUsingDefaultPath reads the first .gemspec file in the repository
+// root, its name is the gem name, or for a version.rb the one closest to
+// lib.
+func (gemspecFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	treeProvider, ok := ghContentProvider.(provider.TreeProvider)
+	if !ok {
+		return "", provider.ErrNoTreeProvider
+	}
+	files, err := treeProvider.ListFiles()
+	if err != nil {
+		return "", err
+	}
+	defaultPath := ""
+	for _, file := range files {
+		if gemspecFetcher.VersionRb {
+			if strings.HasPrefix(file, "lib/") && path.Base(file) == "version.rb" &&
+				(defaultPath == "" || strings.Count(file, "/") < strings.Count(defaultPath, "/")) {
+				defaultPath = file
+			}
+		} else if !strings.Contains(file, "/") && strings.HasSuffix(file, ".gemspec") {
+			defaultPath = file
+			break
+		}
+	}
+	if defaultPath == "" {
+		return "", fetcher.ErrNoVers
+	}
+	return gemspecFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: defaultPath})
+}
@@ -0,0 +1,85 @@
+package gemspec
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+var literalGemspec = `Gem::Specification.new do |spec|
+  spec.name          = "atc"
+  spec.version       = "0.3.1"
+  spec.summary       = "Automated tag creator"
+  spec.add_dependency "octokit", "~> 6.0"
+end
+`
+
+var constantGemspec = `require_relative "lib/http_client/version"
+
+Gem::Specification.new do |s|
+  s.name    = "http_client"
+  s.version = HTTPClient::VERSION
+end
+`
+
+var versionRb = `# frozen_string_literal: true
+
+module HTTPClient
+  VERSION = "2.1.0.pre"
+end
+`
+
+func TestGemspecFetcher(t *testing.T) {
+	var tests = []struct {
+		cp      provider.MockFilesContentProvider
+		path    string
+		version string
+		err     error
+	}{
+		{provider.MockFilesContentProvider{"atc.gemspec": literalGemspec}, "atc.gemspec", "0.3.1", nil},
+		{provider.MockFilesContentProvider{"http_client.gemspec": constantGemspec, "lib/http_client/version.rb": versionRb}, "http_client.gemspec", "2.1.0.pre", nil},
+		// a module without the conventional file name
+		{provider.MockFilesContentProvider{"gems/h/h.gemspec": constantGemspec, "gems/h/lib/h/version.rb": versionRb}, "gems/h/h.gemspec", "2.1.0.pre", nil},
+		{provider.MockFilesContentProvider{"http_client.gemspec": constantGemspec}, "http_client.gemspec", "", fetcher.ErrNoVers},
+		{provider.MockFilesContentProvider{"x.gemspec": "Gem::Specification.new do |s|\n  s.version = File.read('VERSION')\nend\n"}, "x.gemspec", "", fetcher.ErrNoVers},
+	}
+	for _, test := range tests {
+		vers, err := (&Fetcher{}).GetVersion(test.cp, settings.AtcSettings{Path: test.path})
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: expected err %v, got %v", test.path, test.err, err)
+		}
+		if vers != test.version {
+			t.Errorf("%s: expected %q, got %q", test.path, test.version, vers)
+		}
+	}
+}
+
+func TestGemspecFetcherDefaultPath(t *testing.T) {
+	cp := provider.MockFilesContentProvider{
+		"README.md":                  "# atc",
+		"atc.gemspec":                literalGemspec,
+		"lib/atc/version.rb":         "module Atc\n  VERSION = '0.3.2'\nend\n",
+		"lib/atc/plugins/version.rb": "module Atc::Plugins\n  VERSION = '9.9.9'\nend\n",
+		"spec/fixtures/x.gemspec":    constantGemspec,
+	}
+	if vers, err := (&Fetcher{}).GetVersionUsingDefaultPath(cp); err != nil || vers != "0.3.1" {
+		t.Errorf("gemspec: expected %q, got %q, %v", "0.3.1", vers, err)
+	}
+	if vers, err := NewVersionRbFetcher().GetVersionUsingDefaultPath(cp); err != nil || vers != "0.3.2" {
+		t.Errorf("version.rb: expected %q, got %q, %v", "0.3.2", vers, err)
+	}
+	if _, err := (&Fetcher{}).GetVersionUsingDefaultPath(provider.MockFilesContentProvider{"Gemfile": ""}); err != fetcher.ErrNoVers {
+		t.Errorf("expected err %v, got %v", fetcher.ErrNoVers, err)
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	for module, expected := range map[string]string{"Atc": "atc", "MyGem": "my_gem", "HTTPClient": "http_client", "OAuth2": "o_auth2"} {
+		if name := snakeCase(module); name != expected {
+			t.Errorf("%s: expected %q, got %q", module, expected, name)
+		}
+	}
+}
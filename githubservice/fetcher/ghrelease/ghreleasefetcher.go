@@ -0,0 +1,38 @@
+package ghrelease
+
+import (
+	"errors"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+)
+
+// LatestReleasePath is the reserved path value that selects this fetcher.
+const LatestReleasePath = ":latest-release"
+
+var ErrNoReleaseProvider = errors.New("content provider can't read releases")
+
+// Fetcher uses the tag name of the latest GitHub Release as the version.
+type Fetcher struct {
+}
+
+func (ghReleaseFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	releaseProvider, ok := ghContentProvider.(provider.ReleaseProvider)
+	if !ok {
+		return "", ErrNoReleaseProvider
+	}
+	version, err := releaseProvider.GetLatestRelease()
+	if err != nil {
+		return "", err
+	}
+	if version == "" {
+		return "", fetcher.ErrNoVers
+	}
+	return version, nil
+}
+
+func (ghReleaseFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	return ghReleaseFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: LatestReleasePath})
+}
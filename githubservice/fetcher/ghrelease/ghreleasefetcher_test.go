@@ -0,0 +1,43 @@
+package ghrelease
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+type mockReleaseProvider struct {
+	provider.MockContentProvider
+	release string
+	err     error
+}
+
+func (mrp *mockReleaseProvider) GetLatestRelease() (string, error) {
+	return mrp.release, mrp.err
+}
+
+func TestGhReleaseFetcher(t *testing.T) {
+	releaseErr := errors.New("release error")
+	var tests = []struct {
+		cp      provider.ContentProvider
+		version string
+		err     error
+	}{
+		{&mockReleaseProvider{release: "v1.2.0"}, "v1.2.0", nil},
+		{&mockReleaseProvider{}, "", fetcher.ErrNoVers},
+		{&mockReleaseProvider{err: releaseErr}, "", releaseErr},
+		{&provider.MockContentProvider{Content: "1.0"}, "", ErrNoReleaseProvider},
+	}
+	for _, test := range tests {
+		vers, err := (&Fetcher{}).GetVersion(test.cp, settings.AtcSettings{Path: LatestReleasePath})
+		if err != test.err {
+			t.Errorf("expected err %v, got %v", test.err, err)
+		}
+		if vers != test.version {
+			t.Errorf("expected %q, got %q", test.version, vers)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package goversion
+
+import (
+	"regexp"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+)
+
+type GoVersion struct {
+	Version string
+}
+
+// Fetcher reads the version of a Go package from a `const Version = "..."`
+// declaration, also accepting AppVersion and BuildVersion.
+type Fetcher struct {
+}
+
+// defaultPaths are tried in order by GetVersionUsingDefaultPath.
+var defaultPaths = []string{"version.go", "internal/version.go", "pkg/version/version.go"}
+
+var versionConstRegex = regexp.MustCompile(`(?m)^\s*const\s+(?i:(?:app|build)?version)\s*(?:string\s*)?=\s*"([^"]+)"`)
+
+var unmarshalGoVersion = func(content []byte, goVersionPtr *GoVersion) error {
+	res := versionConstRegex.FindSubmatch(content)
+	if len(res) != 2 {
+		return fetcher.ErrNoVers
+	}
+	goVersionPtr.Version = string(res[1])
+	return nil
+}
+
+func (goVersionFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	goVersion := &GoVersion{}
+	if err := unmarshalGoVersion([]byte(content), goVersion); err != nil {
+		return "", err
+	}
+	return goVersion.Version, nil
+}
+
+func (goVersionFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	var err error
+	for _, defaultPath := range defaultPaths {
+		var version string
+		if version, err = goVersionFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: defaultPath}); err == nil {
+			return version, nil
+		}
+	}
+	return "", err
+}
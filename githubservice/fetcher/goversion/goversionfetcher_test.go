@@ -0,0 +1,68 @@
+package goversion
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+func TestUnmarshalGoVersion(t *testing.T) {
+	var tests = []struct {
+		content string
+		version string
+		err     error
+	}{
+		{"package version\n\nconst Version = \"1.2.3\"\n", "1.2.3", nil},
+		{"package main\n\nconst AppVersion = \"2.0.0-rc1\"", "2.0.0-rc1", nil},
+		{"package main\n\nconst BuildVersion string = \"3.1\"", "3.1", nil},
+		{"package main\n\nconst version = \"0.0.1\"", "0.0.1", nil},
+		{"package main\n\n// const Version = \"9.9.9\"\nconst Version = \"1.0\"", "1.0", nil},
+		{"package main\n\nvar Version = \"1.0\"", "", fetcher.ErrNoVers},
+		{"package main\n\nconst MinVersion = \"1.0\"", "", fetcher.ErrNoVers},
+	}
+	for _, test := range tests {
+		goVersion := &GoVersion{}
+		err := unmarshalGoVersion([]byte(test.content), goVersion)
+		if err != test.err {
+			t.Errorf("content %q: expected err %v, got %v", test.content, test.err, err)
+		}
+		if goVersion.Version != test.version {
+			t.Errorf("content %q: expected %q, got %q", test.content, test.version, goVersion.Version)
+		}
+	}
+}
+
+func TestGoVersionFetcher(t *testing.T) {
+	cp := provider.MockContentProvider{Content: "package version\nconst Version = \"1.4.0\""}
+	vers, err := (&Fetcher{}).GetVersion(&cp, settings.AtcSettings{Path: "cmd/version.go"})
+	if err != nil || vers != "1.4.0" {
+		t.Errorf("expected %q, got %q (err %v)", "1.4.0", vers, err)
+	}
+}
+
+func TestGoVersionDefaultPaths(t *testing.T) {
+	var tests = []struct {
+		files   provider.MockFilesContentProvider
+		version string
+	}{
+		{provider.MockFilesContentProvider{"version.go": `const Version = "1.0.0"`, "internal/version.go": `const Version = "2.0.0"`}, "1.0.0"},
+		{provider.MockFilesContentProvider{"internal/version.go": `const Version = "2.0.0"`}, "2.0.0"},
+		{provider.MockFilesContentProvider{"pkg/version/version.go": `const Version = "3.0.0"`}, "3.0.0"},
+		{provider.MockFilesContentProvider{"version.go": `var Version = "1.0.0"`, "pkg/version/version.go": `const Version = "3.0.0"`}, "3.0.0"},
+	}
+	for _, test := range tests {
+		vers, err := (&Fetcher{}).GetVersionUsingDefaultPath(test.files)
+		if err != nil {
+			t.Errorf("Unexpected error %v", err)
+		}
+		if vers != test.version {
+			t.Errorf("expected %q, got %q", test.version, vers)
+		}
+	}
+
+	if _, err := (&Fetcher{}).GetVersionUsingDefaultPath(provider.MockFilesContentProvider{}); err == nil {
+		t.Errorf("expected error when no default path exists")
+	}
+}
@@ -0,0 +1,61 @@
+package gradleproperties
+
+import (
+	"regexp"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/fetcher/buildgradle"
+)
+
+type GradleProperties struct {
+	Version string
+}
+
+// Fetcher reads the `version` property of a gradle.properties.
+type Fetcher struct {
+}
+
+// buildScripts are the root build scripts whose `version` overrides the one
+// of gradle.properties, like in Gradle.
+var buildScripts = []string{"build.gradle.kts", "build.gradle"}
+
+var versionRegex = regexp.MustCompile(`(?m)^[\t ]*version[\t ]*[=:][\t ]*(\S+)[\t\r ]*$`)
+
+var unmarshalGradleProperties = func(content []byte, gradlePropertiesPtr *GradleProperties) error {
+	res := versionRegex.FindSubmatch(content)
+	if len(res) != 2 {
+		return fetcher.ErrNoVers
+	}
+	gradlePropertiesPtr.Version = string(res[1])
+	return nil
+}
+
+func (gradlePropertiesFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	gradleProperties := &GradleProperties{}
+	if err := unmarshalGradleProperties([]byte(content), gradleProperties); err != nil {
+		return "", err
+	}
+	return gradleProperties.Version, nil
+}
+
+// GetVersionUsingDefaultPath reads the root gradle.properties unless a root
+// build script sets the version to a string, that one is returned then.
+func (gradlePropertiesFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	for _, script := range buildScripts {
+		content, err := ghContentProvider.GetContents(script)
+		if err != nil {
+			continue
+		}
+		if version := buildgradle.ProjectVersion([]byte(content)); version != "" {
+			return version, nil
+		}
+	}
+	return gradlePropertiesFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: "gradle.properties"})
+}
@@ -0,0 +1,57 @@
+package gradleproperties
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+var basicProperties = `# project
+group=io.smartforce
+version=1.7.0
+org.gradle.jvmargs=-Xmx2g
+kotlin.code.style=official
+`
+
+func TestGradlePropertiesFetcher(t *testing.T) {
+	var tests = []struct {
+		content string
+		version string
+		err     error
+	}{
+		{basicProperties, "1.7.0", nil},
+		{"version = 2.0.0-SNAPSHOT\n", "2.0.0-SNAPSHOT", nil},
+		{"version:3.1\r\n", "3.1", nil},
+		{"#version=1.0\nkotlinVersion=1.9.0\n", "", fetcher.ErrNoVers},
+	}
+	for _, test := range tests {
+		cp := provider.MockContentProvider{Content: test.content}
+		vers, err := (&Fetcher{}).GetVersion(&cp, settings.AtcSettings{Path: "gradle.properties"})
+		if err != test.err {
+			t.Errorf("content %q: expected err %v, got %v", test.content, test.err, err)
+		}
+		if vers != test.version {
+			t.Errorf("content %q: expected %q, got %q", test.content, test.version, vers)
+		}
+	}
+}
+
+func TestGradlePropertiesDefaultPath(t *testing.T) {
+	var tests = []struct {
+		files   provider.MockFilesContentProvider
+		version string
+	}{
+		{provider.MockFilesContentProvider{"gradle.properties": basicProperties}, "1.7.0"},
+		{provider.MockFilesContentProvider{"gradle.properties": basicProperties, "build.gradle.kts": "version = \"1.8.0\"\n"}, "1.8.0"},
+		{provider.MockFilesContentProvider{"gradle.properties": basicProperties, "build.gradle": "version '1.9.0'\n"}, "1.9.0"},
+		{provider.MockFilesContentProvider{"gradle.properties": basicProperties, "build.gradle.kts": "version = property(\"release\")\n"}, "1.7.0"},
+	}
+	for _, test := range tests {
+		vers, err := (&Fetcher{}).GetVersionUsingDefaultPath(test.files)
+		if err != nil || vers != test.version {
+			t.Errorf("files %v: expected %q, got %q (err %v)", test.files, test.version, vers, err)
+		}
+	}
+}
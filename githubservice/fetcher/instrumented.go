@@ -0,0 +1,34 @@
+package fetcher
+
+import (
+	"time"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+	"github.com/smartforce-io/atc/metrics"
+)
+
+// InstrumentedVersionFetcher records the duration of the calls to
+// VersionFetcher in metrics.VersionFetchDuration with the Name label.
+type InstrumentedVersionFetcher struct {
+	Name           string
+	VersionFetcher VersionFetcher
+}
+
+func NewInstrumentedFetcher(name string, f VersionFetcher) VersionFetcher {
+	return &InstrumentedVersionFetcher{Name: name, VersionFetcher: f}
+}
+
+func (f *InstrumentedVersionFetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	defer f.observe(time.Now())
+	return f.VersionFetcher.GetVersion(ghContentProvider, settings)
+}
+
+func (f *InstrumentedVersionFetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	defer f.observe(time.Now())
+	return f.VersionFetcher.GetVersionUsingDefaultPath(ghContentProvider)
+}
+
+func (f *InstrumentedVersionFetcher) observe(start time.Time) {
+	metrics.VersionFetchDuration.WithLabelValues(f.Name).Observe(time.Since(start).Seconds())
+}
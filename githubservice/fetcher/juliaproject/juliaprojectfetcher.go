@@ -0,0 +1,62 @@
+package juliaproject
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+)
+
+var ErrNotJuliaProject = errors.New("Project.toml has a [project] table, it isn't a Julia project")
+
+type JuliaProject struct {
+	Version string
+}
+
+// Fetcher reads the top-level `version = "1.2.3"` of a Julia Project.toml.
+type Fetcher struct {
+}
+
+var (
+	versionKeyRegex   = regexp.MustCompile(`^\s*version\s*=\s*["']([^"']+)["']`)
+	projectTableRegex = regexp.MustCompile(`(?m)^\s*\[project\]`)
+)
+
+// unmarshalJuliaProject skips files with a [project] table, the Python
+// metadata table, and only reads the keys before the first table, e.g.
+// [compat] has versions of the dependencies.
+var unmarshalJuliaProject = func(content []byte, juliaProjectPtr *JuliaProject) error {
+	if projectTableRegex.Match(content) {
+		return ErrNotJuliaProject
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "[") {
+			break
+		}
+		if res := versionKeyRegex.FindStringSubmatch(line); len(res) == 2 {
+			juliaProjectPtr.Version = res[1]
+			return nil
+		}
+	}
+	return fetcher.ErrNoVers
+}
+
+func (juliaProjectFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	juliaProject := &JuliaProject{}
+	if err := unmarshalJuliaProject([]byte(content), juliaProject); err != nil {
+		return "", err
+	}
+	return juliaProject.Version, nil
+}
+
+func (juliaProjectFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	return juliaProjectFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: "Project.toml"})
+}
@@ -0,0 +1,43 @@
+package juliaproject
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+)
+
+var basicProject = `name = "AtcExample"
+uuid = "7876af07-990d-54b4-ab0e-23690620f79a"
+authors = ["Jane Doe <jane@example.com>"]
+version = "0.5.1"
+
+[deps]
+JSON = "682c06a0-de6a-54ab-a142-c8b1cf79cde6"
+
+[compat]
+JSON = "0.21"
+julia = "1.6"
+`
+
+func TestJuliaProjectFetcher(t *testing.T) {
+	var tests = []struct {
+		content string
+		version string
+		err     error
+	}{
+		{basicProject, "0.5.1", nil},
+		{"name = \"X\"\n\n[compat]\nversion = \"1.0\"\n", "", fetcher.ErrNoVers},
+		{"[project]\nname = \"x\"\nversion = \"1.2.3\"\n", "", ErrNotJuliaProject},
+	}
+	for _, test := range tests {
+		cp := provider.MockContentProvider{Content: test.content}
+		vers, err := (&Fetcher{}).GetVersionUsingDefaultPath(&cp)
+		if err != test.err {
+			t.Errorf("expected err %v, got %v", test.err, err)
+		}
+		if vers != test.version {
+			t.Errorf("expected %q, got %q", test.version, vers)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package kotlinconfig
+
+import (
+	"regexp"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+)
+
+type KotlinConfig struct {
+	Version string
+}
+
+// Fetcher reads `const val VERSION = "1.2.3"` or `const val APP_VERSION =
+// "1.2.3"` from the Config.kt of a Gradle convention plugin. The file has no
+// standard location, so the path has to be set in .atc.yaml, e.g.
+// `path: build-logic/src/main/kotlin/Config.kt`.
+type Fetcher struct {
+}
+
+var versionConstRegex = regexp.MustCompile(`const val (?:APP_)?VERSION\s*=\s*"([^"]+)"`)
+
+var unmarshalKotlinConfig = func(content []byte, kotlinConfigPtr *KotlinConfig) error {
+	res := versionConstRegex.FindSubmatch(content)
+	if len(res) != 2 {
+		return fetcher.ErrNoVers
+	}
+	kotlinConfigPtr.Version = string(res[1])
+	return nil
+}
+
+func (kotlinConfigFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	kotlinConfig := &KotlinConfig{}
+	if err := unmarshalKotlinConfig([]byte(content), kotlinConfig); err != nil {
+		return "", err
+	}
+	return kotlinConfig.Version, nil
+}
+
+// GetVersionUsingDefaultPath always fails, the path has to be configured.
+func (kotlinConfigFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	return "", fetcher.ErrNoVers
+}
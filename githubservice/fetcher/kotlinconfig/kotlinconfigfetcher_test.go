@@ -0,0 +1,43 @@
+package kotlinconfig
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+var basicConfig = `object Config {
+    const val MIN_SDK = 24
+    const val KOTLIN_VERSION = "1.9.22"
+    const val VERSION = "2.3.0"
+}
+`
+
+func TestKotlinConfigFetcher(t *testing.T) {
+	var tests = []struct {
+		content string
+		version string
+		err     error
+	}{
+		{basicConfig, "2.3.0", nil},
+		{`object Config { const val APP_VERSION = "1.0.0-beta" }`, "1.0.0-beta", nil},
+		{`object Config { const val KOTLIN_VERSION = "1.9.22" }`, "", fetcher.ErrNoVers},
+	}
+	for _, test := range tests {
+		cp := provider.MockContentProvider{Content: test.content}
+		vers, err := (&Fetcher{}).GetVersion(&cp, settings.AtcSettings{Path: "build-logic/src/main/kotlin/Config.kt"})
+		if err != test.err {
+			t.Errorf("expected err %v, got %v", test.err, err)
+		}
+		if vers != test.version {
+			t.Errorf("expected %q, got %q", test.version, vers)
+		}
+	}
+
+	cp := provider.MockContentProvider{Content: basicConfig}
+	if _, err := (&Fetcher{}).GetVersionUsingDefaultPath(&cp); err != fetcher.ErrNoVers {
+		t.Errorf("expected err %v, got %v", fetcher.ErrNoVers, err)
+	}
+}
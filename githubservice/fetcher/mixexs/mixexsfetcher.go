@@ -0,0 +1,63 @@
+package mixexs
+
+import (
+	"regexp"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+)
+
+type MixExs struct {
+	Version string
+}
+
+// Fetcher reads the `version: "1.2.3"` of the project of an Elixir mix.exs,
+// or the module attribute it refers to with `version: @version`. Without a
+// `version:` keyword the `@version` attribute is used.
+type Fetcher struct {
+}
+
+var (
+	versionKeywordRegex   = regexp.MustCompile(`(?m)^[^#\n]*\bversion:\s*"([^"]+)"`)
+	versionAttributeRegex = regexp.MustCompile(`(?m)^[^#\n]*\bversion:\s*@([a-z_][a-zA-Z0-9_]*)`)
+)
+
+// attributeRegex matches the definition of the module attribute name.
+func attributeRegex(name string) *regexp.Regexp {
+	return regexp.MustCompile(`(?m)^\s*@` + regexp.QuoteMeta(name) + `\s+"([^"]+)"`)
+}
+
+var unmarshalMixExs = func(content []byte, mixExsPtr *MixExs) error {
+	if res := versionKeywordRegex.FindSubmatch(content); len(res) == 2 {
+		mixExsPtr.Version = string(res[1])
+		return nil
+	}
+	attribute := "version"
+	if res := versionAttributeRegex.FindSubmatch(content); len(res) == 2 {
+		attribute = string(res[1])
+	}
+	res := attributeRegex(attribute).FindSubmatch(content)
+	if len(res) != 2 {
+		return fetcher.ErrNoVers
+	}
+	mixExsPtr.Version = string(res[1])
+	return nil
+}
+
+func (mixExsFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	mixExs := &MixExs{}
+	if err := unmarshalMixExs([]byte(content), mixExs); err != nil {
+		return "", err
+	}
+	return mixExs.Version, nil
+}
+
+func (mixExsFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	return mixExsFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: "mix.exs"})
+}
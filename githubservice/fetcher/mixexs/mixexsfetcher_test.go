@@ -0,0 +1,66 @@
+package mixexs
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+)
+
+var basicMixExs = `defmodule Atc.MixProject do
+  use Mix.Project
+
+  def project do
+    [
+      app: :atc,
+      version: "0.4.2",
+      elixir: "~> 1.14",
+      deps: deps()
+    ]
+  end
+
+  defp deps do
+    [{:jason, "~> 1.4"}]
+  end
+end
+`
+
+var attributeMixExs = `defmodule Atc.MixProject do
+  use Mix.Project
+
+  @source_url "https://github.com/smartforce-io/atc"
+  @app_version "1.3.0-rc.1"
+
+  def project do
+    [
+      app: :atc,
+      # version: "0.0.1",
+      version: @app_version,
+      source_url: @source_url
+    ]
+  end
+end
+`
+
+func TestMixExsFetcher(t *testing.T) {
+	var tests = []struct {
+		content string
+		version string
+		err     error
+	}{
+		{basicMixExs, "0.4.2", nil},
+		{attributeMixExs, "1.3.0-rc.1", nil},
+		{"defmodule A.MixProject do\n  @version \"2.0.0\"\n  def project, do: [app: :a]\nend\n", "2.0.0", nil},
+		{"defmodule A.MixProject do\n  def project, do: [app: :a, version: version()]\nend\n", "", fetcher.ErrNoVers},
+	}
+	for _, test := range tests {
+		cp := provider.MockContentProvider{Content: test.content}
+		vers, err := (&Fetcher{}).GetVersionUsingDefaultPath(&cp)
+		if err != test.err {
+			t.Errorf("expected err %v, got %v", test.err, err)
+		}
+		if vers != test.version {
+			t.Errorf("expected %q, got %q", test.version, vers)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package npmrc
+
+import (
+	"regexp"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+)
+
+type Npmrc struct {
+	Version string
+}
+
+// Fetcher reads a `version=1.2.3` entry that some CI setups write to .npmrc.
+type Fetcher struct {
+}
+
+var versionRegex = regexp.MustCompile(`(?m)^\s*version\s*=\s*["']?([^"'\s]+)["']?\s*$`)
+
+var unmarshalNpmrc = func(content []byte, npmrcPtr *Npmrc) error {
+	res := versionRegex.FindSubmatch(content)
+	if len(res) != 2 {
+		return fetcher.ErrNoVers
+	}
+	npmrcPtr.Version = string(res[1])
+	return nil
+}
+
+func (npmrcFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	npmrc := &Npmrc{}
+	if err := unmarshalNpmrc([]byte(content), npmrc); err != nil {
+		return "", err
+	}
+	return npmrc.Version, nil
+}
+
+func (npmrcFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	return npmrcFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: ".npmrc"})
+}
@@ -0,0 +1,45 @@
+package npmrc
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+func TestUnmarshalNpmrc(t *testing.T) {
+	var tests = []struct {
+		content string
+		version string
+		err     error
+	}{
+		{"registry=https://registry.npmjs.org/\nversion=1.2.3\n", "1.2.3", nil},
+		{"version = \"2.0.0-beta.1\"", "2.0.0-beta.1", nil},
+		{"; version=0.0.1\nversion=1.0.0", "1.0.0", nil},
+		{"init-version=1.0.0", "", fetcher.ErrNoVers},
+		{"registry=https://registry.npmjs.org/", "", fetcher.ErrNoVers},
+	}
+	for _, test := range tests {
+		npmrc := &Npmrc{}
+		err := unmarshalNpmrc([]byte(test.content), npmrc)
+		if err != test.err {
+			t.Errorf("content %q: expected err %v, got %v", test.content, test.err, err)
+		}
+		if npmrc.Version != test.version {
+			t.Errorf("content %q: expected %q, got %q", test.content, test.version, npmrc.Version)
+		}
+	}
+}
+
+func TestNpmrcFetcher(t *testing.T) {
+	cp := provider.MockContentProvider{Content: "save-exact=true\nversion=3.1.4\n"}
+	vers, err := (&Fetcher{}).GetVersion(&cp, settings.AtcSettings{Path: "web/.npmrc"})
+	if err != nil || vers != "3.1.4" {
+		t.Errorf("expected %q, got %q (err %v)", "3.1.4", vers, err)
+	}
+	cp = provider.MockContentProvider{Err: provider.ErrGeneral}
+	if _, err := (&Fetcher{}).GetVersionUsingDefaultPath(&cp); err != provider.ErrGeneral {
+		t.Errorf("expected %v, got %v", provider.ErrGeneral, err)
+	}
+}
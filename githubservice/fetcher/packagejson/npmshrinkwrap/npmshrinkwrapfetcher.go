@@ -0,0 +1,17 @@
+package npmshrinkwrap
+
+import (
+	"github.com/smartforce-io/atc/githubservice/fetcher/packagejson"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+// Fetcher reads npm-shrinkwrap.json, which keeps the package version in the
+// same "version" field as package.json.
+type Fetcher struct {
+	*packagejson.Fetcher
+}
+
+func (npmShrinkwrapFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	return npmShrinkwrapFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: "npm-shrinkwrap.json"})
+}
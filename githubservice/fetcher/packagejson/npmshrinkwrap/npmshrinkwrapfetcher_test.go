@@ -0,0 +1,28 @@
+package npmshrinkwrap
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+)
+
+var basicShrinkwrap = `{
+  "name": "atc",
+  "version": "1.3.0",
+  "lockfileVersion": 3,
+  "requires": true,
+  "packages": {
+    "node_modules/left-pad": {"version": "1.3.0"}
+  }
+}`
+
+func TestNpmShrinkwrapFetcher(t *testing.T) {
+	cp := provider.MockContentProvider{Content: basicShrinkwrap}
+	vers, err := (&Fetcher{}).GetVersionUsingDefaultPath(&cp)
+	if err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if vers != "1.3.0" {
+		t.Errorf("expected %q, got %q", "1.3.0", vers)
+	}
+}
@@ -0,0 +1,64 @@
+package packervars
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+)
+
+type PackerVars struct {
+	Version string
+}
+
+// Fetcher reads the `version = "1.2.3"` variable of a Packer .pkrvars.hcl
+// file.
+type Fetcher struct {
+}
+
+// variable files only have top-level assignments, so a regex is enough
+// instead of a full HCL parser
+var versionVariableRegex = regexp.MustCompile(`(?m)^\s*version\s*=\s*"([^"]+)"`)
+
+var unmarshalPackerVars = func(content []byte, packerVarsPtr *PackerVars) error {
+	res := versionVariableRegex.FindSubmatch(content)
+	if len(res) != 2 {
+		return fetcher.ErrNoVers
+	}
+	packerVarsPtr.Version = string(res[1])
+	return nil
+}
+
+func (packerVarsFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	packerVars := &PackerVars{}
+	if err := unmarshalPackerVars([]byte(content), packerVars); err != nil {
+		return "", err
+	}
+	return packerVars.Version, nil
+}
+
+// GetVersionUsingDefaultPath reads the first .auto.pkrvars.hcl file in the
+// repository root, Packer loads those files automatically.
+func (packerVarsFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	treeProvider, ok := ghContentProvider.(provider.TreeProvider)
+	if !ok {
+		return "", provider.ErrNoTreeProvider
+	}
+	files, err := treeProvider.ListFiles()
+	if err != nil {
+		return "", err
+	}
+	for _, file := range files {
+		if !strings.Contains(file, "/") && strings.HasSuffix(file, ".auto.pkrvars.hcl") {
+			return packerVarsFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: file})
+		}
+	}
+	return "", fetcher.ErrNoVers
+}
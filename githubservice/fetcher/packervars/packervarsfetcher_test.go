@@ -0,0 +1,58 @@
+package packervars
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+)
+
+var basicPackerVars = `# image settings
+image_name    = "atc-runner"
+version       = "2.4.0"
+source_ami_id = "ami-0123456789abcdef0"
+tags = {
+  version = "old"
+}
+`
+
+func TestUnmarshalPackerVars(t *testing.T) {
+	var tests = []struct {
+		content string
+		version string
+		err     error
+	}{
+		{basicPackerVars, "2.4.0", nil},
+		{"version=\"1.0.0-rc1\"\n", "1.0.0-rc1", nil},
+		{"packer_version = \"1.9.0\"\n", "", fetcher.ErrNoVers},
+	}
+	for _, test := range tests {
+		packerVars := &PackerVars{}
+		err := unmarshalPackerVars([]byte(test.content), packerVars)
+		if err != test.err {
+			t.Errorf("expected err %v, got %v", test.err, err)
+		}
+		if packerVars.Version != test.version {
+			t.Errorf("expected %q, got %q", test.version, packerVars.Version)
+		}
+	}
+}
+
+func TestPackerVarsFetcherDefaultPath(t *testing.T) {
+	cp := provider.MockFilesContentProvider{
+		"variables.pkr.hcl":            `variable "version" {}`,
+		"images/base.auto.pkrvars.hcl": `version = "9.9.9"`,
+		"image.auto.pkrvars.hcl":       basicPackerVars,
+	}
+	vers, err := (&Fetcher{}).GetVersionUsingDefaultPath(cp)
+	if err != nil || vers != "2.4.0" {
+		t.Errorf("expected %q, got %q, %v", "2.4.0", vers, err)
+	}
+
+	if _, err := (&Fetcher{}).GetVersionUsingDefaultPath(provider.MockFilesContentProvider{"variables.pkr.hcl": ""}); err != fetcher.ErrNoVers {
+		t.Errorf("expected err %v, got %v", fetcher.ErrNoVers, err)
+	}
+	if _, err := (&Fetcher{}).GetVersionUsingDefaultPath(&provider.MockContentProvider{}); err != provider.ErrNoTreeProvider {
+		t.Errorf("expected err %v, got %v", provider.ErrNoTreeProvider, err)
+	}
+}
@@ -0,0 +1,52 @@
+package distini
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+)
+
+type DistIni struct {
+	Version string
+}
+
+// Fetcher reads the `version = 1.23` of the root section of a Dist::Zilla
+// dist.ini.
+type Fetcher struct {
+}
+
+var versionKeyRegex = regexp.MustCompile(`^\s*version\s*=\s*(.+)`)
+
+var unmarshalDistIni = func(content []byte, distIniPtr *DistIni) error {
+	for _, line := range strings.Split(string(content), "\n") {
+		// plugin sections can have their own version keys
+		if strings.HasPrefix(strings.TrimSpace(line), "[") {
+			break
+		}
+		if res := versionKeyRegex.FindStringSubmatch(line); len(res) == 2 {
+			distIniPtr.Version = strings.TrimSpace(res[1])
+			return nil
+		}
+	}
+	return fetcher.ErrNoVers
+}
+
+func (distIniFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	distIni := &DistIni{}
+	if err := unmarshalDistIni([]byte(content), distIni); err != nil {
+		return "", err
+	}
+	return distIni.Version, nil
+}
+
+func (distIniFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	return distIniFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: "dist.ini"})
+}
@@ -0,0 +1,30 @@
+package distini
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+)
+
+func TestDistIniFetcher(t *testing.T) {
+	var tests = []struct {
+		content string
+		version string
+		err     error
+	}{
+		{"name    = Foo-Bar\nauthor  = Jane <jane@example.com>\nlicense = Perl_5\nversion = 1.23\n\n[@Basic]\n", "1.23", nil},
+		{"name = Foo\nversion=0.001_01  \n", "0.001_01", nil},
+		{"name = Foo\n\n[Prereqs]\nversion = 0.77\n", "", fetcher.ErrNoVers},
+	}
+	for _, test := range tests {
+		cp := provider.MockContentProvider{Content: test.content}
+		vers, err := (&Fetcher{}).GetVersionUsingDefaultPath(&cp)
+		if err != test.err {
+			t.Errorf("expected err %v, got %v", test.err, err)
+		}
+		if vers != test.version {
+			t.Errorf("expected %q, got %q", test.version, vers)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package makefilepl
+
+import (
+	"regexp"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+)
+
+type MakefilePL struct {
+	Version string
+}
+
+// Fetcher reads the `VERSION => '1.23'` argument of WriteMakefile in a Perl
+// Makefile.PL.
+type Fetcher struct {
+}
+
+// \b keeps MIN_PERL_VERSION from matching, VERSION_FROM has no "=>" after VERSION
+var versionArgRegex = regexp.MustCompile(`\b['"]?VERSION['"]?\s*=>\s*['"]?([^\s'",]+)`)
+
+var unmarshalMakefilePL = func(content []byte, makefilePLPtr *MakefilePL) error {
+	res := versionArgRegex.FindSubmatch(content)
+	if len(res) != 2 {
+		return fetcher.ErrNoVers
+	}
+	makefilePLPtr.Version = string(res[1])
+	return nil
+}
+
+func (makefilePLFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	makefilePL := &MakefilePL{}
+	if err := unmarshalMakefilePL([]byte(content), makefilePL); err != nil {
+		return "", err
+	}
+	return makefilePL.Version, nil
+}
+
+func (makefilePLFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	return makefilePLFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: "Makefile.PL"})
+}
@@ -0,0 +1,31 @@
+package makefilepl
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+)
+
+func TestMakefilePLFetcher(t *testing.T) {
+	var tests = []struct {
+		content string
+		version string
+		err     error
+	}{
+		{"use ExtUtils::MakeMaker;\nWriteMakefile(\n    NAME => 'Foo::Bar',\n    MIN_PERL_VERSION => '5.008',\n    VERSION => '1.23',\n);", "1.23", nil},
+		{"WriteMakefile(NAME => \"Foo\", 'VERSION' => \"0.01_02\");", "0.01_02", nil},
+		{"WriteMakefile(NAME => 'Foo', VERSION => 2.5,);", "2.5", nil},
+		{"WriteMakefile(NAME => 'Foo', VERSION_FROM => 'lib/Foo.pm', MIN_PERL_VERSION => 5.010);", "", fetcher.ErrNoVers},
+	}
+	for _, test := range tests {
+		cp := provider.MockContentProvider{Content: test.content}
+		vers, err := (&Fetcher{}).GetVersionUsingDefaultPath(&cp)
+		if err != test.err {
+			t.Errorf("expected err %v, got %v", test.err, err)
+		}
+		if vers != test.version {
+			t.Errorf("expected %q, got %q", test.version, vers)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package plaintext
+
+import (
+	"strings"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+)
+
+type PlainText struct {
+	Version string
+}
+
+// Fetcher reads a file that holds nothing but the version, like a `.version`
+// dotfile. A "v" prefix is dropped so "v1.2.3" and "1.2.3" are the same.
+type Fetcher struct {
+	// DefaultPaths are tried in order by GetVersionUsingDefaultPath,
+	// defaultPaths if nil.
+	DefaultPaths []string
+}
+
+// defaultPaths are tried in order by GetVersionUsingDefaultPath.
+var defaultPaths = []string{".version"}
+
+// NewVersionFileFetcher returns the Fetcher of the `VERSION` file Go and
+// other projects without a manifest version keep in the root.
+func NewVersionFileFetcher() *Fetcher {
+	return &Fetcher{DefaultPaths: []string{"VERSION"}}
+}
+
+var unmarshalPlainText = func(content []byte, plainTextPtr *PlainText) error {
+	version := strings.TrimSpace(string(content))
+	if i := strings.IndexAny(version, "\r\n"); i >= 0 {
+		version = strings.TrimSpace(version[:i])
+	}
+	version = strings.TrimPrefix(strings.TrimPrefix(version, "v"), "V")
+	if version == "" || strings.ContainsAny(version, " \t=") {
+		return fetcher.ErrNoVers
+	}
+	plainTextPtr.Version = version
+	return nil
+}
+
+func (plainTextFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	plainText := &PlainText{}
+	if err := unmarshalPlainText([]byte(content), plainText); err != nil {
+		return "", err
+	}
+	return plainText.Version, nil
+}
+
+func (plainTextFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	paths := plainTextFetcher.DefaultPaths
+	if paths == nil {
+		paths = defaultPaths
+	}
+	var err error
+	for _, defaultPath := range paths {
+		var version string
+		if version, err = plainTextFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: defaultPath}); err == nil {
+			return version, nil
+		}
+	}
+	return "", err
+}
@@ -0,0 +1,65 @@
+package plaintext
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+func TestUnmarshalPlainText(t *testing.T) {
+	var tests = []struct {
+		content string
+		version string
+		err     error
+	}{
+		{"1.2.3", "1.2.3", nil},
+		{"v1.2.3\n", "1.2.3", nil},
+		{"  V2.0.0-rc1  \r\n", "2.0.0-rc1", nil},
+		{"3.0\n# released on friday\n", "3.0", nil},
+		{"", "", fetcher.ErrNoVers},
+		{"\n\n", "", fetcher.ErrNoVers},
+		{"v", "", fetcher.ErrNoVers},
+		{"version=1.0", "", fetcher.ErrNoVers},
+		{"version 1.0", "", fetcher.ErrNoVers},
+	}
+	for _, test := range tests {
+		plainText := &PlainText{}
+		err := unmarshalPlainText([]byte(test.content), plainText)
+		if err != test.err {
+			t.Errorf("content %q: expected err %v, got %v", test.content, test.err, err)
+		}
+		if plainText.Version != test.version {
+			t.Errorf("content %q: expected %q, got %q", test.content, test.version, plainText.Version)
+		}
+	}
+}
+
+func TestPlainTextFetcher(t *testing.T) {
+	cp := provider.MockContentProvider{Content: "v4.5.6\n"}
+	vers, err := (&Fetcher{}).GetVersion(&cp, settings.AtcSettings{Path: "app/.version"})
+	if err != nil || vers != "4.5.6" {
+		t.Errorf("expected %q, got %q (err %v)", "4.5.6", vers, err)
+	}
+	vers, err = (&Fetcher{}).GetVersionUsingDefaultPath(&cp)
+	if err != nil || vers != "4.5.6" {
+		t.Errorf("expected %q, got %q (err %v)", "4.5.6", vers, err)
+	}
+	cp = provider.MockContentProvider{Err: provider.ErrGeneral}
+	if _, err := (&Fetcher{}).GetVersionUsingDefaultPath(&cp); err != provider.ErrGeneral {
+		t.Errorf("expected %v, got %v", provider.ErrGeneral, err)
+	}
+}
+
+func TestVersionFileFetcher(t *testing.T) {
+	cp := provider.MockFilesContentProvider{"VERSION": "1.4.0\n", ".version": "9.9.9"}
+	vers, err := NewVersionFileFetcher().GetVersionUsingDefaultPath(cp)
+	if err != nil || vers != "1.4.0" {
+		t.Errorf("expected %q, got %q (err %v)", "1.4.0", vers, err)
+	}
+	vers, err = (&Fetcher{}).GetVersionUsingDefaultPath(cp)
+	if err != nil || vers != "9.9.9" {
+		t.Errorf("expected %q, got %q (err %v)", "9.9.9", vers, err)
+	}
+}
@@ -2,6 +2,10 @@ package pomxml
 
 import (
 	"encoding/xml"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
 
 	"github.com/smartforce-io/atc/githubservice/provider"
 
@@ -10,12 +14,40 @@ import (
 )
 
 type PomXml struct {
-	Version string `xml:"version"`
+	Version    string     `xml:"version"`
+	Parent     Parent     `xml:"parent"`
+	Properties Properties `xml:"properties"`
 }
 
+type Parent struct {
+	Version      string `xml:"version"`
+	RelativePath string `xml:"relativePath"`
+}
+
+type Properties struct {
+	Entries []Property `xml:",any"`
+}
+
+type Property struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+// Fetcher reads the project `<version>` of a pom.xml, or the `<parent>`
+// version it inherits. `${revision}`-style placeholders of CI friendly
+// versions are resolved with the `-D` options of .mvn/maven.config, the
+// properties of the pom and those of its parents.
 type Fetcher struct {
 }
 
+// mavenConfigPath is where Maven reads the default command line options.
+const mavenConfigPath = ".mvn/maven.config"
+
+// maxParents limits how many parent poms are read for the properties.
+const maxParents = 5
+
+var placeholderRegex = regexp.MustCompile(`\$\{([^}]+)\}`)
+
 var unmarshalPomXml = func(content []byte, pomXmlPtr *PomXml) error {
 	return xml.Unmarshal([]byte(content), pomXmlPtr)
 }
@@ -29,10 +61,100 @@ func (pomXmlFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvi
 	if err := unmarshalPomXml([]byte(content), pom); err != nil {
 		return "", err
 	}
-	if pom.Version == "" {
+	version := strings.TrimSpace(pom.Version)
+	if version == "" {
+		version = strings.TrimSpace(pom.Parent.Version)
+	}
+	if version == "" {
 		return "", fetcher.ErrNoVers
 	}
-	return pom.Version, nil
+	if !strings.Contains(version, "${") {
+		return version, nil
+	}
+	return resolvePlaceholders(ghContentProvider, settings.Path, pom, version)
+}
+
+// resolvePlaceholders replaces the ${...} placeholders of version read from
+// the pom at pomPath.
+func resolvePlaceholders(ghContentProvider provider.ContentProvider, pomPath string, pom *PomXml, version string) (string, error) {
+	properties := map[string]string{}
+	// the poms closest to pomPath win, the command line options win over all
+	addProperties := func(pom *PomXml) {
+		for _, property := range pom.Properties.Entries {
+			if _, ok := properties[property.XMLName.Local]; !ok {
+				properties[property.XMLName.Local] = strings.TrimSpace(property.Value)
+			}
+		}
+	}
+	for name, value := range mavenConfigProperties(ghContentProvider) {
+		properties[name] = value
+	}
+	addProperties(pom)
+	if parentVersion := strings.TrimSpace(pom.Parent.Version); parentVersion != "" {
+		properties["project.parent.version"] = parentVersion
+		properties["parent.version"] = parentVersion
+	}
+
+	parentPath := pomPath
+	for i := 0; i < maxParents && pom.Parent.Version != ""; i++ {
+		relativePath := pom.Parent.RelativePath
+		if relativePath == "" {
+			relativePath = "../pom.xml"
+		}
+		if !strings.HasSuffix(relativePath, ".xml") {
+			relativePath = path.Join(relativePath, "pom.xml")
+		}
+		parentPath = path.Join(path.Dir(parentPath), relativePath)
+		if strings.HasPrefix(parentPath, "../") {
+			break
+		}
+		content, err := ghContentProvider.GetContents(parentPath)
+		if err != nil {
+			break
+		}
+		pom = &PomXml{}
+		if err := unmarshalPomXml([]byte(content), pom); err != nil {
+			break
+		}
+		addProperties(pom)
+	}
+
+	var unresolved []string
+	resolved := version
+	// properties may refer to other properties
+	for i := 0; i < maxParents && strings.Contains(resolved, "${"); i++ {
+		unresolved = nil
+		resolved = placeholderRegex.ReplaceAllStringFunc(resolved, func(placeholder string) string {
+			name := placeholderRegex.FindStringSubmatch(placeholder)[1]
+			if value, ok := properties[name]; ok {
+				return value
+			}
+			unresolved = append(unresolved, name)
+			return placeholder
+		})
+	}
+	if strings.Contains(resolved, "${") {
+		return "", fmt.Errorf("%w: unresolved properties %q in %q", fetcher.ErrNoVers, unresolved, version)
+	}
+	return resolved, nil
+}
+
+// mavenConfigProperties returns the -Dname=value options of .mvn/maven.config.
+func mavenConfigProperties(ghContentProvider provider.ContentProvider) map[string]string {
+	properties := map[string]string{}
+	content, err := ghContentProvider.GetContents(mavenConfigPath)
+	if err != nil {
+		return properties
+	}
+	for _, option := range strings.Fields(content) {
+		if !strings.HasPrefix(option, "-D") {
+			continue
+		}
+		if name, value, ok := strings.Cut(strings.TrimPrefix(option, "-D"), "="); ok {
+			properties[name] = strings.Trim(value, `"'`)
+		}
+	}
+	return properties
 }
 
 func (pomXmlFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
@@ -134,3 +134,44 @@ func TestPomXmlFetcherUnmarshalError(t *testing.T) {
 
 	unmarshalPomXml = unmarshalPomXmlCopy
 }
+
+func TestPomXmlFetcherProperties(t *testing.T) {
+	rootPom := `<project>
+	<groupId>io.smartforce</groupId>
+	<artifactId>atc-parent</artifactId>
+	<version>${revision}${changelist}</version>
+	<properties>
+		<revision>1.4.0</revision>
+		<changelist>-SNAPSHOT</changelist>
+		<base.version>2.0</base.version>
+		<full.version>${base.version}.1</full.version>
+	</properties>
+</project>`
+	var tests = []struct {
+		files   provider.MockFilesContentProvider
+		path    string
+		version string
+		err     error
+	}{
+		{provider.MockFilesContentProvider{"pom.xml": rootPom}, "pom.xml", "1.4.0-SNAPSHOT", nil},
+		// the command line options of maven.config win
+		{provider.MockFilesContentProvider{"pom.xml": rootPom, ".mvn/maven.config": "-B -Drevision=1.5.0 -Dchangelist="}, "pom.xml", "1.5.0", nil},
+		// a module inherits the version of its parent
+		{provider.MockFilesContentProvider{
+			"pom.xml":      rootPom,
+			"core/pom.xml": "<project><parent><artifactId>atc-parent</artifactId><version>${revision}${changelist}</version></parent></project>",
+		}, "core/pom.xml", "1.4.0-SNAPSHOT", nil},
+		{provider.MockFilesContentProvider{
+			"build/parent.xml": rootPom,
+			"core/pom.xml":     "<project><parent><version>3.0</version><relativePath>../build/parent.xml</relativePath></parent><version>${full.version}</version></project>",
+		}, "core/pom.xml", "2.0.1", nil},
+		{provider.MockFilesContentProvider{"pom.xml": "<project><parent><version>7.1</version></parent></project>"}, "pom.xml", "7.1", nil},
+		{provider.MockFilesContentProvider{"pom.xml": "<project><version>${revision}</version></project>"}, "pom.xml", "", fetcher.ErrNoVers},
+	}
+	for _, test := range tests {
+		vers, err := (&Fetcher{}).GetVersion(test.files, settings.AtcSettings{Path: test.path})
+		if !errors.Is(err, test.err) || vers != test.version {
+			t.Errorf("%s: expected %q, %v, got %q, %v", test.path, test.version, test.err, vers, err)
+		}
+	}
+}
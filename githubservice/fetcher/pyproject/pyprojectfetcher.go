@@ -0,0 +1,72 @@
+package pyproject
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+)
+
+type PyProject struct {
+	// ProjectVersion is the PEP 621 `[project] version`.
+	ProjectVersion string
+	// PoetryVersion is the `[tool.poetry] version` of Poetry before 2.0.
+	PoetryVersion string
+}
+
+// Fetcher reads the `[project] version` of a Python pyproject.toml, or the
+// `[tool.poetry] version` when there is none. A version listed in
+// `dynamic` isn't in the file, so it can't be read.
+type Fetcher struct {
+}
+
+var (
+	tableRegex   = regexp.MustCompile(`^\s*\[([^\[\]]+)\]`)
+	versionRegex = regexp.MustCompile(`^\s*version\s*=\s*["']([^"']+)["']`)
+)
+
+var unmarshalPyProject = func(content []byte, pyProjectPtr *PyProject) error {
+	table := ""
+	for _, line := range strings.Split(string(content), "\n") {
+		if res := tableRegex.FindStringSubmatch(line); res != nil {
+			table = strings.TrimSpace(res[1])
+			continue
+		}
+		res := versionRegex.FindStringSubmatch(line)
+		if res == nil {
+			continue
+		}
+		switch table {
+		case "project":
+			pyProjectPtr.ProjectVersion = res[1]
+		case "tool.poetry":
+			pyProjectPtr.PoetryVersion = res[1]
+		}
+	}
+	return nil
+}
+
+func (pyProjectFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	pyProject := &PyProject{}
+	if err := unmarshalPyProject([]byte(content), pyProject); err != nil {
+		return "", err
+	}
+	if pyProject.ProjectVersion != "" {
+		return pyProject.ProjectVersion, nil
+	}
+	if pyProject.PoetryVersion != "" {
+		return pyProject.PoetryVersion, nil
+	}
+	return "", fetcher.ErrNoVers
+}
+
+func (pyProjectFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	return pyProjectFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: "pyproject.toml"})
+}
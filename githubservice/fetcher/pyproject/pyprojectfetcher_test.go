@@ -0,0 +1,55 @@
+package pyproject
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+)
+
+var pep621Project = `[build-system]
+requires = ["hatchling"]
+build-backend = "hatchling.build"
+
+[project]
+name = "atc-example"
+version = "1.4.2"
+dependencies = ["requests>=2.31"]
+
+[tool.ruff]
+target-version = "py311"
+`
+
+var poetryProject = `[tool.poetry]
+name = "atc-example"
+version = '0.9.0'
+description = ""
+
+[tool.poetry.dependencies]
+python = "^3.11"
+requests = { version = "^2.31" }
+`
+
+func TestPyProjectFetcher(t *testing.T) {
+	var tests = []struct {
+		content string
+		version string
+		err     error
+	}{
+		{pep621Project, "1.4.2", nil},
+		{poetryProject, "0.9.0", nil},
+		{"[project]\nversion = \"2.0.0\"\n\n[tool.poetry]\nversion = \"1.0.0\"\n", "2.0.0", nil},
+		{"[project]\nname = \"x\"\ndynamic = [\"version\"]\n", "", fetcher.ErrNoVers},
+		{"[tool.poetry.dependencies]\nversion = \"1.0\"\n", "", fetcher.ErrNoVers},
+	}
+	for _, test := range tests {
+		cp := provider.MockContentProvider{Content: test.content}
+		vers, err := (&Fetcher{}).GetVersionUsingDefaultPath(&cp)
+		if err != test.err {
+			t.Errorf("expected err %v, got %v", test.err, err)
+		}
+		if vers != test.version {
+			t.Errorf("expected %q, got %q", test.version, vers)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package rdescription
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+)
+
+var ErrNotRPackage = errors.New("DESCRIPTION has no Package field, it isn't an R package")
+
+type Description struct {
+	Package string
+	Version string
+}
+
+// Fetcher reads the `Version:` field of an R package DESCRIPTION file.
+type Fetcher struct {
+}
+
+var (
+	packageFieldRegex = regexp.MustCompile(`(?m)^Package:\s*(.+)$`)
+	versionFieldRegex = regexp.MustCompile(`(?m)^Version:\s*(.+)$`)
+)
+
+// unmarshalDescription only reads the R DESCRIPTION files, they have the
+// `Package:` field while the DESCRIPTION of some Ruby gems has `Name:`.
+var unmarshalDescription = func(content []byte, descriptionPtr *Description) error {
+	pkg := packageFieldRegex.FindSubmatch(content)
+	if len(pkg) != 2 {
+		return ErrNotRPackage
+	}
+	descriptionPtr.Package = strings.TrimSpace(string(pkg[1]))
+	res := versionFieldRegex.FindSubmatch(content)
+	if len(res) != 2 {
+		return fetcher.ErrNoVers
+	}
+	descriptionPtr.Version = strings.TrimSpace(string(res[1]))
+	if descriptionPtr.Version == "" {
+		return fetcher.ErrNoVers
+	}
+	return nil
+}
+
+func (descriptionFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	description := &Description{}
+	if err := unmarshalDescription([]byte(content), description); err != nil {
+		return "", err
+	}
+	return description.Version, nil
+}
+
+func (descriptionFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	return descriptionFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: "DESCRIPTION"})
+}
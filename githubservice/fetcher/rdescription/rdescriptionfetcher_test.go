@@ -0,0 +1,42 @@
+package rdescription
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+)
+
+var basicDescription = `Package: atcexample
+Type: Package
+Title: Automated Tag Creator Example
+Version: 0.4.2
+Authors@R: person("Jane", "Doe", email = "jane@example.com", role = c("aut", "cre"))
+Description: An example package.
+License: MIT + file LICENSE
+Imports:
+    Biobase (>= 2.50.0)
+`
+
+func TestRDescriptionFetcher(t *testing.T) {
+	var tests = []struct {
+		content string
+		version string
+		err     error
+	}{
+		{basicDescription, "0.4.2", nil},
+		{"Package: x\nVersion: 1.0.0.9000\r\n", "1.0.0.9000", nil},
+		{"Package: x\nTitle: no version\n", "", fetcher.ErrNoVers},
+		{"Name: rubygem\nVersion: 1.2.3\n", "", ErrNotRPackage},
+	}
+	for _, test := range tests {
+		cp := provider.MockContentProvider{Content: test.content}
+		vers, err := (&Fetcher{}).GetVersionUsingDefaultPath(&cp)
+		if err != test.err {
+			t.Errorf("expected err %v, got %v", test.err, err)
+		}
+		if vers != test.version {
+			t.Errorf("expected %q, got %q", test.version, vers)
+		}
+	}
+}
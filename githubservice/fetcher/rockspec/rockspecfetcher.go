@@ -0,0 +1,66 @@
+package rockspec
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+)
+
+type Rockspec struct {
+	Version string
+}
+
+// Fetcher reads the `version = "1.2.3-1"` of a LuaRocks .rockspec file
+// without the rockspec revision.
+type Fetcher struct {
+}
+
+var (
+	versionFieldRegex = regexp.MustCompile(`(?m)^\s*version\s*=\s*["']([^"']+)`)
+	revisionRegex     = regexp.MustCompile(`-\d+$`)
+)
+
+var unmarshalRockspec = func(content []byte, rockspecPtr *Rockspec) error {
+	res := versionFieldRegex.FindSubmatch(content)
+	if len(res) != 2 {
+		return fetcher.ErrNoVers
+	}
+	// the revision only changes when the rockspec is repackaged
+	rockspecPtr.Version = revisionRegex.ReplaceAllString(string(res[1]), "")
+	return nil
+}
+
+func (rockspecFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	rockspec := &Rockspec{}
+	if err := unmarshalRockspec([]byte(content), rockspec); err != nil {
+		return "", err
+	}
+	return rockspec.Version, nil
+}
+
+// GetVersionUsingDefaultPath reads the first .rockspec file in the
+// repository root.
+func (rockspecFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	treeProvider, ok := ghContentProvider.(provider.TreeProvider)
+	if !ok {
+		return "", provider.ErrNoTreeProvider
+	}
+	files, err := treeProvider.ListFiles()
+	if err != nil {
+		return "", err
+	}
+	for _, file := range files {
+		if !strings.Contains(file, "/") && strings.HasSuffix(file, ".rockspec") {
+			return rockspecFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: file})
+		}
+	}
+	return "", fetcher.ErrNoVers
+}
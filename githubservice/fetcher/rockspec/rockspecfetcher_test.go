@@ -0,0 +1,62 @@
+package rockspec
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+)
+
+var basicRockspec = `rockspec_format = "3.0"
+package = "atc-example"
+version = "1.4.2-1"
+source = {
+   url = "git+https://github.com/smartforce-io/atc-example.git",
+   tag = "v1.4.2"
+}
+dependencies = {
+   "lua >= 5.1"
+}
+`
+
+func TestUnmarshalRockspec(t *testing.T) {
+	var tests = []struct {
+		content string
+		version string
+		err     error
+	}{
+		{basicRockspec, "1.4.2", nil},
+		{"version = 'scm-1'\n", "scm", nil},
+		{"version = \"2.0.0\"\n", "2.0.0", nil},
+		{"package = \"x\"\n", "", fetcher.ErrNoVers},
+	}
+	for _, test := range tests {
+		rockspec := &Rockspec{}
+		err := unmarshalRockspec([]byte(test.content), rockspec)
+		if err != test.err {
+			t.Errorf("expected err %v, got %v", test.err, err)
+		}
+		if rockspec.Version != test.version {
+			t.Errorf("expected %q, got %q", test.version, rockspec.Version)
+		}
+	}
+}
+
+func TestRockspecFetcherDefaultPath(t *testing.T) {
+	cp := provider.MockFilesContentProvider{
+		"README.md":                      "# atc",
+		"rockspecs/atc-0.1.0-1.rockspec": "version = \"0.1.0-1\"",
+		"atc-example-1.4.2-1.rockspec":   basicRockspec,
+	}
+	vers, err := (&Fetcher{}).GetVersionUsingDefaultPath(cp)
+	if err != nil || vers != "1.4.2" {
+		t.Errorf("expected %q, got %q, %v", "1.4.2", vers, err)
+	}
+
+	if _, err := (&Fetcher{}).GetVersionUsingDefaultPath(provider.MockFilesContentProvider{"README.md": ""}); err != fetcher.ErrNoVers {
+		t.Errorf("expected err %v, got %v", fetcher.ErrNoVers, err)
+	}
+	if _, err := (&Fetcher{}).GetVersionUsingDefaultPath(&provider.MockContentProvider{}); err != provider.ErrNoTreeProvider {
+		t.Errorf("expected err %v, got %v", provider.ErrNoTreeProvider, err)
+	}
+}
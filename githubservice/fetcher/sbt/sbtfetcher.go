@@ -0,0 +1,80 @@
+package sbt
+
+import (
+	"errors"
+	"path"
+	"regexp"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+)
+
+type Sbt struct {
+	Version string
+}
+
+// Fetcher reads the `version := "1.2.3"` setting of a Scala build.sbt,
+// including `ThisBuild / version`. If build.sbt doesn't set it, the version
+// is looked up in project/Build.scala and project/Versions.scala next to it.
+type Fetcher struct {
+}
+
+// scalaPaths are tried in order, relative to the directory of build.sbt.
+var scalaPaths = []string{"project/Build.scala", "project/Versions.scala"}
+
+var (
+	versionSettingRegex = regexp.MustCompile(`(?m)^\s*(?:ThisBuild\s*/\s*)?version(?:\s+in\s+ThisBuild)?\s*:=\s*"([^"]+)"`)
+	// Scala files usually keep it in a val, e.g. `val appVersion = "1.2.3"`
+	versionValRegex = regexp.MustCompile(`(?m)^\s*(?:final\s+)?(?:lazy\s+)?val\s+(?i:(?:app|project|build)?version)\s*(?::\s*String\s*)?=\s*"([^"]+)"`)
+)
+
+var unmarshalSbt = func(content []byte, sbtPtr *Sbt) error {
+	res := versionSettingRegex.FindSubmatch(content)
+	if len(res) != 2 {
+		return fetcher.ErrNoVers
+	}
+	sbtPtr.Version = string(res[1])
+	return nil
+}
+
+var unmarshalScala = func(content []byte, sbtPtr *Sbt) error {
+	if err := unmarshalSbt(content, sbtPtr); err == nil {
+		return nil
+	}
+	res := versionValRegex.FindSubmatch(content)
+	if len(res) != 2 {
+		return fetcher.ErrNoVers
+	}
+	sbtPtr.Version = string(res[1])
+	return nil
+}
+
+func (sbtFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	sbt := &Sbt{}
+	err = unmarshalSbt([]byte(content), sbt)
+	if err == nil {
+		return sbt.Version, nil
+	}
+	for _, scalaPath := range scalaPaths {
+		content, scalaErr := ghContentProvider.GetContents(path.Join(path.Dir(settings.Path), scalaPath))
+		if scalaErr != nil {
+			continue
+		}
+		if scalaErr = unmarshalScala([]byte(content), sbt); scalaErr == nil {
+			return sbt.Version, nil
+		} else if !errors.Is(scalaErr, fetcher.ErrNoVers) {
+			return "", scalaErr
+		}
+	}
+	return "", err
+}
+
+func (sbtFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	return sbtFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: "build.sbt"})
+}
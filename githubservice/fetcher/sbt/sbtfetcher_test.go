@@ -0,0 +1,41 @@
+package sbt
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+func TestSbtFetcher(t *testing.T) {
+	var tests = []struct {
+		files   provider.MockFilesContentProvider
+		path    string
+		version string
+		err     error
+	}{
+		{provider.MockFilesContentProvider{"build.sbt": "name := \"atc\"\nversion := \"1.2.3\"\nscalaVersion := \"2.13.10\""}, "build.sbt", "1.2.3", nil},
+		{provider.MockFilesContentProvider{"build.sbt": "ThisBuild / scalaVersion := \"3.3.0\"\nThisBuild / version := \"0.4.0-SNAPSHOT\""}, "build.sbt", "0.4.0-SNAPSHOT", nil},
+		{provider.MockFilesContentProvider{"build.sbt": "version in ThisBuild := \"2.0\""}, "build.sbt", "2.0", nil},
+		{provider.MockFilesContentProvider{
+			"app/build.sbt":                "lazy val root = project.settings(version := Versions.app)",
+			"app/project/Versions.scala":   "object Versions {\n  val scalaVersion = \"2.13.10\"\n  val appVersion = \"3.1.4\"\n}",
+			"app/project/Build.scala.orig": "val version = \"0.0.1\"",
+		}, "app/build.sbt", "3.1.4", nil},
+		{provider.MockFilesContentProvider{
+			"build.sbt":           "lazy val root = project",
+			"project/Build.scala": "object Build {\n  ThisBuild / version := \"5.0.0\"\n}",
+		}, "build.sbt", "5.0.0", nil},
+		{provider.MockFilesContentProvider{"build.sbt": "scalaVersion := \"2.13.10\""}, "build.sbt", "", fetcher.ErrNoVers},
+	}
+	for _, test := range tests {
+		vers, err := (&Fetcher{}).GetVersion(test.files, settings.AtcSettings{Path: test.path})
+		if err != test.err {
+			t.Errorf("%v: expected err %v, got %v", test.files, test.err, err)
+		}
+		if vers != test.version {
+			t.Errorf("%v: expected %q, got %q", test.files, test.version, vers)
+		}
+	}
+}
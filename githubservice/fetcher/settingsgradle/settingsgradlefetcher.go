@@ -0,0 +1,82 @@
+package settingsgradle
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+)
+
+type SettingsGradle struct {
+	Name        string
+	Version     string
+	VersionFile string
+}
+
+// Fetcher reads the version of a Gradle composite build from settings.gradle.
+// The version may be set there directly, in a file passed to versionFile(),
+// or in the gradle.properties next to settings.gradle.
+type Fetcher struct {
+}
+
+var (
+	rootProjectNameRegex   = regexp.MustCompile(`rootProject\.name\s*=\s*["']([^"']+)["']`)
+	versionRegex           = regexp.MustCompile(`(?m)^[^/\n]*\bversion\s*=\s*["']([^"']+)["']`)
+	versionFileRegex       = regexp.MustCompile(`versionFile\s*\(?\s*["']([^"']+)["']`)
+	propertiesVersionRegex = regexp.MustCompile(`(?m)^\s*version\s*[=:]\s*(\S+)\s*$`)
+)
+
+var unmarshalSettingsGradle = func(content []byte, settingsGradlePtr *SettingsGradle) error {
+	if res := rootProjectNameRegex.FindSubmatch(content); len(res) == 2 {
+		settingsGradlePtr.Name = string(res[1])
+	}
+	if res := versionRegex.FindSubmatch(content); len(res) == 2 {
+		settingsGradlePtr.Version = string(res[1])
+		return nil
+	}
+	if res := versionFileRegex.FindSubmatch(content); len(res) == 2 {
+		settingsGradlePtr.VersionFile = string(res[1])
+		return nil
+	}
+	return fetcher.ErrNoVers
+}
+
+func readVersionFile(ghContentProvider provider.ContentProvider, filePath string) (string, error) {
+	content, err := ghContentProvider.GetContents(filePath)
+	if err != nil {
+		return "", err
+	}
+	if res := propertiesVersionRegex.FindStringSubmatch(content); len(res) == 2 {
+		return res[1], nil
+	}
+	version := strings.TrimSpace(content)
+	if version == "" || strings.ContainsAny(version, " \n=") {
+		return "", fetcher.ErrNoVers
+	}
+	return version, nil
+}
+
+func (settingsGradleFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	dir := path.Dir(settings.Path)
+	settingsGradle := &SettingsGradle{}
+	if err := unmarshalSettingsGradle([]byte(content), settingsGradle); err != nil {
+		// settings plugins often keep the version in gradle.properties
+		return readVersionFile(ghContentProvider, path.Join(dir, "gradle.properties"))
+	}
+	if settingsGradle.VersionFile != "" {
+		return readVersionFile(ghContentProvider, path.Join(dir, settingsGradle.VersionFile))
+	}
+	return settingsGradle.Version, nil
+}
+
+func (settingsGradleFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	return settingsGradleFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: "settings.gradle"})
+}
@@ -0,0 +1,43 @@
+package settingsgradle
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+func TestSettingsGradleFetcher(t *testing.T) {
+	var tests = []struct {
+		files   provider.MockFilesContentProvider
+		version string
+		err     error
+	}{
+		{provider.MockFilesContentProvider{"build/settings.gradle": `
+rootProject.name = 'atc'
+gradle.allprojects {
+    version = '1.2.3'
+}`}, "1.2.3", nil},
+		{provider.MockFilesContentProvider{
+			"build/settings.gradle": `rootProject.name = "atc"
+versionFile("version.txt")`,
+			"build/version.txt": "2.0.0\n"}, "2.0.0", nil},
+		{provider.MockFilesContentProvider{
+			"build/settings.gradle":   `rootProject.name = "atc"`,
+			"build/gradle.properties": "group=io.smartforce\nversion=3.1.0\n"}, "3.1.0", nil},
+		{provider.MockFilesContentProvider{
+			"build/settings.gradle":   `rootProject.name = "atc"`,
+			"build/gradle.properties": "group=io.smartforce\n"}, "", fetcher.ErrNoVers},
+	}
+	for _, test := range tests {
+		vers, err := (&Fetcher{}).GetVersion(test.files, settings.AtcSettings{Path: "build/settings.gradle"})
+		if !errors.Is(err, test.err) {
+			t.Errorf("Unexpected error %v, wanted %v", err, test.err)
+		}
+		if vers != test.version {
+			t.Errorf("wrong version! Got %q, wanted %q", vers, test.version)
+		}
+	}
+}
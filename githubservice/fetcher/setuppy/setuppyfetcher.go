@@ -0,0 +1,46 @@
+package setuppy
+
+import (
+	"regexp"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+)
+
+type SetupPy struct {
+	Version string
+}
+
+// Fetcher reads the `version="1.2.3"` keyword argument of setup() in a
+// Python setup.py. A version computed at build time can't be read.
+type Fetcher struct {
+}
+
+var versionArgRegex = regexp.MustCompile(`\bversion\s*=\s*["']([^"']+)["']`)
+
+var unmarshalSetupPy = func(content []byte, setupPyPtr *SetupPy) error {
+	res := versionArgRegex.FindSubmatch(content)
+	if len(res) != 2 {
+		return fetcher.ErrNoVers
+	}
+	setupPyPtr.Version = string(res[1])
+	return nil
+}
+
+func (setupPyFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	setupPy := &SetupPy{}
+	if err := unmarshalSetupPy([]byte(content), setupPy); err != nil {
+		return "", err
+	}
+	return setupPy.Version, nil
+}
+
+func (setupPyFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	return setupPyFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: "setup.py"})
+}
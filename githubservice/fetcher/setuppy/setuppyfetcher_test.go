@@ -0,0 +1,42 @@
+package setuppy
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+)
+
+var basicSetup = `from setuptools import setup, find_packages
+
+__version__ = "0.0.1"
+
+setup(
+    name="atc-example",
+    version="2.3.1",
+    packages=find_packages(),
+    python_requires=">=3.8",
+)
+`
+
+func TestSetupPyFetcher(t *testing.T) {
+	var tests = []struct {
+		content string
+		version string
+		err     error
+	}{
+		{basicSetup, "2.3.1", nil},
+		{"setup(name='x', version = '1.0.0rc1')", "1.0.0rc1", nil},
+		{"setup(name='x', version=__version__)", "", fetcher.ErrNoVers},
+	}
+	for _, test := range tests {
+		cp := provider.MockContentProvider{Content: test.content}
+		vers, err := (&Fetcher{}).GetVersionUsingDefaultPath(&cp)
+		if err != test.err {
+			t.Errorf("expected err %v, got %v", test.err, err)
+		}
+		if vers != test.version {
+			t.Errorf("expected %q, got %q", test.version, vers)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package terraform
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+)
+
+type Variables struct {
+	ModuleVersion string
+}
+
+// Fetcher reads the default of the `module_version` variable of a Terraform
+// module's variables.tf:
+//
+//	variable "module_version" {
+//	  default = "1.2.3"
+//	}
+//
+// The block is found with regexes, the HCL library isn't a dependency.
+type Fetcher struct {
+}
+
+var (
+	variableBlockRegex = regexp.MustCompile(`variable\s+"module_version"\s*\{`)
+	defaultRegex       = regexp.MustCompile(`(?m)^\s*default\s*=\s*"([^"]+)"`)
+)
+
+// blockBody returns the content up to the brace that closes the block
+// opened before it, skipping quoted strings.
+func blockBody(content string) string {
+	depth := 1
+	inString := false
+	for i := 0; i < len(content); i++ {
+		switch c := content[i]; {
+		case inString && c == '\\':
+			i++
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				return content[:i]
+			}
+		}
+	}
+	return content
+}
+
+var unmarshalVariables = func(content []byte, variablesPtr *Variables) error {
+	loc := variableBlockRegex.FindIndex(content)
+	if loc == nil {
+		return fetcher.ErrNoVers
+	}
+	body := blockBody(string(content[loc[1]:]))
+	// nested blocks like validation {} can't have a default
+	res := defaultRegex.FindStringSubmatch(body)
+	if len(res) != 2 || strings.TrimSpace(res[1]) == "" {
+		return fetcher.ErrNoVers
+	}
+	variablesPtr.ModuleVersion = res[1]
+	return nil
+}
+
+func (terraformFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	variables := &Variables{}
+	if err := unmarshalVariables([]byte(content), variables); err != nil {
+		return "", err
+	}
+	return variables.ModuleVersion, nil
+}
+
+func (terraformFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	return terraformFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: "variables.tf"})
+}
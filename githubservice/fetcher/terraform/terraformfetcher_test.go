@@ -0,0 +1,48 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+)
+
+var basicVariables = `variable "region" {
+  type    = string
+  default = "eu-west-1"
+}
+
+variable "module_version" {
+  description = "Version of the module, see {{docs}}"
+  type        = string
+  default     = "1.4.0"
+
+  validation {
+    condition     = can(regex("^\\d+\\.\\d+\\.\\d+$", var.module_version))
+    error_message = "Must be semver."
+  }
+}
+`
+
+func TestTerraformFetcher(t *testing.T) {
+	var tests = []struct {
+		content string
+		version string
+		err     error
+	}{
+		{basicVariables, "1.4.0", nil},
+		{`variable "module_version" {default="2.0.0"}`, "2.0.0", nil},
+		{"variable \"module_version\" {\n  type = string\n}\nvariable \"x\" {\n  default = \"9.9.9\"\n}\n", "", fetcher.ErrNoVers},
+		{"variable \"region\" {\n  default = \"eu-west-1\"\n}\n", "", fetcher.ErrNoVers},
+	}
+	for _, test := range tests {
+		cp := provider.MockContentProvider{Content: test.content}
+		vers, err := (&Fetcher{}).GetVersionUsingDefaultPath(&cp)
+		if err != test.err {
+			t.Errorf("expected err %v, got %v", test.err, err)
+		}
+		if vers != test.version {
+			t.Errorf("expected %q, got %q", test.version, vers)
+		}
+	}
+}
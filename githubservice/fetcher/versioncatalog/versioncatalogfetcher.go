@@ -0,0 +1,90 @@
+package versioncatalog
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+)
+
+const (
+	DefaultPath = "gradle/libs.versions.toml"
+
+	DefaultVersionKey = "version"
+	// AndroidVersionKey is the key Android projects keep the app version in,
+	// older ones use "versionName".
+	AndroidVersionKey     = "appVersion"
+	androidVersionNameKey = "versionName"
+)
+
+// VersionCatalog holds the string entries of the [versions] table.
+type VersionCatalog struct {
+	Versions map[string]string
+}
+
+// Fetcher reads the VersionKey entry of the [versions] table of a Gradle
+// version catalog, e.g. `version = "1.2.3"`.
+type Fetcher struct {
+	VersionKey  string
+	fallbackKey string
+}
+
+// NewAndroidFetcher returns a Fetcher of `appVersion`, or `versionName` when
+// there is no `appVersion`.
+func NewAndroidFetcher() *Fetcher {
+	return &Fetcher{VersionKey: AndroidVersionKey, fallbackKey: androidVersionNameKey}
+}
+
+var (
+	tableRegex = regexp.MustCompile(`^\s*\[([^\]]+)\]`)
+	entryRegex = regexp.MustCompile(`^\s*"?([\w.-]+)"?\s*=\s*"([^"]*)"`)
+)
+
+var unmarshalVersionCatalog = func(content []byte, versionCatalogPtr *VersionCatalog) error {
+	versionCatalogPtr.Versions = map[string]string{}
+	inVersions := false
+	for _, line := range strings.Split(string(content), "\n") {
+		if res := tableRegex.FindStringSubmatch(line); res != nil {
+			inVersions = strings.TrimSpace(res[1]) == "versions"
+			continue
+		}
+		if !inVersions {
+			continue
+		}
+		if res := entryRegex.FindStringSubmatch(line); res != nil {
+			versionCatalogPtr.Versions[res[1]] = res[2]
+		}
+	}
+	return nil
+}
+
+func (versionCatalogFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	catalog := &VersionCatalog{}
+	if err := unmarshalVersionCatalog([]byte(content), catalog); err != nil {
+		return "", err
+	}
+	key := versionCatalogFetcher.VersionKey
+	if key == "" {
+		key = DefaultVersionKey
+	}
+	if version := catalog.Versions[key]; version != "" {
+		return version, nil
+	}
+	if versionCatalogFetcher.fallbackKey != "" {
+		if version := catalog.Versions[versionCatalogFetcher.fallbackKey]; version != "" {
+			return version, nil
+		}
+	}
+	return "", fetcher.ErrNoVers
+}
+
+func (versionCatalogFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	return versionCatalogFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: DefaultPath})
+}
@@ -0,0 +1,47 @@
+package versioncatalog
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+)
+
+var androidCatalog = `[versions]
+agp = "8.2.2"
+kotlin = "1.9.22"
+appVersion = "3.4.0"
+compose-bom = { strictly = "2024.02.00" }
+
+[libraries]
+androidx-core = { group = "androidx.core", name = "core-ktx", version = "1.12.0" }
+
+[plugins]
+android-application = { id = "com.android.application", version.ref = "agp" }
+`
+
+func TestVersionCatalogFetcher(t *testing.T) {
+	var tests = []struct {
+		fetcher *Fetcher
+		content string
+		version string
+		err     error
+	}{
+		{NewAndroidFetcher(), androidCatalog, "3.4.0", nil},
+		{NewAndroidFetcher(), "[versions]\nversionName = \"2.0.1\"\n", "2.0.1", nil},
+		{NewAndroidFetcher(), "[versions]\nagp = \"8.2.2\"\n[libraries]\nappVersion = \"1.0\"\n", "", fetcher.ErrNoVers},
+		{&Fetcher{}, "[versions]\nversion = \"1.1.0\"\n", "1.1.0", nil},
+		{&Fetcher{VersionKey: "kotlin"}, androidCatalog, "1.9.22", nil},
+		{&Fetcher{}, androidCatalog, "", fetcher.ErrNoVers},
+	}
+	for _, test := range tests {
+		cp := provider.MockContentProvider{Content: test.content}
+		vers, err := test.fetcher.GetVersionUsingDefaultPath(&cp)
+		if err != test.err {
+			t.Errorf("expected err %v, got %v", test.err, err)
+		}
+		if vers != test.version {
+			t.Errorf("expected %q, got %q", test.version, vers)
+		}
+	}
+}
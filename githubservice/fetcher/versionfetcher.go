@@ -11,6 +11,9 @@ var (
 	ErrNoVers        = errors.New("empty number version")
 	ErrNoGroupInConf = errors.New("regexStr don't have group")
 	ErrParsRegex     = errors.New("pasre regexStr error")
+	// ErrVersionUnchanged means the version isn't kept in the file, e.g. it's
+	// read from the git tags, so there is nothing to tag.
+	ErrVersionUnchanged = errors.New("version isn't taken from the file")
 )
 
 type VersionFetcher interface {
@@ -0,0 +1,51 @@
+package pubspeclock
+
+import (
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+	"gopkg.in/yaml.v2"
+)
+
+type LockedPackage struct {
+	Dependency string `yaml:"dependency"`
+	Source     string `yaml:"source"`
+	Version    string `yaml:"version"`
+}
+
+type PubspecLock struct {
+	Packages map[string]LockedPackage `yaml:"packages"`
+}
+
+// Fetcher reads the resolved version of the package
+// AtcSettings.LockPackageName from a Dart pubspec.lock.
+type Fetcher struct {
+}
+
+var unmarshalPubspecLock = func(content []byte, pubspecLockPtr *PubspecLock) error {
+	return yaml.Unmarshal(content, pubspecLockPtr)
+}
+
+func (pubspecLockFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	if settings.LockPackageName == "" {
+		return "", fetcher.ErrNoVers
+	}
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	lock := &PubspecLock{}
+	if err := unmarshalPubspecLock([]byte(content), lock); err != nil {
+		return "", err
+	}
+	if pkg, ok := lock.Packages[settings.LockPackageName]; ok && pkg.Version != "" {
+		return pkg.Version, nil
+	}
+	return "", fetcher.ErrNoVers
+}
+
+// GetVersionUsingDefaultPath always fails, the package to read has to be
+// configured.
+func (pubspecLockFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	return "", fetcher.ErrNoVers
+}
@@ -0,0 +1,54 @@
+package pubspeclock
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+var basicPubspecLock = `# Generated by pub
+# See https://dart.dev/tools/pub/glossary#lockfile
+packages:
+  http:
+    dependency: "direct main"
+    description:
+      name: http
+      sha256: "759d1a329847dd0f39226c688d3e06a6b8679668e350e2891a6474f8b4bb8525"
+      url: "https://pub.dev"
+    source: hosted
+    version: "1.1.0"
+  path:
+    dependency: transitive
+    description:
+      name: path
+      url: "https://pub.dev"
+    source: hosted
+    version: "1.8.3"
+sdks:
+  dart: ">=3.0.0 <4.0.0"
+`
+
+func TestPubspecLockFetcher(t *testing.T) {
+	var tests = []struct {
+		packageName string
+		version     string
+		err         error
+	}{
+		{"http", "1.1.0", nil},
+		{"path", "1.8.3", nil},
+		{"dio", "", fetcher.ErrNoVers},
+		{"", "", fetcher.ErrNoVers},
+	}
+	cp := provider.MockContentProvider{Content: basicPubspecLock}
+	for _, test := range tests {
+		vers, err := (&Fetcher{}).GetVersion(&cp, settings.AtcSettings{Path: "pubspec.lock", LockPackageName: test.packageName})
+		if err != test.err {
+			t.Errorf("%q: expected err %v, got %v", test.packageName, test.err, err)
+		}
+		if vers != test.version {
+			t.Errorf("%q: expected %q, got %q", test.packageName, test.version, vers)
+		}
+	}
+}
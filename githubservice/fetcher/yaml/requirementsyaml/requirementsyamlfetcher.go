@@ -0,0 +1,50 @@
+package requirementsyaml
+
+import (
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+	"gopkg.in/yaml.v2"
+)
+
+type Dependency struct {
+	Name       string `yaml:"name"`
+	Version    string `yaml:"version"`
+	Repository string `yaml:"repository"`
+}
+
+type RequirementsYaml struct {
+	Dependencies []Dependency `yaml:"dependencies"`
+}
+
+// Fetcher reads the version of the dependency AtcSettings.DependencyName from
+// a Helm 2 requirements.yaml.
+type Fetcher struct {
+}
+
+var unmarshalRequirementsYaml = func(content []byte, requirementsYamlPtr *RequirementsYaml) error {
+	return yaml.Unmarshal(content, requirementsYamlPtr)
+}
+
+func (requirementsYamlFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	requirements := &RequirementsYaml{}
+	if err := unmarshalRequirementsYaml([]byte(content), requirements); err != nil {
+		return "", err
+	}
+	for _, dependency := range requirements.Dependencies {
+		if dependency.Name == settings.DependencyName && dependency.Version != "" {
+			return dependency.Version, nil
+		}
+	}
+	return "", fetcher.ErrNoVers
+}
+
+// GetVersionUsingDefaultPath always fails, the dependency to read has to be
+// configured.
+func (requirementsYamlFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	return "", fetcher.ErrNoVers
+}
@@ -0,0 +1,53 @@
+package requirementsyaml
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+var basicRequirementsYaml = `
+dependencies:
+  - name: postgresql
+    version: 8.6.4
+    repository: https://kubernetes-charts.storage.googleapis.com/
+  - name: atc-backend
+    version: "1.4.2"
+    repository: "@smartforce"
+`
+
+func TestRequirementsYamlFetcher(t *testing.T) {
+	var tests = []struct {
+		dependencyName string
+		version        string
+		err            error
+	}{
+		{"atc-backend", "1.4.2", nil},
+		{"postgresql", "8.6.4", nil},
+		{"redis", "", fetcher.ErrNoVers},
+		{"", "", fetcher.ErrNoVers},
+	}
+	cp := provider.MockContentProvider{Content: basicRequirementsYaml}
+	for _, test := range tests {
+		vers, err := (&Fetcher{}).GetVersion(&cp, settings.AtcSettings{Path: "chart/requirements.yaml", DependencyName: test.dependencyName})
+		if err != test.err {
+			t.Errorf("dependency %q: expected err %v, got %v", test.dependencyName, test.err, err)
+		}
+		if vers != test.version {
+			t.Errorf("dependency %q: expected %q, got %q", test.dependencyName, test.version, vers)
+		}
+	}
+}
+
+func TestRequirementsYamlFetcherErrors(t *testing.T) {
+	cp := provider.MockContentProvider{Content: "dependencies: {"}
+	if _, err := (&Fetcher{}).GetVersion(&cp, settings.AtcSettings{Path: "requirements.yaml", DependencyName: "atc"}); err == nil {
+		t.Errorf("expected unmarshal error")
+	}
+	cp = provider.MockContentProvider{Content: basicRequirementsYaml}
+	if _, err := (&Fetcher{}).GetVersionUsingDefaultPath(&cp); err != fetcher.ErrNoVers {
+		t.Errorf("expected %v, got %v", fetcher.ErrNoVers, err)
+	}
+}
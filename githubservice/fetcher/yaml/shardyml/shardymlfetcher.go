@@ -0,0 +1,49 @@
+package shardyml
+
+import (
+	"errors"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+	"gopkg.in/yaml.v2"
+)
+
+var ErrNotShard = errors.New("shard.yml has no authors, it isn't a Crystal shard")
+
+type ShardYml struct {
+	Name    string   `yaml:"name"`
+	Version string   `yaml:"version"`
+	Authors []string `yaml:"authors"`
+}
+
+// Fetcher reads the top-level version of a Crystal shard.yml. The file must
+// have `authors:`, which every shard has, to not read other YAML files.
+type Fetcher struct {
+}
+
+var unmarshalShardYml = func(content []byte, shardYmlPtr *ShardYml) error {
+	return yaml.Unmarshal(content, shardYmlPtr)
+}
+
+func (shardYmlFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	shard := &ShardYml{}
+	if err := unmarshalShardYml([]byte(content), shard); err != nil {
+		return "", err
+	}
+	if shard.Authors == nil {
+		return "", ErrNotShard
+	}
+	if shard.Version == "" {
+		return "", fetcher.ErrNoVers
+	}
+	return shard.Version, nil
+}
+
+func (shardYmlFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	return shardYmlFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: "shard.yml"})
+}
@@ -0,0 +1,45 @@
+package shardyml
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+)
+
+var basicShardYml = `name: atc-example
+version: 0.7.1
+
+authors:
+  - Jane Doe <jane@example.com>
+
+dependencies:
+  kemal:
+    github: kemalcr/kemal
+    version: ~> 1.4.0
+
+crystal: ">= 1.10.0"
+license: MIT
+`
+
+func TestShardYmlFetcher(t *testing.T) {
+	var tests = []struct {
+		content string
+		version string
+		err     error
+	}{
+		{basicShardYml, "0.7.1", nil},
+		{"name: x\nauthors: []\n", "", fetcher.ErrNoVers},
+		{"name: x\nversion: 1.0.0\n", "", ErrNotShard},
+	}
+	for _, test := range tests {
+		cp := provider.MockContentProvider{Content: test.content}
+		vers, err := (&Fetcher{}).GetVersionUsingDefaultPath(&cp)
+		if err != test.err {
+			t.Errorf("expected err %v, got %v", test.err, err)
+		}
+		if vers != test.version {
+			t.Errorf("expected %q, got %q", test.version, vers)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package yamlpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+	"gopkg.in/yaml.v2"
+)
+
+// Fetcher reads the version from any YAML file at the dot-separated key path
+// in AtcSettings.YAMLPath, e.g. "spec.appVersion". Numeric segments index
+// into lists.
+type Fetcher struct {
+}
+
+var unmarshalYaml = func(content []byte, out *interface{}) error {
+	return yaml.Unmarshal(content, out)
+}
+
+func lookup(node interface{}, keyPath string) (interface{}, bool) {
+	for _, key := range strings.Split(keyPath, ".") {
+		switch n := node.(type) {
+		case map[interface{}]interface{}:
+			v, ok := n[key]
+			if !ok {
+				return nil, false
+			}
+			node = v
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(n) {
+				return nil, false
+			}
+			node = n[i]
+		default:
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+func (yamlPathFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	var doc interface{}
+	if err := unmarshalYaml([]byte(content), &doc); err != nil {
+		return "", err
+	}
+	value, ok := lookup(doc, settings.YAMLPath)
+	if !ok || value == nil {
+		return "", fetcher.ErrNoVers
+	}
+	switch value.(type) {
+	case map[interface{}]interface{}, []interface{}:
+		return "", fetcher.ErrNoVers
+	}
+	return fmt.Sprint(value), nil
+}
+
+// GetVersionUsingDefaultPath always fails, the fetcher needs a configured
+// path and key path.
+func (yamlPathFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	return "", fetcher.ErrNoVers
+}
@@ -0,0 +1,61 @@
+package yamlpath
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+var chartYaml = `
+apiVersion: v2
+name: atc
+version: 0.3.1
+appVersion: "1.16.0"
+metadata:
+  labels:
+    - version: 2.0.0
+spec:
+  appVersion: 1.2.3
+  replicas: 3
+`
+
+func TestYamlPathFetcher(t *testing.T) {
+	var tests = []struct {
+		yamlPath string
+		version  string
+		err      error
+	}{
+		{"version", "0.3.1", nil},
+		{"appVersion", "1.16.0", nil},
+		{"spec.appVersion", "1.2.3", nil},
+		{"spec.replicas", "3", nil},
+		{"metadata.labels.0.version", "2.0.0", nil},
+		{"metadata.labels.1.version", "", fetcher.ErrNoVers},
+		{"spec", "", fetcher.ErrNoVers},
+		{"spec.missing", "", fetcher.ErrNoVers},
+		{"version.major", "", fetcher.ErrNoVers},
+	}
+	cp := provider.MockContentProvider{Content: chartYaml}
+	for _, test := range tests {
+		vers, err := (&Fetcher{}).GetVersion(&cp, settings.AtcSettings{Path: "charts/atc/Chart.yaml", YAMLPath: test.yamlPath})
+		if err != test.err {
+			t.Errorf("yaml_path %q: expected err %v, got %v", test.yamlPath, test.err, err)
+		}
+		if vers != test.version {
+			t.Errorf("yaml_path %q: expected %q, got %q", test.yamlPath, test.version, vers)
+		}
+	}
+}
+
+func TestYamlPathFetcherErrors(t *testing.T) {
+	cp := provider.MockContentProvider{Err: provider.ErrGeneral}
+	if _, err := (&Fetcher{}).GetVersion(&cp, settings.AtcSettings{Path: "Chart.yaml", YAMLPath: "version"}); err != provider.ErrGeneral {
+		t.Errorf("expected %v, got %v", provider.ErrGeneral, err)
+	}
+	cp = provider.MockContentProvider{Content: "version: [1"}
+	if _, err := (&Fetcher{}).GetVersion(&cp, settings.AtcSettings{Path: "Chart.yaml", YAMLPath: "version"}); err == nil {
+		t.Errorf("expected unmarshal error")
+	}
+}
@@ -0,0 +1,47 @@
+package zigzon
+
+import (
+	"regexp"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+)
+
+type ZigZon struct {
+	Version string
+}
+
+// Fetcher reads the package version from the `.version = "..."` field of a
+// Zig build.zig.zon.
+type Fetcher struct {
+}
+
+// the field has to start the line so `.minimum_zig_version` isn't matched
+var versionFieldRegex = regexp.MustCompile(`(?m)^\s*\.version\s*=\s*"([^"]+)"`)
+
+var unmarshalZigZon = func(content []byte, zigZonPtr *ZigZon) error {
+	res := versionFieldRegex.FindSubmatch(content)
+	if len(res) != 2 {
+		return fetcher.ErrNoVers
+	}
+	zigZonPtr.Version = string(res[1])
+	return nil
+}
+
+func (zigZonFetcher *Fetcher) GetVersion(ghContentProvider provider.ContentProvider, settings settings.AtcSettings) (string, error) {
+	content, err := ghContentProvider.GetContents(settings.Path)
+	if err != nil {
+		return "", err
+	}
+	zigZon := &ZigZon{}
+	if err := unmarshalZigZon([]byte(content), zigZon); err != nil {
+		return "", err
+	}
+	return zigZon.Version, nil
+}
+
+func (zigZonFetcher *Fetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	return zigZonFetcher.GetVersion(ghContentProvider, settings.AtcSettings{Path: "build.zig.zon"})
+}
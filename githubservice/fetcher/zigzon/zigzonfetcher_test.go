@@ -0,0 +1,44 @@
+package zigzon
+
+import (
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+)
+
+var basicZigZon = `.{
+    .name = "atc-example",
+    .version = "0.3.1",
+    .minimum_zig_version = "0.12.0",
+    .dependencies = .{
+        .zap = .{
+            .url = "https://github.com/zigzap/zap/archive/refs/tags/v0.6.0.tar.gz",
+            .hash = "1220a645e8ae84064f3342609f65d1c97e23c292616f5d1040cce2d1aa08a4d8f8",
+        },
+    },
+    .paths = .{""},
+}
+`
+
+func TestZigZonFetcher(t *testing.T) {
+	var tests = []struct {
+		content string
+		version string
+		err     error
+	}{
+		{basicZigZon, "0.3.1", nil},
+		{`.{ .name = "x", .minimum_zig_version = "0.12.0" }`, "", fetcher.ErrNoVers},
+		{".{\n\t.version=\"1.0.0-dev\",\n}", "1.0.0-dev", nil},
+	}
+	for _, test := range tests {
+		cp := provider.MockContentProvider{Content: test.content}
+		vers, err := (&Fetcher{}).GetVersionUsingDefaultPath(&cp)
+		if err != test.err {
+			t.Errorf("expected err %v, got %v", test.err, err)
+		}
+		if vers != test.version {
+			t.Errorf("expected %q, got %q", test.version, vers)
+		}
+	}
+}
@@ -3,26 +3,38 @@ package gitutil
 import (
 	"context"
 	"errors"
-	"log"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/google/go-github/v39/github"
+
+	"github.com/smartforce-io/atc/logger"
 )
 
 var (
 	errCreateTagWrongStatus = errors.New("wrong status for create a tag")
 	errCreateRefWrongStatus = errors.New("wrong status for create a ref")
+	errCreateReleaseStatus  = errors.New("wrong status for create a release")
+
+	ErrNotInstalled = errors.New("app isn't installed in repository")
 )
 
 func AddComment(client *github.Client, owner, repo, sha, text string) {
 	if _, _, err := client.Repositories.CreateComment(context.Background(), owner, repo, sha, &github.RepositoryComment{
 		Body: &text,
 	}); err != nil {
-		log.Printf("add comment error for %s/%s: %v", owner, repo, err)
+		logger.Errorf("add comment error for %s/%s: %v", owner, repo, err)
 	}
 }
 
 func AddTagToCommit(client *github.Client, owner, repo string, tag *github.Tag) error {
+	return AddTagToCommitRef(client, owner, repo, tag, "refs/tags/%s")
+}
+
+// AddTagToCommitRef creates the tag object and points the ref refFormat,
+// formatted with the tag name, at it, e.g. "refs/environments/prod/%s".
+func AddTagToCommitRef(client *github.Client, owner, repo string, tag *github.Tag, refFormat string) error {
 	t, resp, err := client.Git.CreateTag(context.Background(), owner, repo, tag)
 	if err != nil {
 		return err
@@ -31,7 +43,7 @@ func AddTagToCommit(client *github.Client, owner, repo string, tag *github.Tag)
 		return errCreateTagWrongStatus
 	}
 
-	refs := "refs/tags/" + t.GetTag()
+	refs := fmt.Sprintf(refFormat, t.GetTag())
 	_, resp, err = client.Git.CreateRef(context.Background(), owner, repo, &github.Reference{
 		Ref: &refs,
 		Object: &github.GitObject{
@@ -46,3 +58,129 @@ func AddTagToCommit(client *github.Client, owner, repo string, tag *github.Tag)
 	}
 	return nil
 }
+
+// AddLightweightTagRef points the ref refFormat, formatted with tagName,
+// straight at the commit sha without a tag object.
+func AddLightweightTagRef(client *github.Client, owner, repo, tagName, sha, refFormat string) error {
+	ref := fmt.Sprintf(refFormat, tagName)
+	_, resp, err := client.Git.CreateRef(context.Background(), owner, repo, &github.Reference{
+		Ref:    &ref,
+		Object: &github.GitObject{SHA: &sha},
+	})
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return errCreateRefWrongStatus
+	}
+	return nil
+}
+
+// UpdateBranch points branch at sha, creating the branch if it doesn't exist.
+// An existing branch is only fast-forwarded.
+func UpdateBranch(client *github.Client, owner, repo, branch, sha string) error {
+	ref := "refs/heads/" + branch
+	_, resp, err := client.Git.GetRef(context.Background(), owner, repo, ref)
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+		return err
+	}
+	if err != nil {
+		_, _, err = client.Git.CreateRef(context.Background(), owner, repo, &github.Reference{
+			Ref:    &ref,
+			Object: &github.GitObject{SHA: &sha},
+		})
+		return err
+	}
+	_, _, err = client.Git.UpdateRef(context.Background(), owner, repo, &github.Reference{
+		Ref:    &ref,
+		Object: &github.GitObject{SHA: &sha},
+	}, false)
+	return err
+}
+
+// TagExistsOnCommit reports whether the tag tagName exists under refFormat,
+// e.g. "refs/tags/%s", and points at sha, either directly or through an
+// annotated tag object.
+func TagExistsOnCommit(client *github.Client, owner, repo, tagName, sha, refFormat string) (bool, error) {
+	ref := fmt.Sprintf(refFormat, tagName)
+	refs, _, err := client.Git.ListMatchingRefs(context.Background(), owner, repo, &github.ReferenceListOptions{Ref: ref})
+	if err != nil {
+		return false, err
+	}
+	for _, r := range refs {
+		if r.GetRef() != ref {
+			continue // matching-refs is a prefix match
+		}
+		target := r.GetObject().GetSHA()
+		if r.GetObject().GetType() == "tag" {
+			t, _, err := client.Git.GetTag(context.Background(), owner, repo, target)
+			if err != nil {
+				return false, err
+			}
+			target = t.GetObject().GetSHA()
+		}
+		if target == sha {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// RefExists reports whether the ref, e.g. "refs/tags/v1.0", exists.
+func RefExists(client *github.Client, owner, repo, ref string) (bool, error) {
+	refs, _, err := client.Git.ListMatchingRefs(context.Background(), owner, repo, &github.ReferenceListOptions{Ref: ref})
+	if err != nil {
+		return false, err
+	}
+	for _, r := range refs {
+		if r.GetRef() == ref { // matching-refs is a prefix match
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// AddCommitStatus posts a commit status with state, e.g. "success", for
+// statusContext to sha.
+func AddCommitStatus(client *github.Client, owner, repo, sha, state, statusContext, description string) error {
+	_, _, err := client.Repositories.CreateStatus(context.Background(), owner, repo, sha, &github.RepoStatus{
+		State:       &state,
+		Context:     &statusContext,
+		Description: &description,
+	})
+	return err
+}
+
+// CreateRelease publishes a GitHub Release for an existing tag.
+func CreateRelease(client *github.Client, owner, repo string, release *github.RepositoryRelease) error {
+	_, resp, err := client.Repositories.CreateRelease(context.Background(), owner, repo, release)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return errCreateReleaseStatus
+	}
+	return nil
+}
+
+// InstallationPermissions returns the permissions the app installation has
+// in owner/repo, e.g. {"admin": false, "maintain": false, "push": true}.
+func InstallationPermissions(client *github.Client, owner, repo string) (map[string]bool, error) {
+	fullname := owner + "/" + repo
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		repos, resp, err := client.Apps.ListRepos(context.Background(), opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range repos.Repositories {
+			if strings.EqualFold(r.GetFullName(), fullname) {
+				return r.GetPermissions(), nil
+			}
+		}
+		if resp.NextPage == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrNotInstalled, fullname)
+		}
+		opts.Page = resp.NextPage
+	}
+}
@@ -0,0 +1,90 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var ErrHttpStatusCode = errors.New("jira http status code error")
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// Client is a minimal JIRA Cloud REST API v3 client for managing project
+// versions.
+type Client struct {
+	BaseURL    string
+	Username   string
+	APIToken   string
+	HTTPClient *http.Client
+}
+
+type Version struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Project     string `json:"project,omitempty"`
+	Released    bool   `json:"released,omitempty"`
+	ReleaseDate string `json:"releaseDate,omitempty"`
+}
+
+func NewClient(baseURL, username, apiToken string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		Username:   username,
+		APIToken:   apiToken,
+		HTTPClient: httpClient,
+	}
+}
+
+func (c *Client) do(method, path string, body interface{}, out interface{}) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(method, c.BaseURL+path, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(c.Username, c.APIToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%w: %s %s: %d %s", ErrHttpStatusCode, method, path, resp.StatusCode, respBody)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(respBody, out)
+}
+
+// CreateVersion creates an unreleased version name in the project with key projectKey.
+func (c *Client) CreateVersion(projectKey, name string) (*Version, error) {
+	version := &Version{}
+	if err := c.do(http.MethodPost, "/rest/api/3/version", &Version{Name: name, Project: projectKey}, version); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// ReleaseVersion marks the version with id as released today.
+func (c *Client) ReleaseVersion(id string) error {
+	return c.do(http.MethodPut, "/rest/api/3/version/"+id, &Version{
+		Released:    true,
+		ReleaseDate: time.Now().Format("2006-01-02"),
+	}, nil)
+}
@@ -0,0 +1,51 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreateAndReleaseVersion(t *testing.T) {
+	var created, released Version
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, token, ok := r.BasicAuth(); !ok || user != "bot@example.com" || token != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/api/3/version":
+			json.NewDecoder(r.Body).Decode(&created)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "10001", "name": "v1.2.0"}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/rest/api/3/version/10001":
+			json.NewDecoder(r.Body).Decode(&released)
+			w.Write([]byte(`{"id": "10001", "released": true}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL+"/", "bot@example.com", "secret")
+	version, err := client.CreateVersion("ATC", "v1.2.0")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if version.ID != "10001" || created.Name != "v1.2.0" || created.Project != "ATC" {
+		t.Errorf("wrong version created: %+v, response: %+v", created, version)
+	}
+	if err := client.ReleaseVersion(version.ID); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if !released.Released || released.ReleaseDate == "" {
+		t.Errorf("version wasn't released: %+v", released)
+	}
+
+	client = NewClient(server.URL, "bot@example.com", "wrong")
+	if _, err := client.CreateVersion("ATC", "v1.2.0"); !errors.Is(err, ErrHttpStatusCode) {
+		t.Errorf("expected %v, got %v", ErrHttpStatusCode, err)
+	}
+}
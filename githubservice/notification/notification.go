@@ -0,0 +1,153 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/smartforce-io/atc/envvars"
+)
+
+var ErrHttpStatusCode = errors.New("notification http status code error")
+
+// Notification is the JSON body posted to the configured URL after a tag is
+// created.
+type Notification struct {
+	Repo    string `json:"repo"`
+	Tag     string `json:"tag"`
+	SHA     string `json:"sha"`
+	Version string `json:"version"`
+	Pusher  string `json:"pusher"`
+}
+
+// HeaderContent is the data header value templates are rendered with.
+type HeaderContent struct {
+	Notification
+	url string
+}
+
+// Token is the ATC_NOTIFY_TOKEN environment variable. It fails the template
+// unless the URL is allowed by ATC_NOTIFY_URLS, so a repository can't send
+// the token of the server to its own host.
+func (content HeaderContent) Token() (string, error) {
+	if !tokenAllowed(content.url) {
+		return "", fmt.Errorf("%s isn't sent to %s, it isn't in %s", envvars.NotifyToken, content.url, envvars.NotifyURLs)
+	}
+	return os.Getenv(envvars.NotifyToken), nil
+}
+
+// tokenAllowed reports whether url is one of ATC_NOTIFY_URLS or below one
+// of them.
+func tokenAllowed(url string) bool {
+	for _, allowed := range strings.Split(os.Getenv(envvars.NotifyURLs), ",") {
+		allowed = strings.TrimSpace(allowed)
+		if allowed == "" {
+			continue
+		}
+		if url == allowed || strings.HasPrefix(url, strings.TrimSuffix(allowed, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+func renderHeader(value string, content HeaderContent) (string, error) {
+	tmpl, err := template.New("header").Parse(value)
+	if err != nil {
+		return "", err
+	}
+	buf := new(bytes.Buffer)
+	if err := tmpl.Execute(buf, content); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Post sends n as JSON to url with headers, whose values may be templates.
+func Post(url string, headers map[string]string, n Notification) error {
+	rendered := make(map[string]string, len(headers))
+	content := HeaderContent{Notification: n, url: url}
+	for name, value := range headers {
+		var err error
+		if rendered[name], err = renderHeader(value, content); err != nil {
+			return fmt.Errorf("header %q: %v", name, err)
+		}
+	}
+	return postJSON(url, rendered, n)
+}
+
+const (
+	EventCreated = "created"
+	EventFailed  = "failed"
+)
+
+// Event is a created tag or a failed tagging, posted as JSON to generic
+// webhooks and as Text to chats.
+type Event struct {
+	Notification
+	Event string `json:"event"`
+	Error string `json:"error,omitempty"`
+}
+
+// Text is the chat message about e.
+func (e Event) Text() string {
+	if e.Event == EventFailed {
+		return fmt.Sprintf("Tag %s wasn't created in %s at %s: %s", e.Tag, e.Repo, shortSHA(e.SHA), e.Error)
+	}
+	return fmt.Sprintf("Tag %s created in %s at %s by %s", e.Tag, e.Repo, shortSHA(e.SHA), e.Pusher)
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// Slack posts e to a Slack incoming webhook.
+func Slack(url string, e Event) error {
+	return postJSON(url, nil, map[string]string{"text": e.Text()})
+}
+
+// Discord posts e to a Discord webhook.
+func Discord(url string, e Event) error {
+	return postJSON(url, nil, map[string]string{"content": e.Text()})
+}
+
+// Webhook posts e as JSON to url.
+func Webhook(url string, e Event) error {
+	return postJSON(url, nil, e)
+}
+
+func postJSON(url string, headers map[string]string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%w: POST %s: %d", ErrHttpStatusCode, url, resp.StatusCode)
+	}
+	return nil
+}
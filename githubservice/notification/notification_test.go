@@ -0,0 +1,112 @@
+package notification
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/smartforce-io/atc/envvars"
+)
+
+func TestPost(t *testing.T) {
+	var received Notification
+	var authorization, custom string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		custom = r.Header.Get("X-Atc-Tag")
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	os.Setenv(envvars.NotifyToken, "secret")
+	defer os.Unsetenv(envvars.NotifyToken)
+	t.Setenv(envvars.NotifyURLs, "https://hooks.example.com/atc,"+server.URL)
+
+	n := Notification{Repo: "Codertocat/Hello-World", Tag: "v5", SHA: "abc", Version: "5", Pusher: "Codertocat"}
+	err := Post(server.URL, map[string]string{"Authorization": "Bearer {{.Token}}", "X-Atc-Tag": "{{.Tag}}"}, n)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if received != n {
+		t.Errorf("expected %+v, got %+v", n, received)
+	}
+	if authorization != "Bearer secret" || custom != "v5" {
+		t.Errorf("wrong headers: %q, %q", authorization, custom)
+	}
+
+	t.Setenv(envvars.NotifyURLs, "https://hooks.example.com/atc")
+	authorization = ""
+	if err := Post(server.URL, map[string]string{"Authorization": "Bearer {{.Token}}"}, n); err == nil || authorization != "" {
+		t.Errorf("expected the token to be refused for %s, got %v, %q", server.URL, err, authorization)
+	}
+}
+
+func TestTokenAllowed(t *testing.T) {
+	t.Setenv(envvars.NotifyURLs, "https://hooks.example.com/atc/, https://ci.example.com")
+	var tests = []struct {
+		url      string
+		expected bool
+	}{
+		{"https://hooks.example.com/atc/", true},
+		{"https://hooks.example.com/atc/tags?x=1", true},
+		{"https://hooks.example.com/other", false},
+		{"https://ci.example.com", true},
+		{"https://ci.example.com/notify", true},
+		{"https://ci.example.com.attacker.io/notify", false},
+	}
+	for _, test := range tests {
+		if got := tokenAllowed(test.url); got != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.url, test.expected, got)
+		}
+	}
+}
+
+func TestPostErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	if err := Post(server.URL, nil, Notification{}); !errors.Is(err, ErrHttpStatusCode) {
+		t.Errorf("expected %v, got %v", ErrHttpStatusCode, err)
+	}
+	if err := Post(server.URL, map[string]string{"Authorization": "{{.Token"}, Notification{}); err == nil {
+		t.Errorf("expected header template error")
+	}
+}
+
+func TestChatAndWebhook(t *testing.T) {
+	var received map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = nil
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	n := Notification{Repo: "Codertocat/Hello-World", Tag: "v5", SHA: "0123456789abcdef", Version: "5", Pusher: "Codertocat"}
+	created := Event{Notification: n, Event: EventCreated}
+	failed := Event{Notification: n, Event: EventFailed, Error: "reference already exists"}
+	var tests = []struct {
+		send     func(string, Event) error
+		event    Event
+		key      string
+		expected string
+	}{
+		{Slack, created, "text", "Tag v5 created in Codertocat/Hello-World at 0123456 by Codertocat"},
+		{Discord, failed, "content", "Tag v5 wasn't created in Codertocat/Hello-World at 0123456: reference already exists"},
+		{Webhook, failed, "event", EventFailed},
+		{Webhook, failed, "error", "reference already exists"},
+		{Webhook, created, "tag", "v5"},
+	}
+	for _, test := range tests {
+		if err := test.send(server.URL, test.event); err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+		if received[test.key] != test.expected {
+			t.Errorf("%s: expected %q, got %q", test.key, test.expected, received[test.key])
+		}
+	}
+}
@@ -11,6 +11,8 @@ type ClientProvider interface {
 	Get(token string, ctx context.Context) *github.Client
 }
 
+// GithubClientProvider returns clients that retry the requests rejected by
+// a rate limit, see RetryTransport.
 type GithubClientProvider struct {
 }
 
@@ -19,5 +21,6 @@ func (githubClientProvider *GithubClientProvider) Get(token string, ctx context.
 		&oauth2.Token{AccessToken: token},
 	)
 	tc := oauth2.NewClient(ctx, ts)
+	tc.Transport = &RetryTransport{Base: tc.Transport}
 	return github.NewClient(tc)
 }
@@ -3,9 +3,14 @@ package provider
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"strings"
+	"sync"
 
 	"github.com/google/go-github/v39/github"
+
+	"github.com/smartforce-io/atc/logger"
 )
 
 type ContentProvider interface {
@@ -16,7 +21,17 @@ type RequestError struct {
 	StatusCode int
 }
 
-var ErrHttpStatusCode = errors.New("http status code error")
+var (
+	ErrHttpStatusCode  = errors.New("http status code error")
+	ErrNotAFile        = errors.New("path isn't a file")
+	ErrContentTooLarge = errors.New("file is too large")
+	ErrBinaryContent   = errors.New("file is binary")
+	ErrNoTreeProvider  = errors.New("content provider can't list files")
+)
+
+// MaxContentSize is the largest file GetContents decodes. Version files are
+// small, anything bigger is most likely a wrong path.
+const MaxContentSize = 1 << 20
 
 type GhContentProvider struct {
 	Owner    string
@@ -24,9 +39,36 @@ type GhContentProvider struct {
 	Ref      string
 	Ctx      context.Context
 	GhClient *github.Client
+
+	// contents are the files read so far by Ref and path, a file needed
+	// twice, e.g. build.gradle for the version and the templates, is fetched
+	// once.
+	contentsMu sync.Mutex
+	contents   map[string]string
 }
 
 func (ghcp *GhContentProvider) GetContents(path string) (string, error) {
+	key := ghcp.Ref + ":" + path
+	ghcp.contentsMu.Lock()
+	content, ok := ghcp.contents[key]
+	ghcp.contentsMu.Unlock()
+	if ok {
+		return content, nil
+	}
+	content, err := ghcp.getContents(path)
+	if err != nil {
+		return "", err
+	}
+	ghcp.contentsMu.Lock()
+	if ghcp.contents == nil {
+		ghcp.contents = map[string]string{}
+	}
+	ghcp.contents[key] = content
+	ghcp.contentsMu.Unlock()
+	return content, nil
+}
+
+func (ghcp *GhContentProvider) getContents(path string) (string, error) {
 
 	fileContent, _, response, err := ghcp.GhClient.Repositories.GetContents(ghcp.Ctx,
 		ghcp.Owner, ghcp.Repo, path,
@@ -35,11 +77,97 @@ func (ghcp *GhContentProvider) GetContents(path string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	content, _ := fileContent.GetContent()
-
 	if response.StatusCode != http.StatusOK {
-		return content, ErrHttpStatusCode
-	} else {
-		return content, nil
+		return "", ErrHttpStatusCode
+	}
+	if fileContent == nil {
+		return "", fmt.Errorf("%w: %s", ErrNotAFile, path)
+	}
+	if fileContent.GetSize() > MaxContentSize {
+		return "", fmt.Errorf("%w: %s is %d bytes", ErrContentTooLarge, path, fileContent.GetSize())
+	}
+	if encoding := fileContent.GetEncoding(); encoding != "base64" {
+		return "", fmt.Errorf("unsupported encoding: %s", encoding)
+	}
+	content, err := fileContent.GetContent()
+	if err != nil {
+		return "", err
+	}
+	if strings.ContainsRune(content, 0) {
+		return "", fmt.Errorf("%w: %s", ErrBinaryContent, path)
+	}
+	return content, nil
+}
+
+// TreeProvider is implemented by content providers that can list the files
+// of the repository.
+type TreeProvider interface {
+	ListFiles() ([]string, error)
+}
+
+// ListFiles returns the paths of all files in the Ref tree.
+func (ghcp *GhContentProvider) ListFiles() ([]string, error) {
+	ref := ghcp.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	tree, _, err := ghcp.GhClient.Git.GetTree(ghcp.Ctx, ghcp.Owner, ghcp.Repo, ref, true)
+	if err != nil {
+		return nil, err
+	}
+	if tree.GetTruncated() {
+		logger.Warnf("tree of %s/%s at %q is truncated", ghcp.Owner, ghcp.Repo, ref)
+	}
+	files := []string{}
+	for _, entry := range tree.Entries {
+		if entry.GetType() == "blob" {
+			files = append(files, entry.GetPath())
+		}
+	}
+	return files, nil
+}
+
+// ReleaseProvider is implemented by content providers that can read the
+// repository's GitHub Releases.
+type ReleaseProvider interface {
+	GetLatestRelease() (string, error)
+}
+
+// GetLatestRelease returns the tag name of the latest published release that
+// existed at the time of the Ref commit, or "" if there is none. Looking at
+// the commit date keeps the old and new refs of a push from seeing the same
+// release.
+func (ghcp *GhContentProvider) GetLatestRelease() (string, error) {
+	commit, _, err := ghcp.GhClient.Repositories.GetCommit(ghcp.Ctx, ghcp.Owner, ghcp.Repo, ghcp.Ref, nil)
+	if err != nil {
+		return "", err
+	}
+	commitDate := commit.GetCommit().GetCommitter().GetDate()
+
+	release, response, err := ghcp.GhClient.Repositories.GetLatestRelease(ghcp.Ctx, ghcp.Owner, ghcp.Repo)
+	if err != nil {
+		if response != nil && response.StatusCode == http.StatusNotFound {
+			return "", nil
+		}
+		return "", err
+	}
+	if !release.GetPublishedAt().After(commitDate) {
+		return release.GetTagName(), nil
+	}
+
+	// the latest release is newer than the commit, look for an older one
+	releases, _, err := ghcp.GhClient.Repositories.ListReleases(ghcp.Ctx, ghcp.Owner, ghcp.Repo, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return "", err
+	}
+	var latest *github.RepositoryRelease
+	for _, r := range releases {
+		if r.GetDraft() || r.GetPrerelease() || r.GetPublishedAt().After(commitDate) {
+			continue
+		}
+		if latest == nil || r.GetPublishedAt().After(latest.GetPublishedAt().Time) {
+			latest = r
+		}
 	}
+	return latest.GetTagName(), nil
 }
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestGhContentProviderGetContents(t *testing.T) {
+	var tests = []struct {
+		response string
+		content  string
+		err      error
+		errStr   string
+	}{
+		{MockContentResponse("path: pom.xml"), "path: pom.xml", nil, ""},
+		{`{"type": "file", "content": "path: pom.xml", "size": 13}`, "", nil, "unsupported encoding: "},
+		{`{"type": "file", "content": "", "size": 5242880, "encoding": "none"}`, "", ErrContentTooLarge, ""},
+		{MockContentResponse("\x89PNG\r\n\x1a\n\x00\x00"), "", ErrBinaryContent, ""},
+		{`[{"type": "file", "name": "pom.xml"}]`, "", ErrNotAFile, ""},
+	}
+	for _, test := range tests {
+		mockClientProviderPtr := DefaultMockClientProvider()
+		mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn RoundTripFunc) *http.Response {
+			return NewTestResponse(200, test.response)
+		})
+		cp := &GhContentProvider{
+			Owner:    "owner",
+			Repo:     "repo",
+			Ctx:      context.Background(),
+			GhClient: mockClientProviderPtr.Get("", context.Background()),
+		}
+		content, err := cp.GetContents(".atc.yaml")
+		if content != test.content {
+			t.Errorf("response %s: expected content %q, got %q", test.response, test.content, content)
+		}
+		switch {
+		case test.err != nil && !errors.Is(err, test.err):
+			t.Errorf("response %s: expected %v, got %v", test.response, test.err, err)
+		case test.errStr != "" && (err == nil || !strings.Contains(err.Error(), test.errStr)):
+			t.Errorf("response %s: expected %q, got %v", test.response, test.errStr, err)
+		case test.err == nil && test.errStr == "" && err != nil:
+			t.Errorf("response %s: unexpected error %v", test.response, err)
+		}
+	}
+}
+
+func TestGhContentProviderGetContentsOnce(t *testing.T) {
+	mockClientProviderPtr := DefaultMockClientProvider()
+	requests := 0
+	for _, action := range []string{"GET_OLD_VERSION_MAVEN", "GET_NEW_VERSION_MAVEN"} {
+		mockClientProviderPtr.OverrideResponseFn(action, func(req *http.Request, defaultFn RoundTripFunc) *http.Response {
+			requests++
+			return defaultFn(req)
+		})
+	}
+	cp := &GhContentProvider{
+		Owner:    "owner",
+		Repo:     "repo",
+		Ctx:      context.Background(),
+		GhClient: mockClientProviderPtr.Get("", context.Background()),
+	}
+	contents := map[string]string{}
+	for _, ref := range []string{"before", "main", "before", "main"} {
+		cp.Ref = ref
+		content, err := cp.GetContents("pom.xml")
+		if err != nil {
+			t.Fatalf("ref %q: unexpected error %v", ref, err)
+		}
+		if previous, ok := contents[ref]; ok && previous != content {
+			t.Errorf("ref %q: expected %q, got %q", ref, previous, content)
+		}
+		contents[ref] = content
+	}
+	if contents["before"] == contents["main"] {
+		t.Errorf("refs have the same content %q", contents["main"])
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests, got %d", requests)
+	}
+}
@@ -10,7 +10,9 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"path"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/google/go-github/v39/github"
@@ -98,12 +100,38 @@ func (mockContentProvider *MockContentProvider) GetContents(path string) (string
 	return mockContentProvider.Content, mockContentProvider.Err
 }
 
+// MockFilesContentProvider is a repository of files keyed by their paths.
+// A missing file fails with ErrHttpStatusCode like on GitHub.
+type MockFilesContentProvider map[string]string
+
+func (files MockFilesContentProvider) GetContents(path string) (string, error) {
+	content, ok := files[path]
+	if !ok {
+		return "", ErrHttpStatusCode
+	}
+	return content, nil
+}
+
+// ListFiles returns the paths sorted like in a git tree.
+func (files MockFilesContentProvider) ListFiles() ([]string, error) {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
 // RoundTripFunc
 type RoundTripFunc func(req *http.Request) *http.Response
 
 // RoundTrip
 func (f RoundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { //this is kind of wrapper where original function is used in interface implementation
-	return f(req), nil
+	resp := f(req)
+	if resp != nil && resp.Request == nil {
+		resp.Request = req // go-github reads it when formatting error responses
+	}
+	return resp, nil
 }
 
 // NewTestClient returns *http.Client with Transport replaced to avoid making real calls
@@ -158,6 +186,31 @@ func DefaultMockClientProvider() *MockClientProvider {
 				return NewTestResponse(201, tokenResponse)
 			},
 		},
+		"GET_APP": {
+			func(req *http.Request) bool {
+				return req.Method == http.MethodGet && req.URL.Path == "/app"
+			},
+			func(req *http.Request) *http.Response {
+				return NewTestResponse(200, `{"id": 1, "slug": "atc"}`)
+			},
+		},
+		"GET_APP_BOT_USER": {
+			func(req *http.Request) bool {
+				return req.Method == http.MethodGet && req.URL.Path == "/users/atc[bot]"
+			},
+			func(req *http.Request) *http.Response {
+				return NewTestResponse(200, `{"login": "atc[bot]", "id": 41898282, "type": "Bot"}`)
+			},
+		},
+		"GET_USER": {
+			func(req *http.Request) bool {
+				matched, _ := regexp.MatchString("^/users/[^/]+$", req.URL.Path)
+				return req.Method == http.MethodGet && matched && req.URL.Path != "/users/atc[bot]"
+			},
+			func(req *http.Request) *http.Response {
+				return NewTestResponse(200, fmt.Sprintf(`{"login": "%s", "id": 12345, "type": "User"}`, strings.TrimPrefix(req.URL.Path, "/users/")))
+			},
+		},
 		"GET_ATC_CONFIG": {
 			func(req *http.Request) bool {
 				return strings.HasSuffix(req.URL.String(), "atc.yaml")
@@ -288,7 +341,7 @@ func DefaultMockClientProvider() *MockClientProvider {
 		},
 		"ADD_TAG": {
 			func(req *http.Request) bool {
-				return strings.Contains(req.URL.String(), "/git/tags")
+				return req.Method == http.MethodPost && strings.Contains(req.URL.String(), "/git/tags")
 			},
 			func(req *http.Request) *http.Response {
 				log.Println("ADD_TAG req.Body: ", req.Body)
@@ -297,6 +350,22 @@ func DefaultMockClientProvider() *MockClientProvider {
 				return NewTestResponse(201, fmt.Sprintf(`{"tag":"%s", "sha":"940bd336248efae0f9ee5bc7b2d5c985887b16ac"}`, jsonMap["tag"]))
 			},
 		},
+		"GET_TAG": {
+			func(req *http.Request) bool {
+				return req.Method == http.MethodGet && strings.Contains(req.URL.String(), "/git/tags/")
+			},
+			func(req *http.Request) *http.Response {
+				return NewTestResponse(404, "not found")
+			},
+		},
+		"GET_MATCHING_REFS": {
+			func(req *http.Request) bool {
+				return strings.Contains(req.URL.String(), "/git/matching-refs/")
+			},
+			func(req *http.Request) *http.Response {
+				return NewTestResponse(200, `[]`)
+			},
+		},
 		"ADD_REF": {
 			func(req *http.Request) bool {
 				return strings.Contains(req.URL.String(), "/git/refs")
@@ -305,6 +374,137 @@ func DefaultMockClientProvider() *MockClientProvider {
 				return NewTestResponse(201, `{}`)
 			},
 		},
+		"GET_COMMIT": {
+			func(req *http.Request) bool {
+				matched, _ := regexp.MatchString("/commits/[^/]+$", req.URL.Path)
+				return req.Method == http.MethodGet && matched
+			},
+			func(req *http.Request) *http.Response {
+				date := "2021-06-01T00:00:00Z"
+				if strings.HasSuffix(req.URL.Path, "/main") {
+					date = "2022-06-01T00:00:00Z"
+				}
+				return NewTestResponse(200, fmt.Sprintf(`{"sha": "%s", "commit": {"committer": {"date": "%s"}}}`, path.Base(req.URL.Path), date))
+			},
+		},
+		"GET_COMBINED_STATUS": {
+			func(req *http.Request) bool {
+				return strings.HasSuffix(req.URL.Path, "/status")
+			},
+			func(req *http.Request) *http.Response {
+				return NewTestResponse(200, `{"state": "success", "statuses": []}`)
+			},
+		},
+		"GET_REPO": {
+			func(req *http.Request) bool {
+				matched, _ := regexp.MatchString("^/repos/[^/]+/[^/]+$", req.URL.Path)
+				return req.Method == http.MethodGet && matched
+			},
+			func(req *http.Request) *http.Response {
+				return NewTestResponse(200, fmt.Sprintf(`{"full_name": "%s", "default_branch": "main"}`, strings.TrimPrefix(req.URL.Path, "/repos/")))
+			},
+		},
+		"LIST_INSTALLATION_REPOS": {
+			func(req *http.Request) bool {
+				return req.Method == http.MethodGet && req.URL.Path == "/installation/repositories"
+			},
+			func(req *http.Request) *http.Response {
+				return NewTestResponse(200, `{"total_count": 1, "repositories": [{"full_name": "Codertocat/Hello-World", "permissions": {"admin": true, "push": true, "pull": true}}]}`)
+			},
+		},
+		"LIST_COMMIT_PULLS": {
+			func(req *http.Request) bool {
+				return req.Method == http.MethodGet && strings.HasSuffix(req.URL.Path, "/pulls") && strings.Contains(req.URL.Path, "/commits/")
+			},
+			func(req *http.Request) *http.Response {
+				return NewTestResponse(200, `[]`)
+			},
+		},
+		"LIST_PULL_FILES": {
+			func(req *http.Request) bool {
+				return req.Method == http.MethodGet && strings.Contains(req.URL.Path, "/pulls/") && strings.HasSuffix(req.URL.Path, "/files")
+			},
+			func(req *http.Request) *http.Response {
+				return NewTestResponse(200, `[]`)
+			},
+		},
+		"COMPARE_COMMITS": {
+			func(req *http.Request) bool {
+				return req.Method == http.MethodGet && strings.Contains(req.URL.Path, "/compare/")
+			},
+			func(req *http.Request) *http.Response {
+				return NewTestResponse(200, `{"commits": []}`)
+			},
+		},
+		"GET_TREE": {
+			func(req *http.Request) bool {
+				return req.Method == http.MethodGet && strings.Contains(req.URL.Path, "/git/trees/")
+			},
+			func(req *http.Request) *http.Response {
+				return NewTestResponse(200, `{"sha": "1234567890123456789012345678901234567890", "tree": [{"path": "pom.xml", "type": "blob"}], "truncated": false}`)
+			},
+		},
+		"GET_REPO_INSTALLATION": {
+			func(req *http.Request) bool {
+				matched, _ := regexp.MatchString("^/repos/[^/]+/[^/]+/installation$", req.URL.Path)
+				return req.Method == http.MethodGet && matched
+			},
+			func(req *http.Request) *http.Response {
+				return NewTestResponse(200, `{"id": 2}`)
+			},
+		},
+		"LIST_TAGS": {
+			func(req *http.Request) bool {
+				matched, _ := regexp.MatchString("^/repos/[^/]+/[^/]+/tags$", req.URL.Path)
+				return req.Method == http.MethodGet && matched
+			},
+			func(req *http.Request) *http.Response {
+				return NewTestResponse(200, `[]`)
+			},
+		},
+		"GET_BRANCH": {
+			func(req *http.Request) bool {
+				return req.Method == http.MethodGet && strings.Contains(req.URL.Path, "/branches/")
+			},
+			func(req *http.Request) *http.Response {
+				return NewTestResponse(200, `{"name": "main", "commit": {"sha": "1234567890123456789012345678901234567890",
+					"commit": {"author": {"name": "Codertocat", "email": "21031067+Codertocat@users.noreply.github.com"}}}}`)
+			},
+		},
+		"GET_LATEST_RELEASE": {
+			func(req *http.Request) bool {
+				return strings.HasSuffix(req.URL.Path, "/releases/latest")
+			},
+			func(req *http.Request) *http.Response {
+				return NewTestResponse(200, `{"tag_name": "v2.0.0", "published_at": "2022-01-01T00:00:00Z"}`)
+			},
+		},
+		"LIST_RELEASES": {
+			func(req *http.Request) bool {
+				return req.Method == http.MethodGet && strings.HasSuffix(req.URL.Path, "/releases")
+			},
+			func(req *http.Request) *http.Response {
+				return NewTestResponse(200, `[{"tag_name": "v2.0.0", "published_at": "2022-01-01T00:00:00Z"},
+					{"tag_name": "v1.1.0-rc1", "prerelease": true, "published_at": "2021-03-01T00:00:00Z"},
+					{"tag_name": "v1.0.0", "published_at": "2021-01-01T00:00:00Z"}]`)
+			},
+		},
+		"CREATE_RELEASE": {
+			func(req *http.Request) bool {
+				return req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/releases")
+			},
+			func(req *http.Request) *http.Response {
+				return NewTestResponse(201, `{"id": 1}`)
+			},
+		},
+		"ADD_STATUS": {
+			func(req *http.Request) bool {
+				return req.Method == http.MethodPost && strings.Contains(req.URL.Path, "/statuses/")
+			},
+			func(req *http.Request) *http.Response {
+				return NewTestResponse(201, `{}`)
+			},
+		},
 		"ADD_COMMENT": {
 			func(req *http.Request) bool {
 				matched, err := regexp.MatchString(".*/commits/(.{40})/comments", req.URL.String())
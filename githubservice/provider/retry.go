@@ -0,0 +1,154 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/smartforce-io/atc/logger"
+)
+
+const (
+	DefaultMaxRetries     = 3
+	DefaultRetryBaseDelay = time.Second
+	// DefaultRetryMaxDelay caps the wait before a retry, also the one asked
+	// for by Retry-After or X-RateLimit-Reset. Longer waits aren't retried.
+	DefaultRetryMaxDelay = time.Minute
+)
+
+// RetryTransport retries GitHub API requests rejected by a primary or
+// secondary rate limit or the abuse detection. It waits for Retry-After or
+// X-RateLimit-Reset when GitHub sends them, otherwise for an exponential
+// backoff with full jitter.
+type RetryTransport struct {
+	Base       http.RoundTripper
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+// retrySleep waits for d or until ctx is done.
+var retrySleep = func(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	maxRetries, baseDelay, maxDelay := t.MaxRetries, t.BaseDelay, t.MaxDelay
+	if maxRetries == 0 {
+		maxRetries = DefaultMaxRetries
+	}
+	if baseDelay == 0 {
+		baseDelay = DefaultRetryBaseDelay
+	}
+	if maxDelay == 0 {
+		maxDelay = DefaultRetryMaxDelay
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := base.RoundTrip(req)
+		if err != nil || attempt >= maxRetries || !isRateLimited(resp) {
+			return resp, err
+		}
+		// the body of a request can only be sent again if it can be rewound
+		if req.Body != nil && req.GetBody == nil {
+			return resp, nil
+		}
+		delay, ok := retryAfter(resp, time.Now())
+		if !ok {
+			delay = Backoff(attempt, baseDelay, maxDelay)
+		}
+		if delay > maxDelay {
+			return resp, nil
+		}
+		logger.Warnf("GitHub rate limit for %s %s, retry %d in %v", req.Method, req.URL.Path, attempt+1, delay)
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		if err := retrySleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// isRateLimited reports whether resp rejects the request for a rate limit.
+// GitHub answers 429, or 403 with a Retry-After header, an exhausted
+// X-RateLimit-Remaining or a "rate limit" or "abuse" message in the body.
+func isRateLimited(resp *http.Response) bool {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if resp.StatusCode != http.StatusForbidden {
+		return false
+	}
+	if resp.Header.Get("Retry-After") != "" || resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		return true
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	message := strings.ToLower(string(body))
+	return strings.Contains(message, "rate limit") || strings.Contains(message, "abuse")
+}
+
+// retryAfter returns the wait asked for by the Retry-After header, in
+// seconds or as a date, or by X-RateLimit-Reset when the rate limit is
+// exhausted.
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if value := resp.Header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if date, err := http.ParseTime(value); err == nil {
+			return nonNegative(date.Sub(now)), true
+		}
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return nonNegative(time.Unix(reset, 0).Sub(now)), true
+		}
+	}
+	return 0, false
+}
+
+// Backoff returns a random delay up to baseDelay * 2^attempt, at most
+// maxDelay.
+func Backoff(attempt int, baseDelay, maxDelay time.Duration) time.Duration {
+	delay := maxDelay
+	if attempt < 30 && baseDelay<<attempt < maxDelay {
+		delay = baseDelay << attempt
+	}
+	return time.Duration(rand.Int63n(int64(delay)) + 1)
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
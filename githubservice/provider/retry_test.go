@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetryTransport(t *testing.T) {
+	defer func(sleep func(context.Context, time.Duration) error) { retrySleep = sleep }(retrySleep)
+	var tests = []struct {
+		name      string
+		status    int
+		header    map[string]string
+		body      string
+		delays    []time.Duration
+		requests  int
+		lastCode  int
+		postBody  bool
+		rewinding bool
+	}{
+		{"retry after", 403, map[string]string{"Retry-After": "7"}, `{}`, []time.Duration{7 * time.Second}, 2, 200, true, true},
+		{"secondary", 403, nil, `{"message": "You have exceeded a secondary rate limit."}`, nil, 2, 200, false, false},
+		{"too many", 429, map[string]string{"Retry-After": "1"}, `{}`, []time.Duration{time.Second}, 2, 200, false, false},
+		{"forbidden", 403, nil, `{"message": "Resource not accessible by integration"}`, nil, 1, 403, false, false},
+		{"long wait", 403, map[string]string{"Retry-After": "3600"}, `{}`, nil, 1, 403, false, false},
+		{"no rewind", 429, map[string]string{"Retry-After": "1"}, `{}`, nil, 1, 429, true, false},
+	}
+
+	for _, test := range tests {
+		requests := 0
+		var bodies []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			body, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			if requests > 1 {
+				w.Write([]byte(`{}`))
+				return
+			}
+			for k, v := range test.header {
+				w.Header().Set(k, v)
+			}
+			w.WriteHeader(test.status)
+			w.Write([]byte(test.body))
+		}))
+
+		var delays []time.Duration
+		retrySleep = func(ctx context.Context, d time.Duration) error {
+			delays = append(delays, d)
+			return nil
+		}
+
+		var req *http.Request
+		switch {
+		case test.postBody && test.rewinding:
+			req, _ = http.NewRequest("POST", server.URL, strings.NewReader(`{"tag": "v1"}`))
+		case test.postBody:
+			req, _ = http.NewRequest("POST", server.URL, io.NopCloser(strings.NewReader(`{"tag": "v1"}`)))
+		default:
+			req, _ = http.NewRequest("GET", server.URL, nil)
+		}
+		resp, err := (&http.Client{Transport: &RetryTransport{}}).Do(req)
+		server.Close()
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+			continue
+		}
+		resp.Body.Close()
+
+		if requests != test.requests || resp.StatusCode != test.lastCode {
+			t.Errorf("%s: expected %d requests and status %d, got %d and %d", test.name, test.requests, test.lastCode, requests, resp.StatusCode)
+		}
+		if test.delays != nil && (len(delays) != len(test.delays) || delays[0] != test.delays[0]) {
+			t.Errorf("%s: expected delays %v, got %v", test.name, test.delays, delays)
+		}
+		if len(delays) != test.requests-1 {
+			t.Errorf("%s: expected %d waits, got %v", test.name, test.requests-1, delays)
+		}
+		if test.postBody && test.rewinding && (len(bodies) != 2 || bodies[1] != `{"tag": "v1"}`) {
+			t.Errorf("%s: body isn't sent again: %q", test.name, bodies)
+		}
+	}
+}
+
+func TestRetryTransportMaxRetries(t *testing.T) {
+	defer func(sleep func(context.Context, time.Duration) error) { retrySleep = sleep }(retrySleep)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(429)
+	}))
+	defer server.Close()
+	retrySleep = func(ctx context.Context, d time.Duration) error { return nil }
+
+	resp, err := (&http.Client{Transport: &RetryTransport{MaxRetries: 2}}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	resp.Body.Close()
+	if requests != 3 || resp.StatusCode != 429 {
+		t.Errorf("expected 3 requests and status 429, got %d and %d", requests, resp.StatusCode)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt := 0; attempt < 40; attempt++ {
+		delay := Backoff(attempt, time.Second, time.Minute)
+		if delay <= 0 || delay > time.Minute || attempt < 5 && delay > time.Second<<attempt {
+			t.Errorf("attempt %d: wrong delay %v", attempt, delay)
+		}
+	}
+}
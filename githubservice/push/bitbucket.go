@@ -0,0 +1,108 @@
+package push
+
+import (
+	"fmt"
+
+	bbprovider "github.com/smartforce-io/atc/bitbucketservice/provider"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+	"github.com/smartforce-io/atc/logger"
+)
+
+// BitbucketActionPush tags the versions of the branches pushed in a
+// Bitbucket Cloud push webhook event with the .atc.yaml of the repository.
+// Failures are only logged, like for GitLab.
+func BitbucketActionPush(event *bbprovider.PushEvent, client *bbprovider.Client) {
+	fullName := event.Repository.FullName
+	mainBranch := ""
+	for _, change := range event.Push.Changes {
+		if change.New == nil || change.New.Type != "branch" {
+			continue
+		}
+		after := change.New.Target.Hash
+		atcs, err := settings.GetAtcSetting(&bbprovider.BbContentProvider{Client: client, FullName: fullName, Ref: after})
+		if err != nil {
+			logger.Errorf("settings error for %q: %v", fullName, err)
+			continue
+		}
+		if atcs.IsBot(event.Actor.Nickname) {
+			logger.Warnf("skip push of %q by bot %q", fullName, event.Actor.Nickname)
+			return
+		}
+
+		branch := change.New.Name
+		branchSettings := atcs.UseBranch(branch)
+		if channelName, channel := atcs.ChannelForBranch(branch); channel != nil {
+			logger.Debugf("branch %q of %q uses channel %q", branch, fullName, channelName)
+			atcs.UseChannel(channel)
+		} else if !branchSettings {
+			tagBranch := atcs.Branch
+			if tagBranch == "" {
+				if mainBranch == "" {
+					if mainBranch, err = client.GetMainBranch(fullName); err != nil {
+						logger.Errorf("main branch error for %q: %v", fullName, err)
+						return
+					}
+				}
+				tagBranch = mainBranch
+			}
+			if branch != tagBranch {
+				continue
+			}
+		}
+
+		before := ""
+		if change.Old != nil && change.Old.Type == "branch" {
+			before = change.Old.Target.Hash
+		}
+		for _, targetSettings := range atcs.TargetSettings() {
+			if err := tagBitbucketPush(client, fullName, before, after, targetSettings); err != nil {
+				logger.Errorf("tag error for %q: %v", fullName, err)
+			}
+		}
+	}
+}
+
+func tagBitbucketPush(client *bbprovider.Client, fullName, before, after string, atcs *settings.AtcSettings) error {
+	var bbOldContentProviderPtr provider.ContentProvider
+	if before != "" {
+		bbOldContentProviderPtr = &bbprovider.BbContentProvider{
+			Client:   client,
+			FullName: fullName,
+			Ref:      before,
+		}
+	}
+	bbNewContentProviderPtr := &bbprovider.BbContentProvider{
+		Client:   client,
+		FullName: fullName,
+		Ref:      after,
+	}
+
+	caption, err := fetch(atcs, bbOldContentProviderPtr, bbNewContentProviderPtr, fullName)
+	if err != nil {
+		return fmt.Errorf("fetch version error: %v", err)
+	}
+	if caption == "" {
+		logger.Debugf("Old and new versions are equal")
+		return nil
+	}
+
+	sha := after
+	if atcs.Behavior == settings.BehaviorBefore && before != "" {
+		sha = before
+	}
+	if atcs.DryRun {
+		logger.Infof("Dry run: tag %q would be added to %s of %q", caption, sha, fullName)
+		return nil
+	}
+	message := caption
+	if atcs.TagType == settings.TagTypeLightweight {
+		message = ""
+	}
+	if _, err := client.CreateTag(fullName, caption, sha, message); err != nil {
+		return fmt.Errorf("error when adding tag to commit %q: %v", fullName, err)
+	}
+
+	logger.Infof("Added a new version for %q: %q", fullName, caption)
+	return nil
+}
@@ -0,0 +1,77 @@
+package push
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	bbprovider "github.com/smartforce-io/atc/bitbucketservice/provider"
+)
+
+func TestBitbucketActionPush(t *testing.T) {
+	contents := map[string]string{
+		"old/.atc.yaml": "path: pom.xml",
+		"new/.atc.yaml": "path: pom.xml",
+		"old/pom.xml":   "<project><version>1.0</version></project>",
+		"new/pom.xml":   "<project><version>1.1</version></project>",
+	}
+	type tagRequest struct {
+		Name   string            `json:"name"`
+		Target bbprovider.Target `json:"target"`
+	}
+	var created []tagRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const srcPrefix = "/repositories/team/atc/src/"
+		switch {
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, srcPrefix):
+			content, ok := contents[strings.TrimPrefix(r.URL.Path, srcPrefix)]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(content))
+		case r.Method == http.MethodGet && r.URL.Path == "/repositories/team/atc":
+			w.Write([]byte(`{"mainbranch": {"name": "main"}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repositories/team/atc/refs/tags":
+			var tag tagRequest
+			json.NewDecoder(r.Body).Decode(&tag)
+			created = append(created, tag)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	var tests = []struct {
+		branch   string
+		nickname string
+		expected int
+	}{
+		{"main", "dev", 1},
+		{"feature", "dev", 0},
+		{"main", "atc[bot]", 0},
+	}
+	for _, test := range tests {
+		created = nil
+		event := &bbprovider.PushEvent{
+			Actor:      bbprovider.Actor{Nickname: test.nickname},
+			Repository: bbprovider.Repository{FullName: "team/atc"},
+			Push: bbprovider.Push{Changes: []bbprovider.Change{{
+				Old: &bbprovider.Ref{Type: "branch", Name: test.branch, Target: bbprovider.Target{Hash: "old"}},
+				New: &bbprovider.Ref{Type: "branch", Name: test.branch, Target: bbprovider.Target{Hash: "new"}},
+			}}},
+		}
+		BitbucketActionPush(event, bbprovider.NewClient(server.URL, "", "token"))
+		if len(created) != test.expected {
+			t.Errorf("branch %q by %q: expected %d tags, got %v", test.branch, test.nickname, test.expected, created)
+			continue
+		}
+		if test.expected > 0 && (created[0].Name != "v1.1" || created[0].Target.Hash != "new") {
+			t.Errorf("wrong tag request: %+v", created[0])
+		}
+	}
+}
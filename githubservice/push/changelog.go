@@ -0,0 +1,81 @@
+package push
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-github/v39/github"
+
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+// maxChangelogCommits is how many commits a changelog lists, the compare
+// API returns up to 250 anyway.
+const maxChangelogCommits = 100
+
+// changelog lists the commits from the tag of prev, the content of the
+// previous version, to sha as "- subject (short sha)" lines. It's empty
+// without a previous version.
+func changelog(ctx context.Context, client *github.Client, owner, repo string, setting *settings.AtcSettings,
+	prev TagContent, sha string) (string, error) {
+	if prev.Version == "" {
+		return "", nil
+	}
+	prevTag, err := previousTag(ctx, client, owner, repo, setting, prev)
+	if err != nil {
+		return "", err
+	}
+	comparison, _, err := client.Repositories.CompareCommits(ctx, owner, repo, prevTag, sha, nil)
+	if err != nil {
+		return "", fmt.Errorf("compare %s...%s: %w", prevTag, sha, err)
+	}
+
+	commits := comparison.Commits
+	var sb strings.Builder
+	if len(commits) > maxChangelogCommits {
+		// the newest commits are the last ones
+		commits = commits[len(commits)-maxChangelogCommits:]
+		fmt.Fprintf(&sb, "- ... %d older commits\n", len(comparison.Commits)-maxChangelogCommits)
+	}
+	for _, commit := range commits {
+		subject := strings.SplitN(commit.GetCommit().GetMessage(), "\n", 2)[0]
+		commitSHA := commit.GetSHA()
+		if len(commitSHA) > 7 {
+			commitSHA = commitSHA[:7]
+		}
+		fmt.Fprintf(&sb, "- %s (%s)\n", subject, commitSHA)
+	}
+	return strings.TrimSuffix(sb.String(), "\n"), nil
+}
+
+// previousTag returns the tag of the previous version from the tags of the
+// repository: the one the template renders for prev, or else the newest one
+// with prev.Version in its name, e.g. when the template has the time or the
+// SHA. The rendered name is returned when none of them is found.
+func previousTag(ctx context.Context, client *github.Client, owner, repo string, setting *settings.AtcSettings,
+	prev TagContent) (string, error) {
+	rendered, err := renderTemplate(setting.Template, prev)
+	if err != nil {
+		return "", err
+	}
+	tags, _, err := client.Repositories.ListTags(ctx, owner, repo, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return rendered, nil
+	}
+	versionRegex := regexp.MustCompile(`(^|[^0-9.])` + regexp.QuoteMeta(prev.Version) + `($|[^0-9.])`)
+	found := ""
+	for _, tag := range tags {
+		if tag.GetName() == rendered {
+			return rendered, nil
+		}
+		if found == "" && versionRegex.MatchString(tag.GetName()) {
+			found = tag.GetName()
+		}
+	}
+	if found == "" {
+		return rendered, nil
+	}
+	return found, nil
+}
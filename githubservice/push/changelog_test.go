@@ -0,0 +1,110 @@
+package push
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v39/github"
+
+	"github.com/smartforce-io/atc/envvars"
+	"github.com/smartforce-io/atc/githubservice/provider"
+)
+
+func TestChangelog(t *testing.T) {
+	var tests = []struct {
+		config  string
+		message string
+		body    string
+	}{
+		{"path: pom.xml\nchangelog: true\ncreate_release: true",
+			"v5\n\n- Fix the parser (1111111)\n- Bump version (2222222)",
+			"- Fix the parser (1111111)\n- Bump version (2222222)"},
+		{"path: pom.xml\nchangelog: true\ncreate_release: true\ntag_annotation_template: \"Release {{.Version}}:\\n{{.Changelog}}\"\ntag_body: \"## Changes\\n{{.Changelog}}\"",
+			"Release 5:\n- Fix the parser (1111111)\n- Bump version (2222222)",
+			"## Changes\n- Fix the parser (1111111)\n- Bump version (2222222)"},
+		{"path: pom.xml\ncreate_release: true", "v5", ""},
+	}
+
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	for _, test := range tests {
+		mockClientProviderPtr := provider.DefaultMockClientProvider()
+		var message, body, compared string
+
+		mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			return provider.NewTestResponse(200, provider.MockContentResponse(test.config))
+		})
+		mockClientProviderPtr.OverrideResponseFn("COMPARE_COMMITS", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			compared = req.URL.Path
+			return provider.NewTestResponse(200, `{"commits": [
+				{"sha": "1111111111111111111111111111111111111111", "commit": {"message": "Fix the parser\n\nLong description"}},
+				{"sha": "2222222222222222222222222222222222222222", "commit": {"message": "Bump version"}}]}`)
+		})
+		mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			message = fmt.Sprint(provider.GetBodyJson(req)["message"])
+			return defaultFn(req)
+		})
+		mockClientProviderPtr.OverrideResponseFn("CREATE_RELEASE", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			body = fmt.Sprint(provider.GetBodyJson(req)["body"])
+			return defaultFn(req)
+		})
+
+		ActionPush(&p, mockClientProviderPtr)
+
+		if message != test.message {
+			t.Errorf("config %q: expected message %q, got %q", test.config, test.message, message)
+		}
+		if body != test.body {
+			t.Errorf("config %q: expected release body %q, got %q", test.config, test.body, body)
+		}
+		if strings.Contains(test.config, "changelog") && !strings.Contains(compared, "/compare/v") {
+			t.Errorf("config %q: wrong compare request %q", test.config, compared)
+		}
+	}
+}
+
+func TestChangelogPreviousTag(t *testing.T) {
+	var tests = []struct {
+		template string
+		tags     string
+		compared string
+	}{
+		{"{{.Branch}}-v{{.Version}}", `[]`, "/compare/main-v4..."},
+		{"v{{.Version}}", `[{"name": "v5"}, {"name": "v4"}]`, "/compare/v4..."},
+		// the date of the previous tag can't be rendered again
+		{`v{{.Version}}-{{Time.Format "20060102"}}`, `[{"name": "v41-20261012"}, {"name": "v4-20261001"}, {"name": "v4-20260901"}]`, "/compare/v4-20261001..."},
+	}
+
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	for _, test := range tests {
+		mockClientProviderPtr := provider.DefaultMockClientProvider()
+		compared := ""
+		config := fmt.Sprintf("path: pom.xml\nchangelog: true\ntemplate: '%s'", test.template)
+		mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			return provider.NewTestResponse(200, provider.MockContentResponse(config))
+		})
+		tags := test.tags
+		mockClientProviderPtr.OverrideResponseFn("LIST_TAGS", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			return provider.NewTestResponse(200, tags)
+		})
+		mockClientProviderPtr.OverrideResponseFn("COMPARE_COMMITS", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			compared = req.URL.Path
+			return defaultFn(req)
+		})
+
+		ActionPush(&p, mockClientProviderPtr)
+
+		if !strings.Contains(compared, test.compared) {
+			t.Errorf("template %q: expected compare %q, got %q", test.template, test.compared, compared)
+		}
+	}
+}
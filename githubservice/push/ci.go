@@ -0,0 +1,162 @@
+package push
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+	glprovider "github.com/smartforce-io/atc/gitlabservice/provider"
+	"github.com/smartforce-io/atc/logger"
+)
+
+type CIProvider string
+
+const (
+	CIProviderGithub   CIProvider = "github"
+	CIProviderGitlab   CIProvider = "gitlab"
+	CIProviderCircleCI CIProvider = "circleci"
+)
+
+// ciEnvironment holds the values CIActionPush needs, read from the
+// provider-specific environment variables.
+type ciEnvironment struct {
+	Token      string
+	Repository string
+	CommitSHA  string
+	APIURL     string
+	JobToken   bool
+}
+
+// DetectCIProvider returns the provider set in CI_PROVIDER or, when it's
+// empty, guesses it from well-known variables of the CI systems.
+func DetectCIProvider() CIProvider {
+	if p := os.Getenv("CI_PROVIDER"); p != "" {
+		return CIProvider(p)
+	}
+	if os.Getenv("GITLAB_CI") != "" {
+		return CIProviderGitlab
+	}
+	if os.Getenv("CIRCLECI") != "" {
+		return CIProviderCircleCI
+	}
+	return CIProviderGithub
+}
+
+func getCIEnvironment(ciProvider CIProvider) (*ciEnvironment, error) {
+	switch ciProvider {
+	case CIProviderGithub:
+		return &ciEnvironment{
+			Token:      os.Getenv("GITHUB_TOKEN"),
+			Repository: os.Getenv("GITHUB_REPOSITORY"),
+			CommitSHA:  os.Getenv("COMMIT_SHA"),
+		}, nil
+	case CIProviderCircleCI:
+		// the repository is on GitHub, CIRCLE_TOKEN is a CircleCI API token
+		// GitHub doesn't accept
+		if os.Getenv("GITHUB_TOKEN") == "" {
+			return nil, errors.New("GITHUB_TOKEN is required on CircleCI")
+		}
+		return &ciEnvironment{
+			Token:      os.Getenv("GITHUB_TOKEN"),
+			Repository: os.Getenv("CIRCLE_PROJECT_USERNAME") + "/" + os.Getenv("CIRCLE_PROJECT_REPONAME"),
+			CommitSHA:  os.Getenv("CIRCLE_SHA1"),
+		}, nil
+	case CIProviderGitlab:
+		env := &ciEnvironment{
+			Token:      os.Getenv("GITLAB_TOKEN"),
+			Repository: os.Getenv("CI_PROJECT_PATH"),
+			CommitSHA:  os.Getenv("CI_COMMIT_SHA"),
+			APIURL:     os.Getenv("CI_API_V4_URL"),
+		}
+		// CI_JOB_TOKEN can't create tags on most GitLab setups, so a personal
+		// or project access token in GITLAB_TOKEN wins when it's present.
+		if env.Token == "" {
+			env.Token = os.Getenv("CI_JOB_TOKEN")
+			env.JobToken = true
+		}
+		return env, nil
+	default:
+		return nil, fmt.Errorf("CI provider %q is not supported", ciProvider)
+	}
+}
+
+func getCISettings() *settings.AtcSettings {
+	// an unset or wrong value leaves publishing off
+	syncNPMPackage, _ := strconv.ParseBool(os.Getenv("SYNC_NPM_PACKAGE"))
+	dryRun, _ := strconv.ParseBool(os.Getenv("DRY_RUN"))
+	return &settings.AtcSettings{
+		Path:           os.Getenv("FILE_TYPE"),
+		Behavior:       os.Getenv("BEHAVIOR"),
+		Template:       os.Getenv("TEMPLATE"),
+		RegexStr:       os.Getenv("REGEX"),
+		SyncNPMPackage: syncNPMPackage,
+		NPMScope:       os.Getenv("NPM_SCOPE"),
+		NPMPackageDir:  os.Getenv("NPM_PACKAGE_DIR"),
+		DryRun:         dryRun,
+		TagType:        os.Getenv("TAG_TYPE"),
+	}
+}
+
+func ciActionPushGitlab(env *ciEnvironment, atcs *settings.AtcSettings) error {
+	client := glprovider.NewClient(env.APIURL, env.Token, env.JobToken)
+
+	commit, err := client.GetCommit(env.Repository, env.CommitSHA)
+	if err != nil {
+		return fmt.Errorf("error getting commit %s %v", env.CommitSHA, err)
+	}
+	if len(commit.ParentIDs) == 0 {
+		logger.Debugf("this branch has no older commits")
+		return nil
+	}
+	return tagGitlabPush(client, env.Repository, commit.ParentIDs[0], env.CommitSHA, atcs)
+}
+
+// tagGitlabPush tags the version of project at after when it differs from
+// the one at before. An empty before reads no old version.
+func tagGitlabPush(client *glprovider.Client, project, before, after string, atcs *settings.AtcSettings) error {
+	var glOldContentProviderPtr provider.ContentProvider
+	if before != "" {
+		glOldContentProviderPtr = &glprovider.GlContentProvider{
+			Client:  client,
+			Project: project,
+			Ref:     before,
+		}
+	}
+	glNewContentProviderPtr := &glprovider.GlContentProvider{
+		Client:  client,
+		Project: project,
+		Ref:     after,
+	}
+
+	caption, err := fetch(atcs, glOldContentProviderPtr, glNewContentProviderPtr, project)
+	if err != nil {
+		return fmt.Errorf("fetch version error: %v", err)
+	}
+	if caption == "" {
+		logger.Debugf("Old and new versions are equal")
+		return nil
+	}
+
+	sha := after
+	if atcs.Behavior == settings.BehaviorBefore && before != "" {
+		sha = before
+	}
+	if atcs.DryRun {
+		logger.Infof("Dry run: tag %q would be added to %s of %q", caption, sha, project)
+		return nil
+	}
+	// GitLab creates a lightweight tag without a message
+	message := caption
+	if atcs.TagType == settings.TagTypeLightweight {
+		message = ""
+	}
+	if _, err := client.CreateTag(project, caption, sha, message); err != nil {
+		return fmt.Errorf("error when adding tag to commit %q: %v", project, err)
+	}
+
+	logger.Infof("Added a new version for %q: %q", project, caption)
+	return nil
+}
@@ -0,0 +1,105 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strings"
+
+	"github.com/google/go-github/v39/github"
+
+	"github.com/smartforce-io/atc/githubservice/accesstoken"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+	"github.com/smartforce-io/atc/githubservice/tagsign"
+	"github.com/smartforce-io/atc/logger"
+)
+
+// tagCrossRepoTargets tags the head of the default branch of every target
+// repository whose condition matches the new tag. A target is tagged with a
+// token of its own installation, and only if its .atc.yaml lists the source
+// repository in cross_repo_sources. The tags are created like the tag of the
+// source with its settings and signer. Failures are only logged.
+func tagCrossRepoTargets(ctx context.Context, clientProvider provider.ClientProvider, setting *settings.AtcSettings,
+	tagContent TagContent, tagger *github.CommitAuthor, signer tagsign.Signer) {
+	log := logger.FromContext(ctx)
+	if setting.DryRun && len(setting.CrossRepoTagging) > 0 {
+		log.Infof("Dry run: cross-repo tags of %q are skipped", tagContent.Repository)
+		return
+	}
+	for _, target := range setting.CrossRepoTagging {
+		if target.Condition != "" {
+			if matched, _ := path.Match(target.Condition, tagContent.Tag); !matched {
+				continue
+			}
+		}
+		s := strings.SplitN(target.Repo, "/", 2)
+		owner, repo := s[0], s[1]
+
+		client, err := repoClient(ctx, owner, repo, clientProvider)
+		if err != nil {
+			log.Errorf("cross-repo tagging: can't get a client for %q: %v", target.Repo, err)
+			continue
+		}
+		targetSetting, err := settings.GetAtcSetting(&provider.GhContentProvider{
+			Owner:    owner,
+			Repo:     repo,
+			Ctx:      ctx,
+			GhClient: client,
+		})
+		if err != nil {
+			log.Errorf("cross-repo tagging: settings of %q: %v", target.Repo, err)
+			continue
+		}
+		if !targetSetting.AcceptsCrossRepoTag(tagContent.Repository) {
+			log.Warnf("cross-repo tagging: %q doesn't list %q in cross_repo_sources, skipped", target.Repo, tagContent.Repository)
+			continue
+		}
+
+		r, _, err := client.Repositories.Get(ctx, owner, repo)
+		if err != nil {
+			log.Errorf("cross-repo tagging: can't get %q: %v", target.Repo, err)
+			continue
+		}
+		b, _, err := client.Repositories.GetBranch(ctx, owner, repo, r.GetDefaultBranch(), true)
+		if err != nil {
+			log.Errorf("cross-repo tagging: can't get branch %q of %q: %v", r.GetDefaultBranch(), target.Repo, err)
+			continue
+		}
+		caption, err := renderTemplate(target.TagTemplate, tagContent)
+		if err != nil {
+			log.Errorf("cross-repo tagging: error in go templates for %q: %v", target.Repo, err)
+			continue
+		}
+		targetTagger := NewTagger(client, owner, repo, nil, nil)
+		targetTagger.Settings = setting
+		targetTagger.Signer = signer
+		targetContent := tagContent
+		targetContent.Tag = caption
+		targetContent.SHA = b.GetCommit().GetSHA()
+		err = targetTagger.CreateTag(targetContent, targetContent.SHA, tagger)
+		if errors.Is(err, ErrTagOnCommit) || errors.Is(err, ErrTagExists) {
+			log.Infof("Cross-repo tag %q already exists in %q, skipped", caption, target.Repo)
+			continue
+		}
+		if err != nil {
+			log.Errorf("cross-repo tagging: addTagToCommit Error for %q: %v", target.Repo, err)
+			continue
+		}
+		log.Infof("Added cross-repo tag %q to %q for %q", caption, target.Repo, tagContent.Repository)
+	}
+}
+
+// repoClient returns a client with a token of the installation of the app on
+// owner/repo.
+func repoClient(ctx context.Context, owner, repo string, clientProvider provider.ClientProvider) (*github.Client, error) {
+	id, err := accesstoken.GetRepositoryInstallationID(ctx, owner, repo, clientProvider)
+	if err != nil {
+		return nil, err
+	}
+	token, err := accesstoken.GetAccessToken(id, clientProvider)
+	if err != nil {
+		return nil, err
+	}
+	return clientProvider.Get(token, ctx), nil
+}
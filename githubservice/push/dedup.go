@@ -0,0 +1,42 @@
+package push
+
+import (
+	"context"
+
+	"github.com/smartforce-io/atc/dedup"
+	"github.com/smartforce-io/atc/logger"
+)
+
+// Dedup remembers the tags created by the webhook server, so a redelivered
+// push or a force push of the same commit doesn't tag or comment twice. It's
+// nil, no deduplication, unless main sets it.
+var Dedup dedup.Store
+
+func tagKey(fullname, caption, sha string) string {
+	return "tag:" + fullname + ":" + caption + ":" + sha
+}
+
+// tagSeen reports whether caption was tagged at sha of fullname already and
+// records it otherwise. Store errors are logged and the tag is created.
+func tagSeen(ctx context.Context, fullname, caption, sha string) bool {
+	if Dedup == nil {
+		return false
+	}
+	seen, err := Dedup.Seen(ctx, tagKey(fullname, caption, sha))
+	if err != nil {
+		logger.FromContext(ctx).Warnf("dedup store error for %q: %v", fullname, err)
+		return false
+	}
+	return seen
+}
+
+// forgetTag drops the record of tagSeen after a failure, so a redelivery
+// retries the tag.
+func forgetTag(ctx context.Context, fullname, caption, sha string) {
+	if Dedup == nil {
+		return
+	}
+	if err := Dedup.Forget(ctx, tagKey(fullname, caption, sha)); err != nil {
+		logger.FromContext(ctx).Warnf("dedup store error for %q: %v", fullname, err)
+	}
+}
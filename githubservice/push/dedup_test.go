@@ -0,0 +1,53 @@
+package push
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/google/go-github/v39/github"
+
+	"github.com/smartforce-io/atc/dedup"
+	"github.com/smartforce-io/atc/envvars"
+	"github.com/smartforce-io/atc/githubservice/provider"
+)
+
+func TestDedupRedeliveredPush(t *testing.T) {
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+	Dedup = dedup.NewMemoryStore(dedup.DefaultCapacity, dedup.DefaultTTL)
+	defer func() { Dedup = nil }()
+
+	var tests = []struct {
+		tagStatus        int
+		expectedTags     int
+		expectedComments int
+	}{
+		{http.StatusInternalServerError, 1, 1}, // forgotten, so it's retried
+		{http.StatusCreated, 1, 1},
+		{http.StatusCreated, 0, 0}, // redelivered
+	}
+	for i, test := range tests {
+		tags, comments := 0, 0
+		mockClientProviderPtr := provider.DefaultMockClientProvider()
+		mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			tags++
+			if test.tagStatus != http.StatusCreated {
+				return provider.NewTestResponse(test.tagStatus, `{"message": "error"}`)
+			}
+			return defaultFn(req)
+		})
+		mockClientProviderPtr.OverrideResponseFn("ADD_COMMENT", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			comments++
+			return defaultFn(req)
+		})
+
+		ActionPush(&p, mockClientProviderPtr)
+
+		if tags != test.expectedTags || comments != test.expectedComments {
+			t.Errorf("push %d: expected %d tags and %d comments, got %d and %d", i, test.expectedTags, test.expectedComments, tags, comments)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package push
+
+import (
+	"strings"
+
+	"github.com/google/go-github/v39/github"
+)
+
+const (
+	DirectiveSkip = "[atc skip]"
+	DirectiveTag  = "[atc tag]"
+)
+
+// commitDirective returns the directive in message, DirectiveSkip winning
+// over DirectiveTag, or "" if it has none. Directives are case insensitive.
+func commitDirective(message string) string {
+	message = strings.ToLower(message)
+	if strings.Contains(message, DirectiveSkip) {
+		return DirectiveSkip
+	}
+	if strings.Contains(message, DirectiveTag) {
+		return DirectiveTag
+	}
+	return ""
+}
+
+// headCommitMessage returns the message of the head commit of push, or of
+// its last commit if the payload has no head commit.
+func headCommitMessage(push *github.WebHookPayload) string {
+	if push.HeadCommit != nil {
+		return push.GetHeadCommit().GetMessage()
+	}
+	if len(push.Commits) > 0 {
+		return push.Commits[len(push.Commits)-1].GetMessage()
+	}
+	return ""
+}
@@ -0,0 +1,78 @@
+package push
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/smartforce-io/atc/envvars"
+	"github.com/smartforce-io/atc/githubservice/provider"
+)
+
+func TestCommitDirective(t *testing.T) {
+	var tests = []struct {
+		message  string
+		expected string
+	}{
+		{"Bump version", ""},
+		{"Bump version [atc skip]", DirectiveSkip},
+		{"Retag\n\n[ATC Tag]", DirectiveTag},
+		{"[atc tag] [atc skip]", DirectiveSkip},
+		{"[atc-skip]", ""},
+	}
+
+	for _, test := range tests {
+		if directive := commitDirective(test.message); directive != test.expected {
+			t.Errorf("%q: expected %q, got %q", test.message, test.expected, directive)
+		}
+	}
+}
+
+func TestCommitDirectives(t *testing.T) {
+	const unchangedPomXml = "<project><version>5</version></project>"
+	var tests = []struct {
+		config    string
+		message   string
+		unchanged bool
+		tagged    bool
+	}{
+		{"commit_directives: true", "Release [atc skip]", false, false},
+		{"commit_directives: true", "Retag [atc tag]", true, true},
+		{"commit_directives: true", "Release", true, false},
+		{"commit_directives: true", "Release [atc tag]", false, true},
+		{"commit_directives: false", "Release [atc skip]", false, true},
+		{"commit_directives: false", "Retag [atc tag]", true, false},
+	}
+
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	for _, test := range tests {
+		p := github.WebHookPayload{}
+		json.Unmarshal([]byte(testWebhookPayload), &p)
+		p.HeadCommit = &github.WebHookCommit{Message: github.String(test.message)}
+
+		mockClientProviderPtr := provider.DefaultMockClientProvider()
+		tagged := false
+		config := "path: pom.xml\n" + test.config
+		mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			return provider.NewTestResponse(200, provider.MockContentResponse(config))
+		})
+		if test.unchanged {
+			mockClientProviderPtr.OverrideResponseFn("GET_OLD_VERSION_MAVEN", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+				return provider.NewTestResponse(200, provider.MockContentResponse(unchangedPomXml))
+			})
+		}
+		mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			tagged = true
+			return defaultFn(req)
+		})
+
+		ActionPush(&p, mockClientProviderPtr)
+
+		if tagged != test.tagged {
+			t.Errorf("%s, message %q: expected tagged %v, got %v", test.config, test.message, test.tagged, tagged)
+		}
+	}
+}
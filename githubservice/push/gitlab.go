@@ -0,0 +1,55 @@
+package push
+
+import (
+	"strings"
+
+	"github.com/smartforce-io/atc/githubservice/settings"
+	glprovider "github.com/smartforce-io/atc/gitlabservice/provider"
+	"github.com/smartforce-io/atc/logger"
+)
+
+// GitlabActionPush tags the version of a GitLab push webhook event with the
+// .atc.yaml of the project. Failures are only logged, the GitLab client
+// can't comment on commits.
+func GitlabActionPush(event *glprovider.PushEvent, client *glprovider.Client) {
+	project := event.Project.PathWithNamespace
+	glNewContentProviderPtr := &glprovider.GlContentProvider{
+		Client:  client,
+		Project: project,
+		Ref:     event.After,
+	}
+	atcs, err := settings.GetAtcSetting(glNewContentProviderPtr)
+	if err != nil {
+		logger.Errorf("settings error for %q: %v", project, err)
+		return
+	}
+	if atcs.IsBot(event.UserUsername) {
+		logger.Warnf("skip push of %q by bot %q", project, event.UserUsername)
+		return
+	}
+
+	branch := strings.TrimPrefix(event.Ref, "refs/heads/")
+	branchSettings := atcs.UseBranch(branch)
+	if channelName, channel := atcs.ChannelForBranch(branch); channel != nil {
+		logger.Debugf("branch %q of %q uses channel %q", branch, project, channelName)
+		atcs.UseChannel(channel)
+	} else if !branchSettings {
+		tagBranch := atcs.Branch
+		if tagBranch == "" {
+			tagBranch = event.Project.DefaultBranch
+		}
+		if branch != tagBranch {
+			return
+		}
+	}
+
+	before := event.Before
+	if isZeroSHA(before) {
+		before = ""
+	}
+	for _, targetSettings := range atcs.TargetSettings() {
+		if err := tagGitlabPush(client, project, before, event.After, targetSettings); err != nil {
+			logger.Errorf("tag error for %q: %v", project, err)
+		}
+	}
+}
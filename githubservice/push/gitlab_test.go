@@ -0,0 +1,72 @@
+package push
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	glprovider "github.com/smartforce-io/atc/gitlabservice/provider"
+)
+
+func TestGitlabActionPush(t *testing.T) {
+	contents := map[string]string{
+		"old/.atc.yaml": "path: pom.xml",
+		"new/.atc.yaml": "path: pom.xml",
+		"old/pom.xml":   "<project><version>1.0</version></project>",
+		"new/pom.xml":   "<project><version>1.1</version></project>",
+	}
+	var created []map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const filesPrefix = "/projects/group%2Fatc/repository/files/"
+		switch {
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.EscapedPath(), filesPrefix):
+			file := strings.TrimSuffix(strings.TrimPrefix(r.URL.EscapedPath(), filesPrefix), "/raw")
+			content, ok := contents[r.URL.Query().Get("ref")+"/"+file]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(content))
+		case r.Method == http.MethodPost && r.URL.EscapedPath() == "/projects/group%2Fatc/repository/tags":
+			var tag map[string]string
+			json.NewDecoder(r.Body).Decode(&tag)
+			created = append(created, tag)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	var tests = []struct {
+		ref      string
+		username string
+		expected int
+	}{
+		{"refs/heads/main", "dev", 1},
+		{"refs/heads/feature", "dev", 0},
+		{"refs/heads/main", "atc[bot]", 0},
+	}
+	for _, test := range tests {
+		created = nil
+		event := &glprovider.PushEvent{
+			ObjectKind:   "push",
+			Ref:          test.ref,
+			Before:       "old",
+			After:        "new",
+			UserUsername: test.username,
+			Project:      glprovider.Project{PathWithNamespace: "group/atc", DefaultBranch: "main"},
+		}
+		GitlabActionPush(event, glprovider.NewClient(server.URL, "token", false))
+		if len(created) != test.expected {
+			t.Errorf("ref %q by %q: expected %d tags, got %v", test.ref, test.username, test.expected, created)
+			continue
+		}
+		if test.expected > 0 && (created[0]["tag_name"] != "v1.1" || created[0]["ref"] != "new") {
+			t.Errorf("wrong tag request: %v", created[0])
+		}
+	}
+}
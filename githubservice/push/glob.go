@@ -0,0 +1,114 @@
+package push
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+	"github.com/smartforce-io/atc/logger"
+)
+
+// maxGlobFiles limits how many files a glob path reads, each one is an API
+// request.
+const maxGlobFiles = 20
+
+// isGlobPath reports whether the configured path is a pattern.
+func isGlobPath(filePath string) bool {
+	return strings.ContainsAny(filePath, "*?")
+}
+
+// matchGlob reports whether name matches pattern. Segments are matched with
+// path.Match, a "**" segment matches any number of directories.
+func matchGlob(pattern, name string) (bool, error) {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) (bool, error) {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matched, err := matchSegments(pattern[1:], name[i:]); matched || err != nil {
+					return matched, err
+				}
+			}
+			return false, nil
+		}
+		if len(name) == 0 {
+			return false, nil
+		}
+		if matched, err := path.Match(pattern[0], name[0]); !matched || err != nil {
+			return false, err
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0, nil
+}
+
+// globFetcher reads the version from the files matching a glob path, e.g.
+// "**/pom.xml" for Maven multi-module projects. Each file is read with the
+// fetcher for its own path and the first file with a version wins.
+type globFetcher struct {
+}
+
+func (globFetcher *globFetcher) GetVersion(ghContentProvider provider.ContentProvider, atcSettings settings.AtcSettings) (string, error) {
+	treeProvider, ok := ghContentProvider.(provider.TreeProvider)
+	if !ok {
+		return "", provider.ErrNoTreeProvider
+	}
+	files, err := treeProvider.ListFiles()
+	if err != nil {
+		return "", err
+	}
+	matches := []string{}
+	for _, file := range files {
+		matched, err := matchGlob(atcSettings.Path, file)
+		if err != nil {
+			return "", fmt.Errorf("wrong path %q: %w", atcSettings.Path, err)
+		}
+		if matched {
+			matches = append(matches, file)
+		}
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("%w: no files match %q", fetcher.ErrNoVers, atcSettings.Path)
+	}
+	if len(matches) > maxGlobFiles {
+		logger.Warnf("%d files match %q, reading the first %d", len(matches), atcSettings.Path, maxGlobFiles)
+		matches = matches[:maxGlobFiles]
+	}
+
+	versions := make([]string, len(matches))
+	errs := make([]error, len(matches))
+	var wg sync.WaitGroup
+	for i, file := range matches {
+		wg.Add(1)
+		go func(i int, file string) {
+			defer wg.Done()
+			fileSettings := atcSettings
+			fileSettings.Path = file
+			versionFetcher, err := resolveFetcher(&fileSettings, ghContentProvider)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			versions[i], errs[i] = versionFetcher.GetVersion(ghContentProvider, fileSettings)
+		}(i, file)
+	}
+	wg.Wait()
+
+	// the files are in tree order, so the result doesn't depend on timing
+	for i := range matches {
+		if errs[i] == nil {
+			return versions[i], nil
+		}
+	}
+	return "", errs[len(errs)-1]
+}
+
+func (globFetcher *globFetcher) GetVersionUsingDefaultPath(ghContentProvider provider.ContentProvider) (string, error) {
+	return "", fetcher.ErrNoVers
+}
@@ -0,0 +1,123 @@
+package push
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/smartforce-io/atc/envvars"
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+func TestMatchGlob(t *testing.T) {
+	var tests = []struct {
+		pattern string
+		name    string
+		matched bool
+	}{
+		{"**/pom.xml", "pom.xml", true},
+		{"**/pom.xml", "a/b/pom.xml", true},
+		{"**/pom.xml", "a/b/pom.xml.bak", false},
+		{"modules/*/pom.xml", "modules/core/pom.xml", true},
+		{"modules/*/pom.xml", "modules/core/sub/pom.xml", false},
+		{"modules/**", "modules/core/sub/pom.xml", true},
+		{"app?/build.gradle", "app2/build.gradle", true},
+		{"*.gradle", "app/build.gradle", false},
+	}
+	for _, test := range tests {
+		matched, err := matchGlob(test.pattern, test.name)
+		if err != nil {
+			t.Errorf("%q, %q: unexpected error %v", test.pattern, test.name, err)
+		}
+		if matched != test.matched {
+			t.Errorf("%q, %q: expected %v, got %v", test.pattern, test.name, test.matched, matched)
+		}
+	}
+}
+
+func TestGlobFetcher(t *testing.T) {
+	pom := func(version string) string {
+		return fmt.Sprintf("<project><version>%s</version></project>", version)
+	}
+	cp := provider.MockFilesContentProvider{
+		"README.md":                   "# modules",
+		"parent/pom.xml":              "<project></project>",
+		"modules/core/pom.xml":        pom("2.1.0"),
+		"modules/web/pom.xml":         pom("2.0.0"),
+		"modules/web/build.txt":       "version: 1.0.0",
+		"gems/atc/atc.gemspec":        "Gem::Specification.new do |spec|\n  spec.version = Atc::VERSION\nend\n",
+		"gems/atc/lib/atc/version.rb": "module Atc\n  VERSION = \"0.4.0\"\nend\n",
+	}
+	var tests = []struct {
+		path     string
+		regexStr string
+		version  string
+		err      error
+	}{
+		{"**/pom.xml", "", "2.1.0", nil},
+		{"modules/web/*.xml", "", "2.0.0", nil},
+		{"**/build.txt", "version: (.+)", "1.0.0", nil},
+		{"gems/*/*.gemspec", "", "0.4.0", nil},
+		{"**/build.gradle", "", "", fetcher.ErrNoVers},
+	}
+	for _, test := range tests {
+		vers, err := (&globFetcher{}).GetVersion(cp, settings.AtcSettings{Path: test.path, RegexStr: test.regexStr})
+		if !errors.Is(err, test.err) {
+			t.Errorf("path %q: expected err %v, got %v", test.path, test.err, err)
+		}
+		if vers != test.version {
+			t.Errorf("path %q: expected %q, got %q", test.path, test.version, vers)
+		}
+	}
+
+	if _, err := (&globFetcher{}).GetVersion(&provider.MockContentProvider{}, settings.AtcSettings{Path: "**/pom.xml"}); err != provider.ErrNoTreeProvider {
+		t.Errorf("expected err %v, got %v", provider.ErrNoTreeProvider, err)
+	}
+}
+
+func TestGlobFetcherLimit(t *testing.T) {
+	cp := provider.MockFilesContentProvider{}
+	for i := 0; i < maxGlobFiles+5; i++ {
+		cp[fmt.Sprintf("m%02d/pom.xml", i)] = "<project></project>"
+	}
+	cp["m99/pom.xml"] = "<project><version>1.0.0</version></project>"
+	if vers, err := (&globFetcher{}).GetVersion(cp, settings.AtcSettings{Path: "*/pom.xml"}); err == nil {
+		t.Errorf("expected the file after the limit to be skipped, got version %q", vers)
+	}
+}
+
+func TestGlobPathPush(t *testing.T) {
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+	tag := ""
+	mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		return provider.NewTestResponse(200, provider.MockContentResponse(`path: "**/pom.xml"`))
+	})
+	mockClientProviderPtr.OverrideResponseFn("GET_TREE", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		if !strings.HasSuffix(req.URL.Path, "/main") && !strings.HasSuffix(req.URL.Path, "/"+p.GetBefore()) {
+			t.Errorf("unexpected tree %q", req.URL.Path)
+		}
+		return provider.NewTestResponse(200, `{"tree": [{"path": "README.md", "type": "blob"}, {"path": "core", "type": "tree"}, {"path": "core/pom.xml", "type": "blob"}]}`)
+	})
+	mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		j := provider.GetBodyJson(req)
+		tag = fmt.Sprintf("%v", j["tag"])
+		return defaultFn(req)
+	})
+
+	ActionPush(&p, mockClientProviderPtr)
+
+	if tag != "v5" {
+		t.Errorf("Wrong tag! expected: %s, got: %s", "v5", tag)
+	}
+}
@@ -0,0 +1,46 @@
+package push
+
+import (
+	"os"
+	"strings"
+
+	"github.com/smartforce-io/atc/envvars"
+	"github.com/smartforce-io/atc/githubservice/jira"
+	"github.com/smartforce-io/atc/githubservice/settings"
+	"github.com/smartforce-io/atc/logger"
+)
+
+// releaseJiraVersion creates and releases the JIRA version name. Failures are
+// only logged, the tag is already there. Without api_token the server token
+// is only sent to the base URLs of ATC_JIRA_BASE_URLS.
+func releaseJiraVersion(jiraConfig *settings.JIRAConfig, name string) {
+	apiToken := jiraConfig.APIToken
+	if apiToken == "" {
+		if !jiraServerTokenAllowed(jiraConfig.BaseURL) {
+			logger.Warnf("can't create JIRA version %q: %s isn't in %s and api_token is empty", name, jiraConfig.BaseURL, envvars.JiraBaseURLs)
+			return
+		}
+		apiToken = os.Getenv(envvars.JiraAPIToken)
+	}
+	client := jira.NewClient(jiraConfig.BaseURL, jiraConfig.Username, apiToken)
+	version, err := client.CreateVersion(jiraConfig.ProjectKey, name)
+	if err != nil {
+		logger.Warnf("can't create JIRA version %q in %s: %v", name, jiraConfig.ProjectKey, err)
+		return
+	}
+	if err := client.ReleaseVersion(version.ID); err != nil {
+		logger.Warnf("can't release JIRA version %q in %s: %v", name, jiraConfig.ProjectKey, err)
+	}
+}
+
+// jiraServerTokenAllowed reports whether baseURL is one of ATC_JIRA_BASE_URLS.
+func jiraServerTokenAllowed(baseURL string) bool {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	for _, allowed := range strings.Split(os.Getenv(envvars.JiraBaseURLs), ",") {
+		allowed = strings.TrimSuffix(strings.TrimSpace(allowed), "/")
+		if allowed != "" && strings.EqualFold(allowed, baseURL) {
+			return true
+		}
+	}
+	return false
+}
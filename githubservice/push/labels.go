@@ -0,0 +1,79 @@
+package push
+
+import (
+	"context"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/smartforce-io/atc/githubservice/semver"
+	"github.com/smartforce-io/atc/githubservice/settings"
+	"github.com/smartforce-io/atc/logger"
+)
+
+const (
+	LabelSkip       = "atc:skip"
+	LabelMajor      = "atc:major"
+	LabelPreRelease = "atc:pre-release"
+)
+
+// mergedPRLabels returns the labels of the merged pull requests that
+// contain sha.
+func mergedPRLabels(client *github.Client, owner, repo, sha string) (map[string]bool, error) {
+	prs, _, err := client.PullRequests.ListPullRequestsWithCommit(context.Background(), owner, repo, sha, nil)
+	if err != nil {
+		return nil, err
+	}
+	labels := map[string]bool{}
+	for _, pr := range prs {
+		if pr.MergedAt == nil {
+			continue
+		}
+		for _, label := range pr.Labels {
+			labels[label.GetName()] = true
+		}
+	}
+	return labels, nil
+}
+
+// forceMajor returns newVersion if it's a major bump of oldVersion, or else
+// the next major version after oldVersion.
+func forceMajor(oldVersion, newVersion string) (string, error) {
+	oldSemver, err := semver.Parse(oldVersion)
+	if err != nil {
+		return "", err
+	}
+	newSemver, err := semver.Parse(newVersion)
+	if err != nil {
+		return "", err
+	}
+	if newSemver.Major > oldSemver.Major {
+		return newVersion, nil
+	}
+	return oldSemver.BumpMajor().String(), nil
+}
+
+// applyPRLabels adjusts the new version and setting to the labels of the
+// pull request merged by push. It returns false if the push isn't tagged.
+func applyPRLabels(client *github.Client, push *github.WebHookPayload, setting *settings.AtcSettings, oldVersion, newVersion string) (string, bool) {
+	fullname := push.GetRepo().GetFullName()
+	labels, err := mergedPRLabels(client, push.GetRepo().GetOwner().GetName(), push.GetRepo().GetName(), push.GetAfter())
+	if err != nil {
+		logger.Warnf("can't get pull request labels of %q: %v", fullname, err)
+		return newVersion, true
+	}
+	if labels[LabelSkip] {
+		logger.Infof("push of %q has the %q label, skipped", fullname, LabelSkip)
+		return newVersion, false
+	}
+	if labels[LabelPreRelease] {
+		setting.PreRelease = true
+	}
+	if labels[LabelMajor] {
+		version, err := forceMajor(oldVersion, newVersion)
+		if err != nil {
+			logger.Warnf("can't apply the %q label to %q: %v", LabelMajor, fullname, err)
+			return newVersion, true
+		}
+		newVersion = version
+	}
+	return newVersion, true
+}
@@ -0,0 +1,88 @@
+package push
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/smartforce-io/atc/envvars"
+	"github.com/smartforce-io/atc/githubservice/provider"
+)
+
+func TestForceMajor(t *testing.T) {
+	var tests = []struct {
+		oldVersion string
+		newVersion string
+		expected   string
+		err        bool
+	}{
+		{"1.2.3", "1.2.4", "2.0.0", false},
+		{"1.2.3", "3.0.0", "3.0.0", false},
+		{"v1.2", "v1.3", "2.0.0", false},
+		{"1.2.3", "next", "", true},
+	}
+
+	for _, test := range tests {
+		version, err := forceMajor(test.oldVersion, test.newVersion)
+		if (err != nil) != test.err {
+			t.Errorf("%q -> %q: unexpected error %v", test.oldVersion, test.newVersion, err)
+		}
+		if version != test.expected {
+			t.Errorf("%q -> %q: expected %q, got %q", test.oldVersion, test.newVersion, test.expected, version)
+		}
+	}
+}
+
+func TestLabelOverrides(t *testing.T) {
+	var tests = []struct {
+		config  string
+		pulls   string
+		tagged  bool
+		comment string
+	}{
+		{"label_overrides: true", `[{"number": 1, "merged_at": "2021-01-01T00:00:00Z", "labels": [{"name": "atc:skip"}]}]`, false, ""},
+		{"label_overrides: true", `[{"number": 1, "merged_at": "2021-01-01T00:00:00Z", "labels": [{"name": "atc:pre-release"}]}]`, true,
+			`Added a new version for "Codertocat/Hello-World": "v5-pre"`},
+		{"label_overrides: true", `[{"number": 1, "labels": [{"name": "atc:skip"}]}]`, true,
+			`Added a new version for "Codertocat/Hello-World": "v5"`},
+		{"label_overrides: false", `[{"number": 1, "merged_at": "2021-01-01T00:00:00Z", "labels": [{"name": "atc:skip"}]}]`, true,
+			`Added a new version for "Codertocat/Hello-World": "v5"`},
+	}
+
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	for _, test := range tests {
+		mockClientProviderPtr := provider.DefaultMockClientProvider()
+		tagged := false
+		comment := ""
+		config := "path: pom.xml\ntemplate: v{{.Version}}{{if .PreRelease}}-pre{{end}}\n" + test.config
+		mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			return provider.NewTestResponse(200, provider.MockContentResponse(config))
+		})
+		mockClientProviderPtr.OverrideResponseFn("LIST_COMMIT_PULLS", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			return provider.NewTestResponse(200, test.pulls)
+		})
+		mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			tagged = true
+			return defaultFn(req)
+		})
+		mockClientProviderPtr.OverrideResponseFn("ADD_COMMENT", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			comment = fmt.Sprintf("%v", provider.GetBodyJson(req)["body"])
+			return defaultFn(req)
+		})
+
+		ActionPush(&p, mockClientProviderPtr)
+
+		if tagged != test.tagged {
+			t.Errorf("pulls %s: expected tagged %v, got %v", test.pulls, test.tagged, tagged)
+		}
+		if comment != test.comment {
+			t.Errorf("pulls %s: expected comment %q, got %q", test.pulls, test.comment, comment)
+		}
+	}
+}
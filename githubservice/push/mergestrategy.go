@@ -0,0 +1,44 @@
+package push
+
+import (
+	"context"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/smartforce-io/atc/githubservice/settings"
+	"github.com/smartforce-io/atc/logger"
+)
+
+// isOnBase reports whether push updated the tracked branch.
+func isOnBase(push *github.WebHookPayload, branch string) bool {
+	return push.GetRef() == "refs/heads/"+branch
+}
+
+// tagSHA returns the commit to tag for the merge strategy of the repository.
+// Squash and rebase merges put the change itself on the branch, so its
+// commit is tagged whatever the behavior is.
+func tagSHA(client *github.Client, push *github.WebHookPayload, setting *settings.AtcSettings, branch string) string {
+	if setting.Trigger == settings.TriggerPRMerge {
+		// a merged pull request tags its merge commit
+		return push.GetAfter()
+	}
+	switch setting.MergeStrategy {
+	case settings.MergeStrategyRebase:
+		return push.GetAfter()
+	case settings.MergeStrategySquash:
+		if !isOnBase(push, branch) {
+			break
+		}
+		owner := push.GetRepo().GetOwner().GetName()
+		repo := push.GetRepo().GetName()
+		commit, _, err := client.Git.GetCommit(context.Background(), owner, repo, push.GetAfter())
+		if err != nil {
+			logger.Warnf("can't get commit %q of %q: %v", push.GetAfter(), push.GetRepo().GetFullName(), err)
+			break
+		}
+		// a squash commit has a single parent, a merge commit falls back to behavior
+		if len(commit.Parents) == 1 {
+			return push.GetAfter()
+		}
+	}
+	return *getShaByBehavior(push, setting.Behavior)
+}
@@ -0,0 +1,62 @@
+package push
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/smartforce-io/atc/envvars"
+	"github.com/smartforce-io/atc/githubservice/provider"
+)
+
+func TestMergeStrategy(t *testing.T) {
+	var tests = []struct {
+		config  string
+		parents string
+		before  bool
+	}{
+		{"behavior: before", `[{"sha": "a"}]`, true},
+		{"behavior: before\nmerge_strategy: merge", `[{"sha": "a"}]`, true},
+		{"behavior: before\nmerge_strategy: squash", `[{"sha": "a"}]`, false},
+		{"behavior: before\nmerge_strategy: squash", `[{"sha": "a"}, {"sha": "b"}]`, true},
+		{"behavior: before\nmerge_strategy: rebase", `[{"sha": "a"}, {"sha": "b"}]`, false},
+		{"merge_strategy: squash", `[{"sha": "a"}, {"sha": "b"}]`, false},
+	}
+
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	for _, test := range tests {
+		mockClientProviderPtr := provider.DefaultMockClientProvider()
+		sha := ""
+		mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			return provider.NewTestResponse(200, provider.MockContentResponse("path: pom.xml\n"+test.config))
+		})
+		mockClientProviderPtr.OverrideResponseFn("GET_COMMIT", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			if !strings.Contains(req.URL.Path, "/git/commits/") {
+				return defaultFn(req)
+			}
+			return provider.NewTestResponse(200, fmt.Sprintf(`{"sha": "%s", "parents": %s}`, p.GetAfter(), test.parents))
+		})
+		mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			j := provider.GetBodyJson(req)
+			sha = fmt.Sprintf("%v", j["object"])
+			return defaultFn(req)
+		})
+
+		ActionPush(&p, mockClientProviderPtr)
+
+		expected := p.GetAfter()
+		if test.before {
+			expected = p.GetBefore()
+		}
+		if sha != expected {
+			t.Errorf("config %q, parents %s: expected tag on %s, got %s", test.config, test.parents, expected, sha)
+		}
+	}
+}
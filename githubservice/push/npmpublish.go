@@ -0,0 +1,50 @@
+package push
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+const githubPackagesRegistry = "https://npm.pkg.github.com"
+
+var execCommand = exec.Command
+
+// npmUserConfig returns an .npmrc that publishes scope to GitHub Packages
+// with token.
+func npmUserConfig(scope, token string) string {
+	if !strings.HasPrefix(scope, "@") {
+		scope = "@" + scope
+	}
+	host := strings.TrimPrefix(githubPackagesRegistry, "https:")
+	return fmt.Sprintf("%s:registry=%s\n%s/:_authToken=%s\n", scope, githubPackagesRegistry, host, token)
+}
+
+// publishNPMPackage runs `npm publish` in the package directory of the
+// checked out repository, so the version in GitHub Packages follows the tags.
+// The token is passed in a temporary user config to keep it out of the
+// command line and the repository.
+func publishNPMPackage(atcSettings *settings.AtcSettings, token string) error {
+	userConfig, err := os.CreateTemp("", "atc-npmrc-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(userConfig.Name())
+	_, err = userConfig.WriteString(npmUserConfig(atcSettings.NPMScope, token))
+	if closeErr := userConfig.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	cmd := execCommand("npm", "publish", "--userconfig", userConfig.Name())
+	cmd.Dir = atcSettings.NPMPackageDir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("npm publish: %v: %s", err, out)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package push
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+func TestNPMUserConfig(t *testing.T) {
+	expected := "@my-org:registry=https://npm.pkg.github.com\n//npm.pkg.github.com/:_authToken=ghs_token\n"
+	for _, scope := range []string{"@my-org", "my-org"} {
+		if userConfig := npmUserConfig(scope, "ghs_token"); userConfig != expected {
+			t.Errorf("scope %q: expected %q, got %q", scope, expected, userConfig)
+		}
+	}
+}
+
+func TestPublishNPMPackage(t *testing.T) {
+	defer func() { execCommand = exec.Command }()
+	var args []string
+	var userConfig string
+	execCommand = func(name string, arg ...string) *exec.Cmd {
+		args = append([]string{name}, arg...)
+		content, _ := os.ReadFile(arg[len(arg)-1])
+		userConfig = string(content)
+		return exec.Command("true")
+	}
+
+	err := publishNPMPackage(&settings.AtcSettings{NPMScope: "@my-org", NPMPackageDir: "."}, "ghs_token")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(args) != 4 || args[0] != "npm" || args[1] != "publish" || args[2] != "--userconfig" {
+		t.Errorf("wrong command %q", args)
+	}
+	if userConfig != npmUserConfig("@my-org", "ghs_token") {
+		t.Errorf("wrong user config %q", userConfig)
+	}
+	if _, err := os.Stat(args[3]); !os.IsNotExist(err) {
+		t.Errorf("user config %s isn't removed", args[3])
+	}
+
+	execCommand = func(name string, arg ...string) *exec.Cmd {
+		return exec.Command("false")
+	}
+	if err := publishNPMPackage(&settings.AtcSettings{NPMScope: "@my-org"}, "ghs_token"); err == nil {
+		t.Error("expected an error of a failed npm publish")
+	}
+}
@@ -0,0 +1,56 @@
+package push
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v39/github"
+
+	"github.com/smartforce-io/atc/githubservice/settings"
+	"github.com/smartforce-io/atc/logger"
+)
+
+// propagateToEnvironments tags sha once per configured environment. The
+// environments without a delay are tagged right away, the others are queued
+// in TagRetryQueue, which creates them after their delay with a fresh
+// installation token, so the push isn't held up.
+func propagateToEnvironments(client *github.Client, installationID int64, owner, repo string, setting *settings.AtcSettings,
+	tagContent TagContent, sha string, tagger *github.CommitAuthor, refFormat string) {
+	for _, env := range setting.PropagateToEnvironments {
+		caption, err := renderTemplate(env.Template, tagContent)
+		if err != nil {
+			logger.Errorf("error in go templates of environment %q: %v", env.Branch, err)
+			continue
+		}
+		job := &tagJob{
+			InstallationID: installationID,
+			Owner:          owner,
+			Repo:           repo,
+			SHA:            sha,
+			Tag:            newTag(caption, sha, tagger),
+			TagType:        setting.TagType,
+			RefFormat:      refFormat,
+			Comments:       setting.Comments,
+			Branch:         env.Branch,
+		}
+		if env.Delay > 0 {
+			if TagRetryQueue == nil {
+				logger.Errorf("can't propagate version to environment %q of %s/%s after %s without the retry queue", env.Branch, owner, repo, env.Delay)
+				continue
+			}
+			id := fmt.Sprintf("environment:%s/%s:%s:%s", owner, repo, caption, sha)
+			if err := TagRetryQueue.AddAt(id, job, time.Now().Add(env.Delay)); err != nil {
+				logger.Errorf("retry queue error for %q: %v", id, err)
+				continue
+			}
+			logger.Infof("Environment %q of %s/%s is queued for %s: %q", env.Branch, owner, repo, env.Delay, caption)
+			continue
+		}
+		if err := runTagJob(client, job); err != nil {
+			logger.Errorf("propagation error for %s/%s environment %q: %v", owner, repo, env.Branch, err)
+			addComment(client, setting, owner, repo, sha, fmt.Sprintf("can't add environment tag %q to commit, error : %v", caption, err))
+			continue
+		}
+		logger.Infof("Propagated version to environment %q of %s/%s: %q", env.Branch, owner, repo, caption)
+	}
+}
@@ -0,0 +1,30 @@
+package push
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/smartforce-io/atc/githubservice/gitutil"
+)
+
+// checkTagProtectionBypass returns an error if the app can't create tags
+// covered by tag protection rules in owner/repo. Only the admin and maintain
+// roles can, without them GitHub rejects the tag with a less clear error.
+func checkTagProtectionBypass(client *github.Client, owner, repo string) error {
+	permissions, err := gitutil.InstallationPermissions(client, owner, repo)
+	if err != nil {
+		return fmt.Errorf("can't check the permissions of the ATC app: %w", err)
+	}
+	if permissions["admin"] || permissions["maintain"] {
+		return nil
+	}
+	granted := []string{}
+	for name, ok := range permissions {
+		if ok {
+			granted = append(granted, name)
+		}
+	}
+	sort.Strings(granted)
+	return fmt.Errorf("protected tags need the admin or maintain permission for the ATC app in %s/%s, it has %v", owner, repo, granted)
+}
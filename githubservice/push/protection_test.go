@@ -0,0 +1,61 @@
+package push
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/smartforce-io/atc/envvars"
+	"github.com/smartforce-io/atc/githubservice/provider"
+)
+
+func TestTagProtectionBypass(t *testing.T) {
+	var tests = []struct {
+		repos   string
+		tagged  bool
+		comment string
+	}{
+		{`[{"full_name": "Codertocat/Hello-World", "permissions": {"admin": true}}]`, true, `Added a new version for "Codertocat/Hello-World": "v5"`},
+		{`[{"full_name": "Codertocat/Hello-World", "permissions": {"maintain": true, "push": true}}]`, true, `Added a new version for "Codertocat/Hello-World": "v5"`},
+		{`[{"full_name": "Codertocat/Hello-World", "permissions": {"admin": false, "push": true, "pull": true}}]`, false,
+			`tag "v5" wasn't created: protected tags need the admin or maintain permission for the ATC app in Codertocat/Hello-World, it has [pull push]`},
+		{`[{"full_name": "Codertocat/other", "permissions": {"admin": true}}]`, false,
+			`tag "v5" wasn't created: can't check the permissions of the ATC app: app isn't installed in repository: Codertocat/Hello-World`},
+	}
+
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	for _, test := range tests {
+		mockClientProviderPtr := provider.DefaultMockClientProvider()
+		tagged := false
+		comment := ""
+		mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			return provider.NewTestResponse(200, provider.MockContentResponse("path: pom.xml\ntag_protection_bypass: true"))
+		})
+		mockClientProviderPtr.OverrideResponseFn("LIST_INSTALLATION_REPOS", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			return provider.NewTestResponse(200, fmt.Sprintf(`{"repositories": %s}`, test.repos))
+		})
+		mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			tagged = true
+			return defaultFn(req)
+		})
+		mockClientProviderPtr.OverrideResponseFn("ADD_COMMENT", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			comment = fmt.Sprintf("%v", provider.GetBodyJson(req)["body"])
+			return defaultFn(req)
+		})
+
+		ActionPush(&p, mockClientProviderPtr)
+
+		if tagged != test.tagged {
+			t.Errorf("repos %s: expected tagged %v, got %v", test.repos, test.tagged, tagged)
+		}
+		if comment != test.comment {
+			t.Errorf("repos %s: expected comment %q, got %q", test.repos, test.comment, comment)
+		}
+	}
+}
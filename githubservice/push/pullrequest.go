@@ -0,0 +1,205 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v39/github"
+
+	"github.com/smartforce-io/atc/githubservice/accesstoken"
+	"github.com/smartforce-io/atc/githubservice/gitutil"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/semver"
+	"github.com/smartforce-io/atc/githubservice/settings"
+	"github.com/smartforce-io/atc/logger"
+)
+
+// maxPullRequestFiles limits how many changed files of a pull request are
+// listed, GitHub doesn't return more than 3000.
+const maxPullRequestFiles = 3000
+
+// PullRequestCheck sets the version_bump_check status on the head of a
+// pull request: failure when files matching version_bump_paths changed but
+// the version isn't bumped compared to the base branch.
+func PullRequestCheck(ctx context.Context, event *github.PullRequestEvent, clientProvider provider.ClientProvider) {
+	switch event.GetAction() {
+	case "opened", "reopened", "synchronize":
+	default:
+		return
+	}
+	id := event.GetInstallation().GetID()
+	owner := event.GetRepo().GetOwner().GetLogin()
+	repo := event.GetRepo().GetName()
+	fullname := event.GetRepo().GetFullName()
+	pr := event.GetPullRequest()
+	headSHA := pr.GetHead().GetSHA()
+	log := logger.FromContext(ctx).With("repo", fullname, "installation_id", id, "pull_request", pr.GetNumber())
+	ctx = logger.NewContext(ctx, log)
+
+	token, err := accesstoken.GetAccessToken(id, clientProvider)
+	if err != nil {
+		log.Errorf("getAccessToken Error: %v", err)
+		return
+	}
+	client := clientProvider.Get(token, ctx)
+
+	// the settings of the default branch, a pull request can't turn its check off
+	setting, err := settings.GetAtcSetting(&provider.GhContentProvider{
+		Owner:    owner,
+		Repo:     repo,
+		Ctx:      ctx,
+		GhClient: client,
+	})
+	if err != nil {
+		log.Errorf("settings error: %v", err)
+		return
+	}
+	if setting.VersionBumpCheck == "" {
+		return
+	}
+	branch := setting.Branch
+	if branch == "" {
+		branch = event.GetRepo().GetDefaultBranch()
+	}
+	if !setting.UseBranch(pr.GetBase().GetRef()) && pr.GetBase().GetRef() != branch {
+		log.Debugf("pull request into %q isn't checked", pr.GetBase().GetRef())
+		return
+	}
+
+	state, description, err := checkVersionBump(ctx, client, owner, repo, pr, setting)
+	if err != nil {
+		log.Errorf("version bump check error: %v", err)
+		state, description = "error", err.Error()
+	}
+	if err := gitutil.AddCommitStatus(client, owner, repo, headSHA, state, setting.VersionBumpCheck, description); err != nil {
+		log.Errorf("add commit status error for %q: %v", fullname, err)
+	}
+}
+
+// PullRequestMerged tags the merge commit of a merged pull request for the
+// repositories with trigger pr_merge. The version at the merge commit is
+// compared with the one of the base commit of the pull request.
+func PullRequestMerged(ctx context.Context, event *github.PullRequestEvent, clientProvider provider.ClientProvider) {
+	pr := event.GetPullRequest()
+	if event.GetAction() != "closed" || !pr.GetMerged() || pr.GetMergeCommitSHA() == "" {
+		return
+	}
+	ctx = logger.NewContext(ctx, logger.FromContext(ctx).With("pull_request", pr.GetNumber()))
+	actionPush(ctx, mergedPullRequestPush(event), clientProvider, settings.TriggerPRMerge)
+}
+
+// mergedPullRequestPush returns the push of the merge commit of the pull
+// request of event onto its base branch, pushed by whoever merged it.
+func mergedPullRequestPush(event *github.PullRequestEvent) *github.WebHookPayload {
+	pr := event.GetPullRequest()
+	repo := *event.GetRepo()
+	owner := *repo.GetOwner()
+	// pushes name the owner, pull requests only have its login
+	if owner.Name == nil {
+		owner.Name = owner.Login
+	}
+	repo.Owner = &owner
+	mergedBy := pr.GetMergedBy()
+	if mergedBy == nil {
+		mergedBy = &github.User{Login: github.String(event.GetSender().GetLogin())}
+	}
+	pusher := &github.User{Name: mergedBy.Login, Login: mergedBy.Login, Email: mergedBy.Email}
+	if mergedBy.Name != nil {
+		pusher.Name = mergedBy.Name
+	}
+	return &github.WebHookPayload{
+		Ref:          github.String("refs/heads/" + pr.GetBase().GetRef()),
+		Before:       pr.GetBase().SHA,
+		After:        pr.MergeCommitSHA,
+		Repo:         &repo,
+		Pusher:       pusher,
+		Installation: event.Installation,
+		// the commit directives are read from the pull request
+		HeadCommit: &github.WebHookCommit{
+			ID:      pr.MergeCommitSHA,
+			Message: github.String(strings.TrimSpace(pr.GetTitle() + "\n\n" + pr.GetBody())),
+		},
+	}
+}
+
+// checkVersionBump returns the state and description of the status for pr.
+func checkVersionBump(ctx context.Context, client *github.Client, owner, repo string, pr *github.PullRequest,
+	setting *settings.AtcSettings) (string, string, error) {
+	required, err := needsVersionBump(ctx, client, owner, repo, pr.GetNumber(), setting.VersionBumpPaths)
+	if err != nil {
+		return "", "", err
+	}
+	if !required {
+		return "success", "No version bump needed", nil
+	}
+
+	newVersion, err := fetchVersion(setting, &provider.GhContentProvider{
+		Owner:    owner,
+		Repo:     repo,
+		Ref:      pr.GetHead().GetSHA(),
+		Ctx:      ctx,
+		GhClient: client,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("get new version error: %w", err)
+	}
+	oldVersion, err := fetchVersion(setting, &provider.GhContentProvider{
+		Owner:    owner,
+		Repo:     repo,
+		Ref:      pr.GetBase().GetSHA(),
+		Ctx:      ctx,
+		GhClient: client,
+	})
+	if err != nil && !errors.Is(err, provider.ErrHttpStatusCode) { // the version file may be new
+		return "", "", fmt.Errorf("get prev version error: %w", err)
+	}
+	if setting.NormalizeVersion {
+		oldVersion, newVersion = semver.Normalize(oldVersion), semver.Normalize(newVersion)
+	}
+
+	bump, err := isVersionBump(setting, oldVersion, newVersion)
+	if errors.Is(err, errVersionDowngrade) {
+		return "failure", fmt.Sprintf("Version %q is lower than %q", newVersion, oldVersion), nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+	if !bump {
+		return "failure", fmt.Sprintf("Version %q wasn't bumped", oldVersion), nil
+	}
+	if err := checkVersion(setting, newVersion); err != nil {
+		return "failure", err.Error(), nil
+	}
+	return "success", fmt.Sprintf("Version bumped from %q to %q", oldVersion, newVersion), nil
+}
+
+// needsVersionBump reports whether a file of the pull request matches one of
+// paths. Any change needs a bump when paths is empty.
+func needsVersionBump(ctx context.Context, client *github.Client, owner, repo string, number int, paths []string) (bool, error) {
+	if len(paths) == 0 {
+		return true, nil
+	}
+	opts := &github.ListOptions{PerPage: 100}
+	for listed := 0; listed < maxPullRequestFiles; listed += opts.PerPage {
+		files, resp, err := client.PullRequests.ListFiles(ctx, owner, repo, number, opts)
+		if err != nil {
+			return false, err
+		}
+		for _, file := range files {
+			for _, pattern := range paths {
+				if matched, err := matchGlob(pattern, file.GetFilename()); err != nil {
+					return false, err
+				} else if matched {
+					return true, nil
+				}
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return false, nil
+}
@@ -0,0 +1,175 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v39/github"
+
+	"github.com/smartforce-io/atc/envvars"
+	"github.com/smartforce-io/atc/githubservice/provider"
+)
+
+const testPullRequestPayload = `{
+	"action": "synchronize",
+	"installation": {"id": 8},
+	"repository": {"name": "Hello-World", "full_name": "Codertocat/Hello-World", "default_branch": "main", "owner": {"login": "Codertocat"}},
+	"pull_request": {
+		"number": 2,
+		"base": {"ref": "main", "sha": "1111111111111111111111111111111111111111"},
+		"head": {"ref": "feature", "sha": "2222222222222222222222222222222222222222"}
+	}
+}`
+
+func TestPullRequestCheck(t *testing.T) {
+	e := &github.PullRequestEvent{}
+	if err := json.Unmarshal([]byte(testPullRequestPayload), e); err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	var tests = []struct {
+		headVersion string
+		changedFile string
+		state       string
+		description string
+	}{
+		{"5", "src/main.go", "success", `Version bumped from "4" to "5"`},
+		{"4", "src/main.go", "failure", `Version "4" wasn't bumped`},
+		{"4", "docs/README.md", "success", "No version bump needed"},
+		{"3", "src/main.go", "failure", `Version "3" is lower than "4"`},
+	}
+	for _, test := range tests {
+		mockClientProviderPtr := provider.DefaultMockClientProvider()
+		mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			return provider.NewTestResponse(200, provider.MockContentResponse(`
+path: pom.xml
+version_check: semver
+version_bump_check: atc/version-bump
+version_bump_paths: ["src/**"]`))
+		})
+		mockClientProviderPtr.OverrideResponseFn("GET_OLD_VERSION_MAVEN", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			version := "4"
+			if strings.Contains(req.URL.RawQuery, e.GetPullRequest().GetHead().GetSHA()) {
+				version = test.headVersion
+			}
+			return provider.NewTestResponse(200, provider.MockContentResponse(fmt.Sprintf("<project><version>%s</version></project>", version)))
+		})
+		mockClientProviderPtr.OverrideResponseFn("LIST_PULL_FILES", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			return provider.NewTestResponse(200, fmt.Sprintf(`[{"filename": %q}]`, test.changedFile))
+		})
+		var status map[string]interface{}
+		var statusURL string
+		mockClientProviderPtr.OverrideResponseFn("ADD_STATUS", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			statusURL = req.URL.Path
+			status = provider.GetBodyJson(req)
+			return defaultFn(req)
+		})
+
+		PullRequestCheck(context.Background(), e, mockClientProviderPtr)
+
+		if !strings.HasSuffix(statusURL, "/statuses/"+e.GetPullRequest().GetHead().GetSHA()) {
+			t.Errorf("status isn't set on the head commit: %q", statusURL)
+		}
+		if status["state"] != test.state || status["description"] != test.description || status["context"] != "atc/version-bump" {
+			t.Errorf("head version %q, file %q: expected %s %q, got %v", test.headVersion, test.changedFile, test.state, test.description, status)
+		}
+	}
+}
+
+func TestPullRequestCheckDisabled(t *testing.T) {
+	e := &github.PullRequestEvent{}
+	json.Unmarshal([]byte(testPullRequestPayload), e)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+	mockClientProviderPtr.OverrideResponseFn("ADD_STATUS", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		t.Errorf("unexpected status without version_bump_check")
+		return defaultFn(req)
+	})
+
+	PullRequestCheck(context.Background(), e, mockClientProviderPtr)
+}
+
+const testMergedPullRequestPayload = `{
+	"action": "closed",
+	"installation": {"id": 8},
+	"repository": {"name": "Hello-World", "full_name": "Codertocat/Hello-World", "default_branch": "main", "owner": {"login": "Codertocat"}},
+	"pull_request": {
+		"number": 2,
+		"merged": true,
+		"merge_commit_sha": "3333333333333333333333333333333333333333",
+		"merged_by": {"login": "Codertocat"},
+		"base": {"ref": "main", "sha": "1111111111111111111111111111111111111111"},
+		"head": {"ref": "feature", "sha": "2222222222222222222222222222222222222222"}
+	}
+}`
+
+func TestPullRequestMerged(t *testing.T) {
+	e := &github.PullRequestEvent{}
+	if err := json.Unmarshal([]byte(testMergedPullRequestPayload), e); err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	var tests = []struct {
+		trigger      string
+		mergeVersion string
+		expectedTag  string
+	}{
+		{"pr_merge", "5", "v5"},
+		{"pr_merge", "4", ""},
+		{"push", "5", ""},
+		{"", "5", ""},
+	}
+	for _, test := range tests {
+		mockClientProviderPtr := provider.DefaultMockClientProvider()
+		mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			return provider.NewTestResponse(200, provider.MockContentResponse("path: pom.xml\ntrigger: "+test.trigger))
+		})
+		mockClientProviderPtr.OverrideResponseFn("GET_OLD_VERSION_MAVEN", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			version := "4"
+			if strings.Contains(req.URL.RawQuery, e.GetPullRequest().GetMergeCommitSHA()) {
+				version = test.mergeVersion
+			}
+			return provider.NewTestResponse(200, provider.MockContentResponse(fmt.Sprintf("<project><version>%s</version></project>", version)))
+		})
+		tag, sha := "", ""
+		mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			j := provider.GetBodyJson(req)
+			tag, sha = fmt.Sprint(j["tag"]), fmt.Sprint(j["object"])
+			return provider.NewTestResponse(201, `{}`)
+		})
+
+		PullRequestMerged(context.Background(), e, mockClientProviderPtr)
+
+		if tag != test.expectedTag {
+			t.Errorf("trigger %q, merge version %q: expected tag %q, got %q", test.trigger, test.mergeVersion, test.expectedTag, tag)
+		}
+		if tag != "" && sha != e.GetPullRequest().GetMergeCommitSHA() {
+			t.Errorf("trigger %q: expected tag on the merge commit, got %s", test.trigger, sha)
+		}
+	}
+}
+
+func TestPushWithPullRequestMergeTrigger(t *testing.T) {
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+	mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		return provider.NewTestResponse(200, provider.MockContentResponse("path: pom.xml\ntrigger: pr_merge"))
+	})
+	mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		t.Errorf("unexpected tag of a push with trigger pr_merge")
+		return defaultFn(req)
+	})
+
+	ActionPush(&p, mockClientProviderPtr)
+}
@@ -5,42 +5,168 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"text/template"
 	"time"
+	"unicode/utf8"
 
+	"github.com/smartforce-io/atc/envvars"
 	"github.com/smartforce-io/atc/githubservice/accesstoken"
 	"github.com/smartforce-io/atc/githubservice/fetcher"
 	"github.com/smartforce-io/atc/githubservice/fetcher/customregex"
+	"github.com/smartforce-io/atc/githubservice/fetcher/npmrc"
 	"github.com/smartforce-io/atc/githubservice/fetcher/packagejson"
+	"github.com/smartforce-io/atc/githubservice/fetcher/packagejson/npmshrinkwrap"
+	"github.com/smartforce-io/atc/githubservice/fetcher/packervars"
+	"github.com/smartforce-io/atc/githubservice/fetcher/perl/distini"
+	"github.com/smartforce-io/atc/githubservice/fetcher/perl/makefilepl"
+	"github.com/smartforce-io/atc/githubservice/fetcher/settingsgradle"
+	"github.com/smartforce-io/atc/githubservice/fetcher/terraform"
+	"github.com/smartforce-io/atc/githubservice/fetcher/versioncatalog"
 	"github.com/smartforce-io/atc/githubservice/fetcher/yaml/pluginyaml"
+	"github.com/smartforce-io/atc/githubservice/fetcher/yaml/pubspeclock"
 	"github.com/smartforce-io/atc/githubservice/fetcher/yaml/pubspecyaml"
+	"github.com/smartforce-io/atc/githubservice/fetcher/yaml/requirementsyaml"
+	"github.com/smartforce-io/atc/githubservice/fetcher/yaml/shardyml"
+	"github.com/smartforce-io/atc/githubservice/fetcher/yaml/yamlpath"
 	"github.com/smartforce-io/atc/githubservice/gitutil"
+	"github.com/smartforce-io/atc/githubservice/notification"
 	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/semver"
 	"github.com/smartforce-io/atc/githubservice/settings"
-	"golang.org/x/oauth2"
+	"github.com/smartforce-io/atc/githubservice/tagsign"
 
 	"github.com/google/go-github/v39/github"
 
 	"github.com/smartforce-io/atc/githubservice/fetcher/buildgradle"
+	"github.com/smartforce-io/atc/githubservice/fetcher/bundlerlock"
+	"github.com/smartforce-io/atc/githubservice/fetcher/cabal"
+	"github.com/smartforce-io/atc/githubservice/fetcher/cargotoml"
+	"github.com/smartforce-io/atc/githubservice/fetcher/composerjson"
+	"github.com/smartforce-io/atc/githubservice/fetcher/composerlock"
+	"github.com/smartforce-io/atc/githubservice/fetcher/condameta"
+	"github.com/smartforce-io/atc/githubservice/fetcher/deno"
+	"github.com/smartforce-io/atc/githubservice/fetcher/dotnetproject"
+	"github.com/smartforce-io/atc/githubservice/fetcher/duneproject"
+	"github.com/smartforce-io/atc/githubservice/fetcher/gemspec"
+	"github.com/smartforce-io/atc/githubservice/fetcher/ghrelease"
+	"github.com/smartforce-io/atc/githubservice/fetcher/goversion"
+	"github.com/smartforce-io/atc/githubservice/fetcher/gradleproperties"
+	"github.com/smartforce-io/atc/githubservice/fetcher/juliaproject"
+	"github.com/smartforce-io/atc/githubservice/fetcher/kotlinconfig"
+	"github.com/smartforce-io/atc/githubservice/fetcher/mixexs"
+	"github.com/smartforce-io/atc/githubservice/fetcher/plaintext"
 	"github.com/smartforce-io/atc/githubservice/fetcher/pomxml"
+	"github.com/smartforce-io/atc/githubservice/fetcher/pyproject"
+	"github.com/smartforce-io/atc/githubservice/fetcher/rdescription"
+	"github.com/smartforce-io/atc/githubservice/fetcher/rockspec"
+	"github.com/smartforce-io/atc/githubservice/fetcher/sbt"
+	"github.com/smartforce-io/atc/githubservice/fetcher/setuppy"
+	"github.com/smartforce-io/atc/githubservice/fetcher/zigzon"
+	"github.com/smartforce-io/atc/logger"
 )
 
 type TagContent struct {
-	Version string
+	Version    string
+	PreRelease bool
+	Tag        string
+	Repository string
+	Error      string
+	// Actor is the pusher of the tagged commit.
+	Actor string
+	// SHA is the tagged commit, ShortSHA its first 7 characters and Branch
+	// the pushed branch. They're empty in CI mode and for GitLab and
+	// Bitbucket.
+	SHA      string
+	ShortSHA string
+	Branch   string
+	// Major, Minor and Patch are the numbers of a semantic Version.
+	Major int
+	Minor int
+	Patch int
+	// VersionName and VersionCode are set for an Android build.gradle.
+	VersionName string
+	VersionCode string
+	// Changelog lists the commits since the previous tag with changelog on.
+	Changelog string
 }
 
 var autoFetchers = map[string]fetcher.VersionFetcher{
-	"pom.xml":      &pomxml.Fetcher{},
-	"build.gradle": &buildgradle.Fetcher{},
-	"package.json": &packagejson.Fetcher{},
-	"pubspec.yaml": &pubspecyaml.Fetcher{},
-	"plugin.yaml":  &pluginyaml.Fetcher{},
+	"pom.xml":               &pomxml.Fetcher{},
+	"build.gradle":          &buildgradle.Fetcher{},
+	"build.gradle.kts":      buildgradle.NewKotlinFetcher(),
+	"gradle.properties":     &gradleproperties.Fetcher{},
+	"package.json":          &packagejson.Fetcher{},
+	"pubspec.yaml":          &pubspecyaml.Fetcher{},
+	"plugin.yaml":           &pluginyaml.Fetcher{},
+	"settings.gradle":       &settingsgradle.Fetcher{},
+	"version.go":            &goversion.Fetcher{},
+	"meta.yaml":             &condameta.Fetcher{},
+	".version":              &plaintext.Fetcher{},
+	"VERSION":               plaintext.NewVersionFileFetcher(),
+	"requirements.yaml":     &requirementsyaml.Fetcher{},
+	"deno.json":             &deno.Fetcher{},
+	".npmrc":                &npmrc.Fetcher{},
+	"npm-shrinkwrap.json":   &npmshrinkwrap.Fetcher{},
+	"Gemfile.lock":          &bundlerlock.Fetcher{},
+	"composer.lock":         &composerlock.Fetcher{},
+	"composer.json":         &composerjson.Fetcher{},
+	"build.zig.zon":         &zigzon.Fetcher{},
+	"dune-project":          &duneproject.Fetcher{},
+	"Makefile.PL":           &makefilepl.Fetcher{},
+	"dist.ini":              &distini.Fetcher{},
+	"build.sbt":             &sbt.Fetcher{},
+	".cabal":                &cabal.Fetcher{},
+	"DESCRIPTION":           &rdescription.Fetcher{},
+	"Project.toml":          &juliaproject.Fetcher{},
+	".pkrvars.hcl":          &packervars.Fetcher{},
+	".rockspec":             &rockspec.Fetcher{},
+	".csproj":               &dotnetproject.Fetcher{},
+	".fsproj":               &dotnetproject.Fetcher{},
+	".vbproj":               &dotnetproject.Fetcher{},
+	"Directory.Build.props": dotnetproject.NewBuildPropsFetcher(),
+	"shard.yml":             &shardyml.Fetcher{},
+	"Config.kt":             &kotlinconfig.Fetcher{},
+	"libs.versions.toml":    versioncatalog.NewAndroidFetcher(),
+	"variables.tf":          &terraform.Fetcher{},
+	"pubspec.lock":          &pubspeclock.Fetcher{},
+	"Cargo.toml":            &cargotoml.Fetcher{},
+	"pyproject.toml":        &pyproject.Fetcher{},
+	"setup.py":              &setuppy.Fetcher{},
+	"mix.exs":               &mixexs.Fetcher{},
+	".gemspec":              &gemspec.Fetcher{},
+	"version.rb":            gemspec.NewVersionRbFetcher(),
 }
 
+// autoFetcherOrder is the order the default paths of autoFetchers are tried
+// in without a configured path, so the same file wins on every push.
+var autoFetcherOrder = []string{
+	"pom.xml", "build.gradle", "build.gradle.kts", "gradle.properties", "package.json",
+	"pubspec.yaml", "plugin.yaml", "settings.gradle", "version.go", "meta.yaml", ".version",
+	"VERSION", "requirements.yaml", "deno.json", ".npmrc", "npm-shrinkwrap.json", "Gemfile.lock",
+	"composer.lock", "composer.json", "build.zig.zon", "dune-project", "Makefile.PL", "dist.ini",
+	"build.sbt", ".cabal", "DESCRIPTION", "Project.toml", ".pkrvars.hcl", ".rockspec", ".csproj",
+	".fsproj", ".vbproj", "Directory.Build.props", "shard.yml", "Config.kt", "libs.versions.toml",
+	"variables.tf", "pubspec.lock", "Cargo.toml", "pyproject.toml", "setup.py", "mix.exs", ".gemspec",
+	"version.rb",
+}
+
+func init() {
+	// the built-in fetchers are measured with their key, e.g. ".csproj" for
+	// every project file, whoever calls them
+	for name, versionFetcher := range autoFetchers {
+		autoFetchers[name] = fetcher.NewInstrumentedFetcher(name, versionFetcher)
+	}
+}
+
+// extensionTypes are the autoFetchers keys that match every file with the
+// extension, e.g. "atc.cabal" or "image.auto.pkrvars.hcl".
+var extensionTypes = map[string]bool{".cabal": true, ".pkrvars.hcl": true, ".rockspec": true,
+	".csproj": true, ".fsproj": true, ".vbproj": true, ".gemspec": true}
+
 func detectFetchType(path string) string {
 	if path == "" {
 		return ""
@@ -48,13 +174,120 @@ func detectFetchType(path string) string {
 	return filepath.Base(path)
 }
 
+// customFilesType is the fetch type of settings with custom_files instead of
+// a path.
+const customFilesType = "custom_files"
+
+// settingsFetchType returns the fetch type of the configured path,
+// customFilesType for custom_files or "" for the default paths.
+func settingsFetchType(atcSettings *settings.AtcSettings) string {
+	if atcSettings.Path == "" && len(atcSettings.CustomFiles) > 0 {
+		return customFilesType
+	}
+	return detectFetchType(atcSettings.Path)
+}
+
+// fetcherForType returns the fetcher for a configured path, or nil if the
+// path needs the custom regex fetcher.
+func fetcherForType(fetchType string) fetcher.VersionFetcher {
+	if fetchType == ghrelease.LatestReleasePath {
+		return &ghrelease.Fetcher{}
+	}
+	if versionFetcher, ok := autoFetchers[fetchType]; ok {
+		return versionFetcher
+	}
+	for ext := range extensionTypes {
+		if strings.HasSuffix(fetchType, ext) {
+			return autoFetchers[ext]
+		}
+	}
+	return nil
+}
+
+// configuredFetcher returns the fetcher for the path and key path set in
+// settings, or nil if it can't be told from them.
+func configuredFetcher(atcSettings *settings.AtcSettings) fetcher.VersionFetcher {
+	if settingsFetchType(atcSettings) == customFilesType {
+		return &customregex.Fetcher{}
+	}
+	if isGlobPath(atcSettings.Path) {
+		return &globFetcher{}
+	}
+	if atcSettings.YAMLPath != "" && settings.IsYAMLPath(atcSettings.Path) {
+		return &yamlpath.Fetcher{}
+	}
+	return fetcherForType(detectFetchType(atcSettings.Path))
+}
+
+// instrumented records the fetch durations of a fetcher that isn't one of
+// autoFetchers, e.g. the custom regex or glob fetcher, as "custom" so that
+// the metric labels stay few. The autoFetchers are measured already.
+func instrumented(versionFetcher fetcher.VersionFetcher) fetcher.VersionFetcher {
+	if _, ok := versionFetcher.(*fetcher.InstrumentedVersionFetcher); ok {
+		return versionFetcher
+	}
+	return fetcher.NewInstrumentedFetcher("custom", versionFetcher)
+}
+
+// normalizedVersion wraps getVersion to return semver.Normalize versions.
+func normalizedVersion(getVersion func(provider.ContentProvider) (string, error)) func(provider.ContentProvider) (string, error) {
+	return func(cp provider.ContentProvider) (string, error) {
+		version, err := getVersion(cp)
+		return semver.Normalize(version), err
+	}
+}
+
 func renderTagNameTemplate(templateString, version string) (string, error) {
+	return renderTemplate(templateString, TagContent{Version: version})
+}
+
+// shortSHALength is the length of {{.ShortSHA}}, the default of git.
+const shortSHALength = 7
+
+// templateFuncs are the functions of the tag and comment templates. date
+// formats the current UTC time with a Go layout, e.g. {{date "20060102"}}.
+// The string functions take the string last, so they work in pipelines like
+// {{.Branch | replace "/" "-"}}.
+var templateFuncs = template.FuncMap{
+	"Time":       func() time.Time { return time.Now() },
+	"date":       func(layout string) string { return time.Now().UTC().Format(layout) },
+	"upper":      strings.ToUpper,
+	"lower":      strings.ToLower,
+	"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+	"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+	"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+	"shortSHA":   shortSHA,
+	"major":      versionNumber(func(v semver.Version) int { return v.Major }),
+	"minor":      versionNumber(func(v semver.Version) int { return v.Minor }),
+	"patch":      versionNumber(func(v semver.Version) int { return v.Patch }),
+}
+
+// versionNumber returns a template function with the number of a semantic
+// version, it fails for other versions.
+func versionNumber(number func(semver.Version) int) func(string) (int, error) {
+	return func(version string) (int, error) {
+		v, err := semver.Parse(version)
+		if err != nil {
+			return 0, err
+		}
+		return number(v), nil
+	}
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > shortSHALength {
+		return sha[:shortSHALength]
+	}
+	return sha
+}
+
+func renderTemplate(templateString string, tagContent TagContent) (string, error) {
 	buf := new(bytes.Buffer)
-	tagContent := TagContent{version}
-	tmplFuncMap := template.FuncMap{
-		"Time": func() time.Time { return time.Now() },
+	if v, err := semver.Parse(tagContent.Version); err == nil {
+		tagContent.Major, tagContent.Minor, tagContent.Patch = v.Major, v.Minor, v.Patch
 	}
-	tmpl, err := template.New("template tagContent").Funcs(tmplFuncMap).Parse(templateString)
+	tagContent.ShortSHA = shortSHA(tagContent.SHA)
+	tmpl, err := template.New("template tagContent").Funcs(templateFuncs).Parse(templateString)
 	if err != nil {
 		return "", err
 	}
@@ -65,6 +298,72 @@ func renderTagNameTemplate(templateString, version string) (string, error) {
 	return buf.String(), nil
 }
 
+// checkVersion reports whether version can be tagged with settings.
+func checkVersion(atcSettings *settings.AtcSettings, version string) error {
+	maxVersionLength := atcSettings.MaxVersionLength
+	if maxVersionLength == 0 {
+		maxVersionLength = settings.DefaultMaxVersionLength
+	}
+	if len(version) > maxVersionLength {
+		return fmt.Errorf("version from %q is %d characters long, max_version_length is %d", atcSettings.Path, len(version), maxVersionLength)
+	}
+	if atcSettings.VersionFormat != "" {
+		versionFormat, err := regexp.Compile(atcSettings.VersionFormat)
+		if err != nil {
+			return fmt.Errorf("wrong version_format: %v", err)
+		}
+		if !versionFormat.MatchString(version) {
+			return fmt.Errorf("version %q doesn't match version_format %s", version, atcSettings.VersionFormat)
+		}
+	}
+	return nil
+}
+
+// androidVersions reads the versionName and versionCode of a configured
+// build.gradle or build.gradle.kts for the templates. cp has the file from
+// reading the version already, it isn't fetched again.
+func androidVersions(cp provider.ContentProvider, atcSettings *settings.AtcSettings) (versionName, versionCode string) {
+	if fetchType := detectFetchType(atcSettings.Path); fetchType != "build.gradle" && fetchType != "build.gradle.kts" {
+		return "", ""
+	}
+	content, err := cp.GetContents(atcSettings.Path)
+	if err != nil {
+		return "", ""
+	}
+	return buildgradle.AndroidVersions([]byte(content))
+}
+
+// addComment comments a failure on sha unless the comments setting is off.
+func addComment(client *github.Client, setting *settings.AtcSettings, owner, repo, sha, text string) {
+	if setting.Comments == settings.CommentsOff {
+		return
+	}
+	gitutil.AddComment(client, owner, repo, sha, text)
+}
+
+// addInfoComment comments a created or skipped tag on sha when the comments
+// setting is always.
+func addInfoComment(client *github.Client, setting *settings.AtcSettings, owner, repo, sha, text string) {
+	if setting.Comments != "" && setting.Comments != settings.CommentsAlways {
+		return
+	}
+	gitutil.AddComment(client, owner, repo, sha, text)
+}
+
+// renderComment renders a commit comment template, falling back to the
+// default text when the template is empty or broken.
+func renderComment(templateString, defaultText string, tagContent TagContent) string {
+	if templateString == "" {
+		return defaultText
+	}
+	comment, err := renderTemplate(templateString, tagContent)
+	if err != nil {
+		logger.Warnf("error in comment template: %v", err)
+		return defaultText
+	}
+	return comment
+}
+
 func getShaByBehavior(push *github.WebHookPayload, behavior string) *string {
 	if strings.ToLower(behavior) == settings.BehaviorBefore {
 		return push.Before
@@ -72,6 +371,14 @@ func getShaByBehavior(push *github.WebHookPayload, behavior string) *string {
 	return push.After
 }
 
+// settingsTrigger returns the trigger of settings, push by default.
+func settingsTrigger(setting *settings.AtcSettings) string {
+	if setting.Trigger == "" {
+		return settings.TriggerPush
+	}
+	return setting.Trigger
+}
+
 func createBranchToClientProvider(settings *settings.AtcSettings, push *github.WebHookPayload) string {
 	if settings.Branch != "" {
 		return settings.Branch
@@ -80,25 +387,43 @@ func createBranchToClientProvider(settings *settings.AtcSettings, push *github.W
 }
 
 func ActionPush(push *github.WebHookPayload, clientProvider provider.ClientProvider) {
+	ActionPushContext(context.Background(), push, clientProvider)
+}
+
+// ActionPushContext is ActionPush logging with the Logger of ctx, e.g. one
+// with the delivery ID of the webhook. The repository, installation ID and
+// pushed SHA are added to its fields.
+func ActionPushContext(ctx context.Context, push *github.WebHookPayload, clientProvider provider.ClientProvider) {
+	actionPush(ctx, push, clientProvider, settings.TriggerPush)
+}
+
+// actionPush tags the version change of push when the trigger of the
+// repository settings is trigger.
+func actionPush(ctx context.Context, push *github.WebHookPayload, clientProvider provider.ClientProvider, trigger string) {
 	id := *push.Installation.ID
+	owner := push.GetRepo().GetOwner().GetName()
+	repo := push.GetRepo().GetName()
+	fullname := push.GetRepo().GetFullName()
+	log := logger.FromContext(ctx).With("repo", fullname, "installation_id", id, "sha", push.GetAfter())
+	ctx = logger.NewContext(ctx, log)
 
 	token, err := accesstoken.GetAccessToken(id, clientProvider)
 	if err != nil {
-		log.Printf("getAccessToken Error: %v", err)
+		log.Errorf("getAccessToken Error: %v", err)
 		return
 	}
-	owner := push.GetRepo().GetOwner().GetName()
-	repo := push.GetRepo().GetName()
-	fullname := push.GetRepo().GetFullName()
-	ctx := context.Background()
 	client := clientProvider.Get(token, ctx)
 
-	ghOldContentProviderPtr := &provider.GhContentProvider{
-		Owner:    owner,
-		Repo:     repo,
-		Ref:      push.GetBefore(),
-		Ctx:      ctx,
-		GhClient: client,
+	// the first push to a branch has no previous commit to read the old version from
+	var ghOldContentProviderPtr provider.ContentProvider
+	if !isZeroSHA(push.GetBefore()) {
+		ghOldContentProviderPtr = &provider.GhContentProvider{
+			Owner:    owner,
+			Repo:     repo,
+			Ref:      push.GetBefore(),
+			Ctx:      ctx,
+			GhClient: client,
+		}
 	}
 	ghNewContentProviderPtr := &provider.GhContentProvider{
 		Owner:    owner,
@@ -109,142 +434,604 @@ func ActionPush(push *github.WebHookPayload, clientProvider provider.ClientProvi
 
 	setting, err := settings.GetAtcSetting(ghNewContentProviderPtr)
 	if err != nil {
-		log.Println("err. send user: ", err)
+		log.Errorf("err. send user: %v", err)
+		gitutil.AddComment(client, owner, repo, push.GetAfter(), fmt.Sprint(err))
+		return
+	}
+	if repository, _, err := client.Repositories.Get(ctx, owner, repo); err != nil {
+		log.Warnf("can't get topics of %q: %v", fullname, err)
+	} else if err := setting.ApplyTopics(repository.Topics); err != nil {
+		log.Errorf("err. send user: %v", err)
 		gitutil.AddComment(client, owner, repo, push.GetAfter(), fmt.Sprint(err))
 		return
 	}
+	if settingsTrigger(setting) != trigger {
+		log.Debugf("%q is tagged on %s events, %s skipped", fullname, settingsTrigger(setting), trigger)
+		return
+	}
+	// a bot pushing a bumped version file back would tag the version again
+	if pusher := push.GetPusher().GetName(); setting.IsBot(pusher) {
+		log.Warnf("skip push of %q by bot %q", fullname, pusher)
+		return
+	}
 
-	ghNewContentProviderPtr.Ref = createBranchToClientProvider(setting, push)
-	if push.GetRef() != "refs/heads/"+ghNewContentProviderPtr.Ref { // checking which branch is in work
+	branch := strings.TrimPrefix(push.GetRef(), "refs/heads/")
+	branchSettings := setting.UseBranch(branch)
+	if channelName, channel := setting.ChannelForBranch(branch); channel != nil {
+		log.Debugf("branch %q of %q uses channel %q", branch, fullname, channelName)
+		setting.UseChannel(channel)
+		ghNewContentProviderPtr.Ref = branch
+	} else if branchSettings {
+		log.Debugf("branch %q of %q uses its branches settings", branch, fullname)
+		ghNewContentProviderPtr.Ref = branch
+	} else {
+		ghNewContentProviderPtr.Ref = createBranchToClientProvider(setting, push)
+		if push.GetRef() != "refs/heads/"+ghNewContentProviderPtr.Ref { // checking which branch is in work
+			return
+		}
+	}
+	if trigger == settings.TriggerPRMerge {
+		// the branch may have moved on since the merge
+		ghNewContentProviderPtr.Ref = push.GetAfter()
+	}
+	if setting.TagAnnotatorLogin != "" {
+		if author, err := userAuthor(ctx, client, setting.TagAnnotatorLogin); err != nil {
+			log.Warnf("can't get the tag_annotator_login user: %v", err)
+		} else {
+			setting.TaggerName, setting.TaggerEmail = author.GetName(), author.GetEmail()
+		}
+	}
+	if setting.TaggerName == "" || setting.TaggerEmail == "" {
+		// tag as the app like the commits it makes, not as whoever pushed
+		if bot, err := appBotAuthor(ctx, clientProvider, client); err != nil {
+			switch {
+			case setting.TaggerName == "" && setting.TaggerEmail == "":
+				log.Warnf("can't get the bot user of the app, tagging as the pusher: %v", err)
+			case setting.TaggerEmail == "":
+				log.Warnf("can't get the bot user of the app, tagging with the email of the pusher: %v", err)
+			default:
+				log.Warnf("can't get the bot user of the app, tagging with the name of the pusher: %v", err)
+			}
+		} else {
+			if setting.TaggerName == "" {
+				setting.TaggerName = bot.GetName()
+			}
+			if setting.TaggerEmail == "" {
+				setting.TaggerEmail = bot.GetEmail()
+			}
+		}
+	}
+	if setting.NPMWorkspaces {
+		targets, err := npmWorkspaceTargets(ghNewContentProviderPtr)
+		if err != nil {
+			log.Errorf("npm workspaces error for %q: %v", fullname, err)
+			addComment(client, setting, owner, repo, push.GetAfter(), fmt.Sprintf("can't read npm workspaces: %v", err))
+			return
+		}
+		setting.Targets = append(setting.Targets, targets...)
+		if len(setting.Targets) == 0 {
+			log.Warnf("%q has no npm workspace packages", fullname)
+			return
+		}
+	}
+
+	for _, targetSetting := range setting.TargetSettings() {
+		tagPushedVersion(ctx, clientProvider, client, token, push, targetSetting, ghOldContentProviderPtr, ghNewContentProviderPtr)
+	}
+}
+
+// tagPushedVersion compares the versions of the file in setting before and
+// after the push and tags the new version.
+func tagPushedVersion(ctx context.Context, clientProvider provider.ClientProvider, client *github.Client, token string, push *github.WebHookPayload, setting *settings.AtcSettings,
+	ghOldContentProviderPtr provider.ContentProvider, ghNewContentProviderPtr *provider.GhContentProvider) {
+	log := logger.FromContext(ctx)
+	id := push.GetInstallation().GetID()
+	owner := push.GetRepo().GetOwner().GetName()
+	repo := push.GetRepo().GetName()
+	fullname := push.GetRepo().GetFullName()
+
+	tagAll := setting.TagAllCommits && len(push.Commits) > 0
+	directive := ""
+	if setting.CommitDirectives && !tagAll {
+		directive = commitDirective(headCommitMessage(push))
+	}
+	if directive == DirectiveSkip {
+		log.Infof("head commit of %q has %s, skipped", fullname, DirectiveSkip)
 		return
 	}
 
 	commitComment := ""
 	newVersion := ""
 	oldVersion := ""
-	fetchType := detectFetchType(setting.Path)
+	var getVersion func(provider.ContentProvider) (string, error)
+	fetchType := settingsFetchType(setting)
 
 	if fetchType != "" {
 		var err error
 		var reqError *provider.RequestError
-		versionFetcher := autoFetchers[fetchType]
+		versionFetcher := configuredFetcher(setting)
+		if versionFetcher == nil && setting.RegexStr == "" {
+			versionFetcher = sniffFetcher(ghNewContentProviderPtr, setting.Path)
+		}
 		if versionFetcher == nil { //not default file
 			if setting.RegexStr == "" {
-				gitutil.AddComment(client, owner, repo, push.GetAfter(), fmt.Sprintf(".atc.yaml don't have regexstr for not default package manager file %s.", fetchType))
+				addComment(client, setting, owner, repo, push.GetAfter(), fmt.Sprintf(".atc.yaml don't have regexstr for not default package manager file %s.", fetchType))
 				return
 			}
 			versionFetcher = &customregex.Fetcher{}
 		} else {
-			if setting.RegexStr != "" {
+			if setting.RegexStr != "" && !isGlobPath(setting.Path) {
 				commitComment += fmt.Sprintf("Used default regexStr in file %s. ", fetchType)
 			}
 		}
-		oldVersion, err = versionFetcher.GetVersion(ghOldContentProviderPtr, *setting)
+		versionFetcher = instrumented(versionFetcher)
+		getVersion = func(cp provider.ContentProvider) (string, error) {
+			return versionFetcher.GetVersion(cp, *setting)
+		}
+		if ghOldContentProviderPtr != nil {
+			oldVersion, err = getVersion(ghOldContentProviderPtr)
+		}
+		if errors.Is(err, fetcher.ErrVersionUnchanged) {
+			log.Infof("version of %q isn't taken from %s, skipped", fullname, fetchType)
+			return
+		}
 		if err != nil && !errors.Is(err, provider.ErrHttpStatusCode) { //ignore http api error
-			log.Printf("get prev version error for %q: %v", fullname, err)
+			log.Errorf("get prev version error for %q: %v", fullname, err)
 			if errors.Is(err, fetcher.ErrNoVers) || errors.Is(err, fetcher.ErrNoGroupInConf) {
-				gitutil.AddComment(client, owner, repo, push.GetAfter(), fmt.Sprintf("file %s with old version err: %v", fetchType, err))
+				addComment(client, setting, owner, repo, push.GetAfter(), fmt.Sprintf("file %s with old version err: %v", fetchType, err))
 			} else {
-				gitutil.AddComment(client, owner, repo, push.GetAfter(), fmt.Sprintf("file %s with old version not found", fetchType))
+				addComment(client, setting, owner, repo, push.GetAfter(), fmt.Sprintf("file %s with old version not found", fetchType))
 			}
 			return
 		}
-		newVersion, err = versionFetcher.GetVersion(ghNewContentProviderPtr, *setting)
+		newVersion, err = getVersion(ghNewContentProviderPtr)
+		if errors.Is(err, fetcher.ErrVersionUnchanged) {
+			log.Infof("version of %q isn't taken from %s, skipped", fullname, fetchType)
+			return
+		}
 		if err != nil {
 			if errors.Is(err, provider.ErrHttpStatusCode) {
-				log.Printf("Wrong access status during getContent for installation %d for %q: %d", id, fullname, reqError.StatusCode)
+				log.Errorf("Wrong access status during getContent for installation %d for %q: %d", id, fullname, reqError.StatusCode)
 			} else if errors.Is(err, fetcher.ErrNoVers) || errors.Is(err, fetcher.ErrNoGroupInConf) {
-				log.Printf("get version error for %q: %v", fullname, err)
-				gitutil.AddComment(client, owner, repo, push.GetAfter(), fmt.Sprintf("file %s with new version err: %v", fetchType, err))
+				log.Errorf("get version error for %q: %v", fullname, err)
+				addComment(client, setting, owner, repo, push.GetAfter(), fmt.Sprintf("file %s with new version err: %v", fetchType, err))
 			} else {
-				log.Printf("get version error for %q: %v", fullname, err)
-				gitutil.AddComment(client, owner, repo, push.GetAfter(), fmt.Sprintf("file %s with new version not found", fetchType))
+				log.Errorf("get version error for %q: %v", fullname, err)
+				addComment(client, setting, owner, repo, push.GetAfter(), fmt.Sprintf("file %s with new version not found", fetchType))
 			}
 			return
 		}
 	} else {
 		commitComment = `File .atc.yaml not found or path = "". `
 		fetched := false
-		for defaultPath, versionFetcher := range autoFetchers {
+		for _, defaultPath := range autoFetcherOrder {
+			versionFetcher := autoFetchers[defaultPath]
 			var err error
-			oldVersion, err = versionFetcher.GetVersionUsingDefaultPath(ghOldContentProviderPtr)
+			if ghOldContentProviderPtr != nil {
+				oldVersion, err = versionFetcher.GetVersionUsingDefaultPath(ghOldContentProviderPtr)
+			}
 			if err != nil && !errors.Is(err, provider.ErrHttpStatusCode) { //ignore http api error
-				log.Printf("get prev version error for %q, default path: %s, err: %v", fullname, defaultPath, err)
+				log.Debugf("get prev version error for %q, default path: %s, err: %v", fullname, defaultPath, err)
 				continue
 			}
 
 			newVersion, err = versionFetcher.GetVersionUsingDefaultPath(ghNewContentProviderPtr)
 			if err == nil {
 				fetched = true
+				getVersion = versionFetcher.GetVersionUsingDefaultPath
 				commitComment += "Used default settings. "
 				break
 			} else {
-				log.Printf("autofetcher error for %q: %v", defaultPath, err)
+				log.Debugf("autofetcher error for %q: %v", defaultPath, err)
 			}
 		}
 		if !fetched {
 			commitComment += "Not found supported package manager."
-			gitutil.AddComment(client, owner, repo, push.GetAfter(), commitComment)
-			log.Printf("Unable to fetch version using known methods!") //probably should be comment
+			addComment(client, setting, owner, repo, push.GetAfter(), commitComment)
+			log.Warnf("Unable to fetch version using known methods!") //probably should be comment
 			return
 		}
 	}
 
-	if newVersion != oldVersion {
-		log.Printf("There is a new version for %q! Old version: %q, new version: %q", fullname, oldVersion, newVersion)
-		caption, err := renderTagNameTemplate(setting.Template, newVersion)
+	if setting.NormalizeVersion {
+		oldVersion, newVersion = semver.Normalize(oldVersion), semver.Normalize(newVersion)
+		getVersion = normalizedVersion(getVersion)
+	}
+
+	tagger := pushTagger(push, setting)
+
+	if tagAll {
+		tagAllCommits(ctx, clientProvider, client, token, push, setting, getVersion, oldVersion, tagger, commitComment)
+		return
+	}
+
+	bump, err := isVersionBump(setting, oldVersion, newVersion)
+	if err != nil {
+		log.Warnf("version check error for %q: %v", fullname, err)
+		if !errors.Is(err, errVersionDowngrade) || setting.CommentOnDowngrade {
+			addComment(client, setting, owner, repo, push.GetAfter(), fmt.Sprintf("tag wasn't created: %v", err))
+		}
+		return
+	}
+	if !bump && directive == DirectiveTag {
+		log.Infof("head commit of %q has %s, tagging unchanged version %q", fullname, DirectiveTag, newVersion)
+		bump = true
+	}
+	if bump {
+		log.Debugf("There is a new version for %q! Old version: %q, new version: %q", fullname, oldVersion, newVersion)
+		if setting.LabelOverrides {
+			var tag bool
+			if newVersion, tag = applyPRLabels(client, push, setting, oldVersion, newVersion); !tag {
+				return
+			}
+		}
+		sha := tagSHA(client, push, setting, ghNewContentProviderPtr.Ref)
+		tagVersion(ctx, clientProvider, client, token, push, setting, ghNewContentProviderPtr, oldVersion, newVersion, sha, tagger, commitComment)
+	}
+}
+
+// tagAllCommits walks the pushed commits in order and tags every commit
+// whose version differs from the one before it. With CommitDirectives the
+// directive in the message of each commit applies to it.
+func tagAllCommits(ctx context.Context, clientProvider provider.ClientProvider, client *github.Client, token string, push *github.WebHookPayload, setting *settings.AtcSettings,
+	getVersion func(provider.ContentProvider) (string, error), oldVersion string, tagger *github.CommitAuthor, commitComment string) {
+	log := logger.FromContext(ctx)
+	fullname := push.GetRepo().GetFullName()
+	prevVersion := oldVersion
+	for _, commit := range push.Commits {
+		cp := &provider.GhContentProvider{
+			Owner:    push.GetRepo().GetOwner().GetName(),
+			Repo:     push.GetRepo().GetName(),
+			Ref:      commit.GetID(),
+			Ctx:      ctx,
+			GhClient: client,
+		}
+		version, err := getVersion(cp)
 		if err != nil {
-			log.Printf("error in go templates: %v", err)
+			log.Errorf("get version error for %q at %s: %v", fullname, commit.GetID(), err)
+			continue
+		}
+		directive := ""
+		if setting.CommitDirectives {
+			directive = commitDirective(commit.GetMessage())
+		}
+		if directive == DirectiveSkip {
+			log.Infof("commit %s of %q has %s, skipped", commit.GetID(), fullname, DirectiveSkip)
+			prevVersion = version
+			continue
+		}
+		bump, err := isVersionBump(setting, prevVersion, version)
+		if err != nil {
+			log.Warnf("version check error for %q at %s: %v", fullname, commit.GetID(), err)
+			if !errors.Is(err, errVersionDowngrade) || setting.CommentOnDowngrade {
+				addComment(client, setting, push.GetRepo().GetOwner().GetName(), push.GetRepo().GetName(), commit.GetID(),
+					fmt.Sprintf("tag wasn't created: %v", err))
+			}
+			continue
+		}
+		if !bump && directive == DirectiveTag {
+			log.Infof("commit %s of %q has %s, tagging unchanged version %q", commit.GetID(), fullname, DirectiveTag, version)
+			bump = true
+		}
+		if bump {
+			log.Debugf("There is a new version for %q at %s! Old version: %q, new version: %q", fullname, commit.GetID(), prevVersion, version)
+			tagVersion(ctx, clientProvider, client, token, push, setting, cp, prevVersion, version, commit.GetID(), tagger, commitComment)
+		}
+		prevVersion = version
+	}
+}
+
+// tagVersion tags sha with the rendered version and reports the result in a
+// commit comment. cp reads the repository at sha, oldVersion is the version
+// before it, token is the installation token for the git pushes to the wiki.
+func tagVersion(ctx context.Context, clientProvider provider.ClientProvider, client *github.Client, token string, push *github.WebHookPayload, setting *settings.AtcSettings,
+	cp provider.ContentProvider, oldVersion, version, sha string, tagger *github.CommitAuthor, commitComment string) {
+	log := logger.FromContext(ctx)
+	owner := push.GetRepo().GetOwner().GetName()
+	repo := push.GetRepo().GetName()
+	fullname := push.GetRepo().GetFullName()
+
+	if err := checkVersion(setting, version); err != nil {
+		log.Warnf("check version error for %q: %v", fullname, err)
+		addComment(client, setting, owner, repo, sha, fmt.Sprintf("tag wasn't created: %v", err))
+		return
+	}
+	tagContent := TagContent{
+		Version:    version,
+		PreRelease: setting.PreRelease,
+		SHA:        sha,
+		Branch:     strings.TrimPrefix(push.GetRef(), "refs/heads/"),
+	}
+	tagContent.VersionName, tagContent.VersionCode = androidVersions(cp, setting)
+	caption, err := renderTemplate(setting.Template, tagContent)
+	if err != nil {
+		log.Errorf("error in go templates: %v", err)
+		return
+	}
+	tagContent.Tag = caption
+	tagContent.Repository = fullname
+	tagContent.Actor = push.GetPusher().GetName()
+	if setting.Changelog {
+		prev := TagContent{
+			Version:    oldVersion,
+			PreRelease: setting.PreRelease,
+			Branch:     tagContent.Branch,
+			Repository: fullname,
+		}
+		prev.VersionName, prev.VersionCode = androidVersions(&provider.GhContentProvider{
+			Owner:    owner,
+			Repo:     repo,
+			Ref:      push.GetBefore(),
+			Ctx:      ctx,
+			GhClient: client,
+		}, setting)
+		if tagContent.Changelog, err = changelog(ctx, client, owner, repo, setting, prev, sha); err != nil {
+			log.Warnf("changelog error for %q: %v", fullname, err)
+		}
+	}
+	event := notification.Event{Notification: notification.Notification{
+		Repo:    fullname,
+		Tag:     caption,
+		SHA:     sha,
+		Version: version,
+		Pusher:  push.GetPusher().GetName(),
+	}}
+	if setting.DryRun {
+		log.Infof("Dry run: tag %q would be added to %s of %q", caption, sha, fullname)
+		addInfoComment(client, setting, owner, repo, sha, fmt.Sprintf("Dry run: tag %q would be created", caption))
+		return
+	}
+	if tagSeen(ctx, fullname, caption, sha) {
+		log.Infof("Tag %q was already added to %s of %q, skipped", caption, sha, fullname)
+		return
+	}
+
+	if setting.WaitForStatus != "" {
+		if err := waitForStatus(client, owner, repo, sha, setting.WaitForStatus, setting.WaitForStatusTimeout); err != nil {
+			log.Errorf("wait for status error for %q: %v", fullname, err)
+			forgetTag(ctx, fullname, caption, sha)
+			addComment(client, setting, owner, repo, sha, fmt.Sprintf("tag %q wasn't created: %v", caption, err))
+			notify(ctx, setting, event, err)
 			return
 		}
-		sha := *getShaByBehavior(push, setting.Behavior)
-		objType := "commit"
-		timestamp := time.Now()
-
-		tag := &github.Tag{
-			Tag:     &caption,
-			Message: &caption,
-			Tagger: &github.CommitAuthor{
-				Date:  &timestamp,
-				Name:  push.GetPusher().Name,
-				Email: push.GetPusher().Email,
-				Login: push.GetPusher().Login,
-			},
-			Object: &github.GitObject{
-				Type: &objType,
-				SHA:  &sha,
-			},
-		}
-
-		if err := gitutil.AddTagToCommit(client, owner, repo, tag); err != nil {
-			log.Printf("addTagToCommit Error for %q: %v", fullname, err)
-			gitutil.AddComment(client, owner, repo, sha, fmt.Sprintf("can't add tag to commit, error : %v", err))
+	}
+
+	if setting.TagProtectionBypass {
+		if err := checkTagProtectionBypass(client, owner, repo); err != nil {
+			log.Errorf("tag protection check error for %q: %v", fullname, err)
+			forgetTag(ctx, fullname, caption, sha)
+			addComment(client, setting, owner, repo, sha, fmt.Sprintf("tag %q wasn't created: %v", caption, err))
+			notify(ctx, setting, event, err)
 			return
 		}
+	}
+
+	signer, err := tagSigner(envvars.TagSigningKey, envvars.TagSigningKeyPassphrase)
+	if err != nil {
+		log.Errorf("tag signing key error for %q: %v", fullname, err)
+		forgetTag(ctx, fullname, caption, sha)
+		addComment(client, setting, owner, repo, sha, fmt.Sprintf("tag %q wasn't created: signing key: %v", caption, err))
+		notify(ctx, setting, event, err)
+		return
+	}
+	versionTagger := NewTagger(client, owner, repo, nil, cp)
+	versionTagger.Settings = setting
+	versionTagger.Signer = signer
+	tagRefFormat := versionTagger.refFormat()
+	successComment := commitComment + renderComment(setting.SuccessCommentTemplate,
+		fmt.Sprintf("Added a new version for %q: %q", fullname, caption), tagContent)
+	unsignedTag, err := versionTagger.createTag(tagContent, sha, tagger)
+	if errors.Is(err, ErrTagOnCommit) {
+		log.Infof("Tag %q already points at %s in %q, skipped", caption, sha, fullname)
+		if setting.CommitStatusContextSkip != "" {
+			if err := gitutil.AddCommitStatus(client, owner, repo, sha, "success", setting.CommitStatusContextSkip,
+				"Tag already exists: "+caption); err != nil {
+				log.Warnf("add commit status error for %q: %v", fullname, err)
+			}
+		}
+		return
+	}
+	if errors.Is(err, ErrTagExists) {
+		log.Infof("Tag %q already exists in %q, skipped", caption, fullname)
+		switch setting.OnExistingTag {
+		case settings.OnExistingTagComment:
+			addInfoComment(client, setting, owner, repo, sha, fmt.Sprintf("tag %q already exists, skipped", caption))
+		case settings.OnExistingTagError:
+			tagContent.Error = fmt.Sprintf("tag %q already exists", caption)
+			addComment(client, setting, owner, repo, sha, renderComment(setting.ErrorCommentTemplate,
+				fmt.Sprintf("can't add tag to commit, error : %s", tagContent.Error), tagContent))
+		}
+		return
+	}
+	if err != nil {
+		log.Errorf("addTagToCommit Error for %q: %v", fullname, err)
+		if queueTag(ctx, err, &tagJob{
+			InstallationID: push.GetInstallation().GetID(),
+			Owner:          owner,
+			Repo:           repo,
+			SHA:            sha,
+			Tag:            unsignedTag,
+			TagType:        setting.TagType,
+			RefFormat:      tagRefFormat,
+			Comments:       setting.Comments,
+			SuccessComment: successComment,
+		}) {
+			log.Infof("Tag %q of %q is queued for a retry", caption, fullname)
+			return
+		}
+		forgetTag(ctx, fullname, caption, sha)
+		tagContent.Error = err.Error()
+		addComment(client, setting, owner, repo, sha, renderComment(setting.ErrorCommentTemplate,
+			fmt.Sprintf("can't add tag to commit, error : %v", err), tagContent))
+		notify(ctx, setting, event, err)
+		return
+	}
+
+	if setting.CreateRelease {
+		if err := createRelease(client, owner, repo, cp, setting, tagContent, sha); err != nil {
+			log.Errorf("createRelease Error for %q: %v", fullname, err)
+			addComment(client, setting, owner, repo, sha, fmt.Sprintf("can't create release %q, error : %v", caption, err))
+		}
+	}
+
+	if setting.WikiReleasePage {
+		if err := updateWikiPage(owner, repo, token, setting, tagContent); err != nil {
+			log.Errorf("wiki page error for %q: %v", fullname, err)
+			addComment(client, setting, owner, repo, sha, fmt.Sprintf("can't update wiki page of %q, error : %v", caption, err))
+		}
+	}
+
+	if setting.JIRAConfig != nil {
+		releaseJiraVersion(setting.JIRAConfig, caption)
+	}
+
+	if setting.NotifyURL != "" {
+		if err := notification.Post(setting.NotifyURL, setting.NotifyHeaders, event.Notification); err != nil {
+			log.Warnf("notification about %q for %q failed: %v", caption, fullname, err)
+		}
+	}
+	notify(ctx, setting, event, nil)
+
+	addInfoComment(client, setting, owner, repo, sha, successComment)
+
+	propagateToEnvironments(client, push.GetInstallation().GetID(), owner, repo, setting, tagContent, sha, tagger, tagRefFormat)
+	tagCrossRepoTargets(ctx, clientProvider, setting, tagContent, tagger, signer)
+}
+
+// notify sends event to the notification targets of setting, as created
+// when tagErr is nil and as failed otherwise. Failures are only logged.
+func notify(ctx context.Context, setting *settings.AtcSettings, event notification.Event, tagErr error) {
+	event.Event = notification.EventCreated
+	if tagErr != nil {
+		event.Event = notification.EventFailed
+		event.Error = tagErr.Error()
+	}
+	for _, target := range setting.Notifications {
+		var err error
+		switch target.Type {
+		case settings.NotificationTypeSlack:
+			err = notification.Slack(target.URL, event)
+		case settings.NotificationTypeDiscord:
+			err = notification.Discord(target.URL, event)
+		default:
+			err = notification.Webhook(target.URL, event)
+		}
+		if err != nil {
+			logger.FromContext(ctx).Warnf("%s notification about %q for %q failed: %v", target.Type, event.Tag, event.Repo, err)
+		}
+	}
+}
+
+// createRelease publishes a release for tagContent.Tag with the body from
+// TagBodyFile or the rendered TagBody.
+func createRelease(client *github.Client, owner, repo string, cp provider.ContentProvider,
+	setting *settings.AtcSettings, tagContent TagContent, sha string) error {
+	var body string
+	var err error
+	switch {
+	case setting.TagBodyFile != "":
+		body, err = cp.GetContents(setting.TagBodyFile)
+	case setting.TagBody == "" && tagContent.Changelog != "":
+		body = tagContent.Changelog
+	default:
+		body, err = renderTemplate(setting.TagBody, tagContent)
+	}
+	if err != nil {
+		return err
+	}
+	return gitutil.CreateRelease(client, owner, repo, &github.RepositoryRelease{
+		TagName:         &tagContent.Tag,
+		TargetCommitish: &sha,
+		Name:            &tagContent.Tag,
+		Body:            &body,
+		Prerelease:      &tagContent.PreRelease,
+	})
+}
+
+// maxTagMessageLen is how much of an annotation is kept. GitHub doesn't
+// document a limit, but longer tag messages get rejected.
+const maxTagMessageLen = 10 * 1024
+
+// tagAnnotation renders the tag message, falling back to the tag name when
+// the template is broken and truncating messages that are too long.
+func tagAnnotation(templateString string, tagContent TagContent) string {
+	message := renderComment(templateString, tagContent.Tag, tagContent)
+	if len(message) <= maxTagMessageLen {
+		return message
+	}
+	logger.Warnf("annotation of tag %q is %d bytes, truncated to %d", tagContent.Tag, len(message), maxTagMessageLen)
+	cut := maxTagMessageLen
+	for cut > 0 && !utf8.RuneStart(message[cut]) { // don't split a multi-byte rune
+		cut--
+	}
+	return message[:cut]
+}
+
+// addTag creates tag at sha as a tag object, signed if signer isn't nil, or,
+// for a lightweight tagType, only as the ref.
+func addTag(client *github.Client, owner, repo, tagType string, signer tagsign.Signer, tag *github.Tag, sha, refFormat string) error {
+	if tagType == settings.TagTypeLightweight {
+		return gitutil.AddLightweightTagRef(client, owner, repo, tag.GetTag(), sha, refFormat)
+	}
+	if signer != nil {
+		if err := tagsign.Sign(signer, tag); err != nil {
+			return fmt.Errorf("signing error: %v", err)
+		}
+	}
+	return gitutil.AddTagToCommitRef(client, owner, repo, tag, refFormat)
+}
+
+// tagSigner returns the signer of the key in the keyVar environment
+// variable, or nil if it's empty.
+func tagSigner(keyVar, passphraseVar string) (tagsign.Signer, error) {
+	key := os.Getenv(keyVar)
+	if key == "" {
+		return nil, nil
+	}
+	return tagsign.NewSigner(key, os.Getenv(passphraseVar))
+}
 
-		commitComment += fmt.Sprintf("Added a new version for %q: %q", fullname, caption)
-		gitutil.AddComment(client, owner, repo, sha, commitComment)
+// newTag returns an annotated tag object for the commit sha.
+func newTag(caption, sha string, tagger *github.CommitAuthor) *github.Tag {
+	objType := "commit"
+	timestamp := time.Now()
+	return &github.Tag{
+		Tag:     &caption,
+		Message: &caption,
+		Tagger: &github.CommitAuthor{
+			Date:  &timestamp,
+			Name:  tagger.Name,
+			Email: tagger.Email,
+			Login: tagger.Login,
+		},
+		Object: &github.GitObject{
+			Type: &objType,
+			SHA:  &sha,
+		},
 	}
 }
 
 func CIActionPush() error {
-	githubToken := os.Getenv("GITHUB_TOKEN")
-	fullname := os.Getenv("GITHUB_REPOSITORY")
-	commitSHA := os.Getenv("COMMIT_SHA")
+	ciProvider := DetectCIProvider()
+	env, err := getCIEnvironment(ciProvider)
+	if err != nil {
+		return err
+	}
+	atcs := getCISettings()
+	if atcs.SyncNPMPackage && atcs.NPMScope == "" {
+		return errors.New("sync_npm_package needs npm_scope")
+	}
 
-	atcs := &settings.AtcSettings{
-		Path:     os.Getenv("FILE_TYPE"),
-		Behavior: os.Getenv("BEHAVIOR"),
-		Template: os.Getenv("TEMPLATE"),
-		RegexStr: os.Getenv("REGEX"),
+	if ciProvider == CIProviderGitlab {
+		return ciActionPushGitlab(env, atcs)
 	}
 
+	githubToken := env.Token
+	fullname := env.Repository
+	commitSHA := env.CommitSHA
+
 	ctx := context.Background()
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: githubToken},
-	)
-	tc := oauth2.NewClient(ctx, ts)
-	client := github.NewClient(tc)
+	client := (&provider.GithubClientProvider{}).Get(githubToken, ctx)
 
 	s := strings.Split(fullname, "/")
 	owner := s[0]
@@ -257,101 +1044,150 @@ func CIActionPush() error {
 
 	parents := commit.Parents
 	if len(parents) == 0 {
-		log.Printf("this branch has no older commits")
+		logger.Debugf("this branch has no older commits")
 		return nil
 	}
 
-	ghOldContentProviderPtr := &provider.GhContentProvider{
+	tagger := NewTagger(client, owner, repo, &provider.GhContentProvider{
 		Owner:    owner,
 		Repo:     repo,
 		Ref:      parents[0].GetSHA(),
 		Ctx:      ctx,
 		GhClient: client,
-	}
-	ghNewContentProviderPtr := &provider.GhContentProvider{
+	}, &provider.GhContentProvider{
 		Owner:    owner,
 		Repo:     repo,
 		Ref:      commitSHA,
 		Ctx:      ctx,
 		GhClient: client,
-	}
-
-	var sha string
+	})
+	tagger.Settings = atcs
 
-	caption, err := fetch(atcs, ghOldContentProviderPtr, ghNewContentProviderPtr, fullname)
+	change, err := tagger.DetectVersionChange()
 	if err != nil {
 		return fmt.Errorf("fetch version error: %v", err)
 	}
-	if caption == "" {
-		log.Printf("Old and new versions are equal")
+	if change == nil {
+		logger.Debugf("Old and new versions are equal")
 		return nil
 	}
+	caption, err := tagger.RenderTag(change.NewVersion)
+	if err != nil {
+		return fmt.Errorf("fetch version error: %v", err)
+	}
 
+	sha := parents[0].GetSHA()
 	if atcs.Behavior == settings.BehaviorAfter {
 		sha = commit.GetSHA()
-	} else {
-		sha = parents[0].GetSHA()
 	}
-
-	objType := "commit"
-	timestamp := time.Now()
-
-	tag := &github.Tag{
-		Tag:     &caption,
-		Message: &caption,
-		Tagger: &github.CommitAuthor{
-			Date:  &timestamp,
-			Name:  commit.Commit.Author.Name,
-			Email: commit.Commit.Author.Email,
-			Login: commit.Commit.Author.Login,
-		},
-		Object: &github.GitObject{
-			Type: &objType,
-			SHA:  &sha,
-		},
+	if atcs.DryRun {
+		logger.Infof("Dry run: tag %q would be added to %s of %q", caption, sha, fullname)
+		return nil
 	}
 
-	if err = gitutil.AddTagToCommit(client, owner, repo, tag); err != nil {
+	if tagger.Signer, err = tagSigner("TAG_SIGNING_KEY", "TAG_SIGNING_KEY_PASSPHRASE"); err != nil {
+		return fmt.Errorf("tag signing key error: %v", err)
+	}
+	err = tagger.CreateTag(TagContent{Version: change.NewVersion, PreRelease: atcs.PreRelease, Tag: caption}, sha, commit.Commit.Author)
+	if errors.Is(err, ErrTagExists) && atcs.OnExistingTag != settings.OnExistingTagError {
+		logger.Infof("Tag %q already exists in %q, skipped", caption, fullname)
+		return nil
+	}
+	if err != nil {
 		return fmt.Errorf("error when adding tag to commit %q: %v", fullname, err)
 	}
 
-	log.Printf("Added a new version for %q: %q", fullname, caption)
+	logger.Infof("Added a new version for %q: %q", fullname, caption)
+
+	if atcs.SyncNPMPackage {
+		if err := publishNPMPackage(atcs, githubToken); err != nil {
+			return fmt.Errorf("error publishing npm package of %q: %v", fullname, err)
+		}
+		logger.Infof("Published npm package of %q for %q", fullname, caption)
+	}
 	return nil
 }
 
+func isZeroSHA(sha string) bool {
+	return strings.Trim(sha, "0") == ""
+}
+
+// resolveFetcher returns the fetcher for settings.Path, detecting the file type
+// from its content or falling back to the custom regex fetcher.
+func resolveFetcher(settings *settings.AtcSettings, cp provider.ContentProvider) (fetcher.VersionFetcher, error) {
+	fetchType := settingsFetchType(settings)
+	af := configuredFetcher(settings)
+	if af == nil && settings.RegexStr == "" {
+		af = sniffFetcher(cp, settings.Path)
+	}
+	if af == nil {
+		logger.Debugf("using custom fetcher")
+		if settings.RegexStr == "" {
+			return nil, fmt.Errorf("don't have regexstr for not default package manager file %s", fetchType)
+		}
+		af = &customregex.Fetcher{}
+	}
+	return af, nil
+}
+
+// fetch returns the rendered tag name when the version changed between the
+// providers, or "" otherwise. A nil ghOldContentProviderPtr means there is no
+// previous commit, so any version found is new.
 func fetch(settings *settings.AtcSettings, ghOldContentProviderPtr,
 	ghNewContentProviderPtr provider.ContentProvider, fullname string) (string, error) {
-	fetchType := detectFetchType(settings.Path)
+	change, err := detectVersionChange(settings, ghOldContentProviderPtr, ghNewContentProviderPtr, fullname)
+	if err != nil || change == nil {
+		return "", err
+	}
+	caption, err := renderTagNameTemplate(settings.Template, change.NewVersion)
+	if err != nil {
+		return "", fmt.Errorf("error in go templates: %v", err)
+	}
+	return caption, nil
+}
+
+// detectVersionChange returns the versions when the version changed between
+// the providers and is tagged with settings, or nil otherwise.
+func detectVersionChange(settings *settings.AtcSettings, ghOldContentProviderPtr,
+	ghNewContentProviderPtr provider.ContentProvider, fullname string) (*VersionChange, error) {
+	fetchType := settingsFetchType(settings)
 	var newVersion string
 	var oldVersion string
 	if fetchType != "" {
-		var err error
-		af := autoFetchers[fetchType]
-		if af == nil {
-			log.Printf("using custom fetcher")
-			if settings.RegexStr == "" {
-				return "", fmt.Errorf("don't have regexstr for not default package manager file %s", fetchType)
-			}
-			af = &customregex.Fetcher{}
+		af, err := resolveFetcher(settings, ghNewContentProviderPtr)
+		if err != nil {
+			return nil, err
 		}
+		af = instrumented(af)
 
-		oldVersion, err = af.GetVersion(ghOldContentProviderPtr, *settings)
+		if ghOldContentProviderPtr != nil {
+			oldVersion, err = af.GetVersion(ghOldContentProviderPtr, *settings)
+		}
+		if errors.Is(err, fetcher.ErrVersionUnchanged) {
+			return nil, nil
+		}
 		if err != nil && !errors.Is(err, provider.ErrHttpStatusCode) { //ignore http api error
-			return "", fmt.Errorf("get prev version error for %q: %w", fullname, err)
+			return nil, fmt.Errorf("get prev version error for %q: %w", fullname, err)
 		}
 
-		log.Printf("old version %s", oldVersion)
+		logger.Debugf("old version %s", oldVersion)
 		newVersion, err = af.GetVersion(ghNewContentProviderPtr, *settings)
+		if errors.Is(err, fetcher.ErrVersionUnchanged) {
+			return nil, nil
+		}
 		if err != nil {
-			return "", fmt.Errorf("get new version error for %q: %w", fullname, err)
+			return nil, fmt.Errorf("get new version error for %q: %w", fullname, err)
 		}
 	} else {
 		fetched := false
-		for defaultPath, af := range autoFetchers {
+		for _, defaultPath := range autoFetcherOrder {
+			af := autoFetchers[defaultPath]
 			var err error
-			oldVersion, err = af.GetVersionUsingDefaultPath(ghOldContentProviderPtr)
+			if ghOldContentProviderPtr != nil {
+				oldVersion, err = af.GetVersionUsingDefaultPath(ghOldContentProviderPtr)
+			}
 			if err != nil && !errors.Is(err, provider.ErrHttpStatusCode) { //ignore http api error
-				log.Printf("get prev version error for %q, default path: %s, err: %v", fullname, defaultPath, err)
+				logger.Debugf("get prev version error for %q, default path: %s, err: %v", fullname, defaultPath, err)
 				continue
 			}
 
@@ -360,23 +1196,28 @@ func fetch(settings *settings.AtcSettings, ghOldContentProviderPtr,
 				fetched = true
 				break
 			} else {
-				return "", fmt.Errorf("autofetcher error for %q: %w", defaultPath, err)
+				return nil, fmt.Errorf("autofetcher error for %q: %w", defaultPath, err)
 			}
 		}
 
 		if !fetched {
-			return "", fmt.Errorf("unable to fetch version using known methods")
+			return nil, fmt.Errorf("unable to fetch version using known methods")
 		}
 	}
 
-	if newVersion != oldVersion {
-		log.Printf("There is a new version for %q! Old version: %q, new version: %q", fullname, oldVersion, newVersion)
-		caption, err := renderTagNameTemplate(settings.Template, newVersion)
-		if err != nil {
-			return "", fmt.Errorf("error in go templates: %v", err)
-		}
-		return caption, nil
+	if settings.NormalizeVersion {
+		oldVersion, newVersion = semver.Normalize(oldVersion), semver.Normalize(newVersion)
 	}
-
-	return "", nil
+	bump, err := isVersionBump(settings, oldVersion, newVersion)
+	if err != nil {
+		return nil, err
+	}
+	if !bump {
+		return nil, nil
+	}
+	logger.Debugf("There is a new version for %q! Old version: %q, new version: %q", fullname, oldVersion, newVersion)
+	if err := checkVersion(settings, newVersion); err != nil {
+		return nil, err
+	}
+	return &VersionChange{OldVersion: oldVersion, NewVersion: newVersion}, nil
 }
@@ -1,21 +1,36 @@
 package push
 
 import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"log"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"regexp"
 	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
 
 	"github.com/smartforce-io/atc/envvars"
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/fetcher/cabal"
+	"github.com/smartforce-io/atc/githubservice/fetcher/packervars"
+	"github.com/smartforce-io/atc/metrics"
 
 	"github.com/google/go-github/v39/github"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"golang.org/x/crypto/ssh"
 )
 
 var (
@@ -804,6 +819,40 @@ func TestMadeСaptionToTemplate(t *testing.T) {
 	}
 }
 
+func TestTemplateFuncs(t *testing.T) {
+	content := TagContent{
+		Version: "2.7.1-rc.1",
+		SHA:     "6113728f27ae82c7b1a177c8d03f9e96e0adf246",
+		Branch:  "release/2.7",
+	}
+	var tests = []struct {
+		template string
+		result   string
+	}{
+		{`{{.Major}}.{{.Minor}}-{{date "2006"}}`, "2.7-" + time.Now().UTC().Format("2006")},
+		{`{{Time.Format "2006"}}`, time.Now().Format("2006")},
+		{`v{{.Version | major}}.{{.Version | minor}}.{{.Version | patch}}`, "v2.7.1"},
+		{`{{.Version}}+{{.ShortSHA}}`, "2.7.1-rc.1+6113728"},
+		{`{{shortSHA .SHA}}`, "6113728"},
+		{`{{.Branch | replace "/" "-" | upper}}-{{.Version | trimSuffix "-rc.1"}}`, "RELEASE-2.7-2.7.1"},
+		{`{{.Branch | trimPrefix "release/" | lower}}`, "2.7"},
+	}
+	for _, test := range tests {
+		result, err := renderTemplate(test.template, content)
+		if err != nil || result != test.result {
+			t.Errorf("template: %q\nwant: %q, got: %q, %v", test.template, test.result, result, err)
+		}
+	}
+
+	// the numbers of versions that aren't semver
+	if result, err := renderTagNameTemplate("{{.Major}}-{{.Version}}", "build-42"); err != nil || result != "0-build-42" {
+		t.Errorf("want: %q, got: %q, %v", "0-build-42", result, err)
+	}
+	if _, err := renderTagNameTemplate("{{major .Version}}", "build-42"); err == nil {
+		t.Errorf("expected an error of major for a version that isn't semver")
+	}
+}
+
 func TestMadeСaptionToTemplateError(t *testing.T) {
 	var tests = []struct {
 		template  string
@@ -819,3 +868,1559 @@ func TestMadeСaptionToTemplateError(t *testing.T) {
 		}
 	}
 }
+
+func TestDetectCIProvider(t *testing.T) {
+	var tests = []struct {
+		ciProvider string
+		gitlabCI   string
+		expected   CIProvider
+	}{
+		{"", "", CIProviderGithub},
+		{"", "true", CIProviderGitlab},
+		{"github", "true", CIProviderGithub},
+		{"bitbucket", "", CIProvider("bitbucket")},
+	}
+	t.Setenv("CIRCLECI", "")
+	for _, test := range tests {
+		t.Setenv("CI_PROVIDER", test.ciProvider)
+		t.Setenv("GITLAB_CI", test.gitlabCI)
+		if got := DetectCIProvider(); got != test.expected {
+			t.Errorf("CI_PROVIDER=%q GITLAB_CI=%q: expected %q, got %q", test.ciProvider, test.gitlabCI, test.expected, got)
+		}
+	}
+}
+
+func TestGetCIEnvironmentGitlab(t *testing.T) {
+	t.Setenv("GITLAB_TOKEN", "")
+	t.Setenv("CI_JOB_TOKEN", "job")
+	t.Setenv("CI_PROJECT_PATH", "group/atc")
+	t.Setenv("CI_COMMIT_SHA", "abc")
+
+	env, err := getCIEnvironment(CIProviderGitlab)
+	if err != nil {
+		t.Errorf("Unexpected error %v", err)
+		return
+	}
+	if env.Token != "job" || !env.JobToken || env.Repository != "group/atc" || env.CommitSHA != "abc" {
+		t.Errorf("wrong gitlab environment: %+v", env)
+	}
+
+	if _, err := getCIEnvironment(CIProvider("azure")); fmt.Sprint(err) != `CI provider "azure" is not supported` {
+		t.Errorf("wrong error for azure: %v", err)
+	}
+}
+
+func TestGetCIEnvironmentCircleCI(t *testing.T) {
+	t.Setenv("CI_PROVIDER", "")
+	t.Setenv("GITLAB_CI", "")
+	t.Setenv("CIRCLECI", "true")
+	if got := DetectCIProvider(); got != CIProviderCircleCI {
+		t.Errorf("expected %q, got %q", CIProviderCircleCI, got)
+	}
+
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("CIRCLE_TOKEN", "circle")
+	t.Setenv("CIRCLE_PROJECT_USERNAME", "Codertocat")
+	t.Setenv("CIRCLE_PROJECT_REPONAME", "Hello-World")
+	t.Setenv("CIRCLE_SHA1", "abc")
+	// CIRCLE_TOKEN isn't a GitHub token
+	if _, err := getCIEnvironment(CIProviderCircleCI); fmt.Sprint(err) != "GITHUB_TOKEN is required on CircleCI" {
+		t.Errorf("expected a GITHUB_TOKEN error, got %v", err)
+	}
+
+	t.Setenv("GITHUB_TOKEN", "github")
+	env, err := getCIEnvironment(CIProviderCircleCI)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if env.Token != "github" || env.Repository != "Codertocat/Hello-World" || env.CommitSHA != "abc" {
+		t.Errorf("wrong circleci environment: %+v", env)
+	}
+}
+
+func TestConfiguredChannels(t *testing.T) {
+	var tests = []struct {
+		confString  string
+		expectedTag string
+	}{
+		{`
+channels:
+  beta:
+    branch_pattern: "ma*"
+    template: "v{{.Version}}{{if .PreRelease}}-beta{{end}}"
+    prerelease: true`, `v5-beta`},
+		{`
+branch: main
+channels:
+  beta:
+    branch_pattern: "release/*"
+    template: "beta-{{.Version}}"`, `v5`},
+		{`
+branch: release
+channels:
+  beta:
+    branch_pattern: "release/*"
+    template: "beta-{{.Version}}"`, ``},
+	}
+
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+
+	var config string
+	var tag string
+
+	mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		return provider.NewTestResponse(200, provider.MockContentResponse(config))
+	})
+	mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		j := provider.GetBodyJson(req)
+		tag = fmt.Sprintf("%v", j["tag"])
+		return defaultFn(req)
+	})
+
+	for _, test := range tests {
+		config = "path: pom.xml" + test.confString
+		tag = ""
+
+		ActionPush(&p, mockClientProviderPtr)
+
+		if tag != test.expectedTag {
+			t.Errorf("Wrong tag! config: %s\nexpected: %s, got: %s", config, test.expectedTag, tag)
+		}
+	}
+}
+
+func TestBranchSettings(t *testing.T) {
+	var tests = []struct {
+		confString  string
+		expectedTag string
+	}{
+		{`
+branch: release
+branches:
+  "ma*":
+    template: "main-{{.Version}}"`, `main-5`},
+		{`
+branches:
+  "release/*":
+    template: "rc-{{.Version}}"`, `v5`},
+		{`
+branch: release
+branches:
+  "release/*":
+    template: "rc-{{.Version}}"`, ``},
+		{`
+branches:
+  "*":
+    template: "any-{{.Version}}"
+  "main":
+    template: "main-{{.Version}}"`, `main-5`},
+	}
+
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+	var config string
+	var tag string
+	mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		return provider.NewTestResponse(200, provider.MockContentResponse(config))
+	})
+	mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		tag = fmt.Sprint(provider.GetBodyJson(req)["tag"])
+		return defaultFn(req)
+	})
+
+	for _, test := range tests {
+		config = "path: pom.xml" + test.confString
+		tag = ""
+
+		ActionPush(&p, mockClientProviderPtr)
+
+		if tag != test.expectedTag {
+			t.Errorf("Wrong tag! config: %s\nexpected: %s, got: %s", config, test.expectedTag, tag)
+		}
+	}
+}
+
+func TestPropagateToEnvironments(t *testing.T) {
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+
+	var mu sync.Mutex
+	tags := map[string]bool{}
+	refs := map[string]bool{}
+
+	mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		return provider.NewTestResponse(200, provider.MockContentResponse(`
+path: pom.xml
+propagate_to_environments:
+  - branch: env/staging
+    template: "staging-v{{.Version}}"
+  - template: "production-v{{.Version}}"
+    delay: 10ms`))
+	})
+	mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		j := provider.GetBodyJson(req)
+		mu.Lock()
+		tags[fmt.Sprintf("%v", j["tag"])] = true
+		mu.Unlock()
+		return defaultFn(req)
+	})
+	mockClientProviderPtr.OverrideResponseFn("ADD_REF", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		j := provider.GetBodyJson(req)
+		mu.Lock()
+		refs[fmt.Sprintf("%v", j["ref"])] = true
+		mu.Unlock()
+		return defaultFn(req)
+	})
+
+	queue, err := NewTagRetryQueue("", mockClientProviderPtr)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	TagRetryQueue = queue
+	defer func() { TagRetryQueue = nil }()
+
+	ActionPush(&p, mockClientProviderPtr)
+
+	for _, expected := range []string{"v5", "staging-v5"} {
+		if !tags[expected] {
+			t.Errorf("tag %q wasn't created, got: %v", expected, tags)
+		}
+	}
+	if !refs["refs/heads/env/staging"] {
+		t.Errorf("branch env/staging wasn't created, got: %v", refs)
+	}
+	if tags["production-v5"] || queue.Len() != 1 {
+		t.Fatalf("expected production-v5 queued, got tags %v, %d queued", tags, queue.Len())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go queue.Run(ctx)
+	created := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return tags["production-v5"]
+	}
+	for !created() && ctx.Err() == nil {
+		time.Sleep(time.Millisecond)
+	}
+	if !created() {
+		t.Errorf("tag production-v5 wasn't created, got: %v", tags)
+	}
+}
+
+func TestIsZeroSHA(t *testing.T) {
+	var tests = []struct {
+		sha      string
+		expected bool
+	}{
+		{"0000000000000000000000000000000000000000", true},
+		{"6113728f27ae82c7b1a177c8d03f9e96e0adf246", false},
+		{"", true},
+	}
+	for _, test := range tests {
+		if got := isZeroSHA(test.sha); got != test.expected {
+			t.Errorf("isZeroSHA(%q) = %v, expected %v", test.sha, got, test.expected)
+		}
+	}
+}
+
+func TestFirstPushToBranch(t *testing.T) {
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	zeroSHA := "0000000000000000000000000000000000000000"
+	p.Before = &zeroSHA
+
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+
+	oldVersionRequested := false
+	var tag string
+
+	mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		return provider.NewTestResponse(200, provider.MockContentResponse(`path: pom.xml`))
+	})
+	mockClientProviderPtr.OverrideResponseFn("GET_OLD_VERSION_MAVEN", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		oldVersionRequested = true
+		return defaultFn(req)
+	})
+	mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		j := provider.GetBodyJson(req)
+		tag = fmt.Sprintf("%v", j["tag"])
+		return defaultFn(req)
+	})
+
+	ActionPush(&p, mockClientProviderPtr)
+
+	if oldVersionRequested {
+		t.Errorf("old version shouldn't be requested for the zero sha")
+	}
+	if tag != "v5" {
+		t.Errorf("Wrong tag! expected: %s, got: %s", "v5", tag)
+	}
+}
+
+func TestOnlyIfNoExistingTag(t *testing.T) {
+	var tests = []struct {
+		config    string
+		refs      string
+		tagObject string
+		tagged    bool
+	}{
+		{"", `[]`, "", true},
+		{"", `[{"ref": "refs/tags/v5", "object": {"type": "commit", "sha": "0000000000000000000000000000000000000000"}}]`, "", false},
+		{"", `[{"ref": "refs/tags/v5", "object": {"type": "commit", "sha": "1234567890"}}]`, "", true},
+		{"", `[{"ref": "refs/tags/v5.1", "object": {"type": "commit", "sha": "0000000000000000000000000000000000000000"}}]`, "", true},
+		{"", `[{"ref": "refs/tags/v5", "object": {"type": "tag", "sha": "aaaa"}}]`, "0000000000000000000000000000000000000000", false},
+		// the tag is looked up under tag_ref_format
+		{"\ntag_ref_format: refs/environments/prod/%s", `[{"ref": "refs/environments/prod/v5", "object": {"type": "commit", "sha": "0000000000000000000000000000000000000000"}}]`, "", false},
+		{"\ntag_ref_format: refs/environments/prod/%s", `[{"ref": "refs/tags/v5", "object": {"type": "commit", "sha": "0000000000000000000000000000000000000000"}}]`, "", true},
+	}
+
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	for _, test := range tests {
+		mockClientProviderPtr := provider.DefaultMockClientProvider()
+		tagged := false
+		var status map[string]interface{}
+
+		mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			return provider.NewTestResponse(200, provider.MockContentResponse("path: pom.xml\nonly_if_no_existing_tag: true\ncommit_status_context_skip: atc/tag"+test.config))
+		})
+		mockClientProviderPtr.OverrideResponseFn("ADD_STATUS", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			status = provider.GetBodyJson(req)
+			return defaultFn(req)
+		})
+		mockClientProviderPtr.OverrideResponseFn("GET_MATCHING_REFS", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			return provider.NewTestResponse(200, test.refs)
+		})
+		mockClientProviderPtr.OverrideResponseFn("GET_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			return provider.NewTestResponse(200, fmt.Sprintf(`{"tag": "v5", "object": {"type": "commit", "sha": "%s"}}`, test.tagObject))
+		})
+		mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			tagged = true
+			return defaultFn(req)
+		})
+
+		ActionPush(&p, mockClientProviderPtr)
+
+		if tagged != test.tagged {
+			t.Errorf("refs %s: expected tagged %v, got %v", test.refs, test.tagged, tagged)
+		}
+		if test.tagged && status != nil {
+			t.Errorf("refs %s: unexpected status %v", test.refs, status)
+		}
+		if !test.tagged && (status["state"] != "success" || status["context"] != "atc/tag" || status["description"] != "Tag already exists: v5") {
+			t.Errorf("refs %s: wrong status %v", test.refs, status)
+		}
+	}
+}
+
+func TestOnExistingTag(t *testing.T) {
+	var tests = []struct {
+		onExistingTag string
+		refs          string
+		tagged        bool
+		comment       string
+	}{
+		{"skip", `[{"ref": "refs/tags/v5"}]`, false, ""},
+		{"comment", `[{"ref": "refs/tags/v5"}]`, false, `tag "v5" already exists, skipped`},
+		{"error", `[{"ref": "refs/tags/v5"}]`, false, `can't add tag to commit, error : tag "v5" already exists`},
+		{"error", `[{"ref": "refs/tags/v5.1"}]`, true, ""},
+	}
+
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	for _, test := range tests {
+		mockClientProviderPtr := provider.DefaultMockClientProvider()
+		tagged := false
+		comment := ""
+
+		mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			return provider.NewTestResponse(200, provider.MockContentResponse("path: pom.xml\non_existing_tag: "+test.onExistingTag))
+		})
+		mockClientProviderPtr.OverrideResponseFn("GET_MATCHING_REFS", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			return provider.NewTestResponse(200, test.refs)
+		})
+		mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			tagged = true
+			return defaultFn(req)
+		})
+		mockClientProviderPtr.OverrideResponseFn("ADD_COMMENT", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			comment = fmt.Sprintf("%v", provider.GetBodyJson(req)["body"])
+			return defaultFn(req)
+		})
+
+		ActionPush(&p, mockClientProviderPtr)
+
+		if tagged != test.tagged {
+			t.Errorf("%s, refs %s: expected tagged %v, got %v", test.onExistingTag, test.refs, test.tagged, tagged)
+		}
+		if !test.tagged && comment != test.comment {
+			t.Errorf("%s: expected comment %q, got %q", test.onExistingTag, test.comment, comment)
+		}
+	}
+}
+
+func TestTagType(t *testing.T) {
+	var tests = []struct {
+		tagType   string
+		tagObject bool
+		refSHA    string
+	}{
+		{"", true, ""},
+		{"annotated", true, ""},
+		{"lightweight", false, "0000000000000000000000000000000000000000"},
+	}
+
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	for _, test := range tests {
+		mockClientProviderPtr := provider.DefaultMockClientProvider()
+		tagObject := false
+		var ref map[string]interface{}
+
+		mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			return provider.NewTestResponse(200, provider.MockContentResponse("path: pom.xml\ntag_type: "+test.tagType))
+		})
+		mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			tagObject = true
+			return defaultFn(req)
+		})
+		mockClientProviderPtr.OverrideResponseFn("ADD_REF", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			ref = provider.GetBodyJson(req)
+			return defaultFn(req)
+		})
+
+		ActionPush(&p, mockClientProviderPtr)
+
+		if tagObject != test.tagObject {
+			t.Errorf("tag_type %q: expected tag object %v, got %v", test.tagType, test.tagObject, tagObject)
+		}
+		if ref["ref"] != "refs/tags/v5" {
+			t.Errorf("tag_type %q: wrong ref %v", test.tagType, ref)
+		}
+		if test.refSHA != "" && ref["sha"] != test.refSHA {
+			t.Errorf("tag_type %q: expected ref to %s, got %v", test.tagType, test.refSHA, ref)
+		}
+	}
+}
+
+func TestCommentTemplates(t *testing.T) {
+	var tests = []struct {
+		tagFails bool
+		expected string
+	}{
+		{false, ":rocket: v5 of Codertocat/Hello-World by Codertocat"},
+		{true, ":boom: v5 failed"},
+	}
+
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	config := `
+path: pom.xml
+success_comment_template: ":rocket: {{.Tag}} of {{.Repository}} by {{.Actor}}"
+error_comment_template: ":boom: {{.Tag}} failed{{if not .Error}} without error{{end}}"`
+
+	for _, test := range tests {
+		mockClientProviderPtr := provider.DefaultMockClientProvider()
+		var message string
+
+		mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			return provider.NewTestResponse(200, provider.MockContentResponse(config))
+		})
+		mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			if test.tagFails {
+				return provider.NewTestResponse(422, `{"message": "Validation Failed"}`)
+			}
+			return defaultFn(req)
+		})
+		mockClientProviderPtr.OverrideResponseFn("ADD_COMMENT", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			message = fmt.Sprintf("%v", provider.GetBodyJson(req)["body"])
+			return defaultFn(req)
+		})
+
+		ActionPush(&p, mockClientProviderPtr)
+
+		if message != test.expected {
+			t.Errorf("Wrong comment! expected: %q, got: %q", test.expected, message)
+		}
+	}
+}
+
+func TestCommentsSetting(t *testing.T) {
+	var tests = []struct {
+		comments         string
+		tagFails         bool
+		expectedComments int
+	}{
+		{"", false, 1},
+		{"always", false, 1},
+		{"always", true, 1},
+		{"errors_only", false, 0},
+		{"errors_only", true, 1},
+		{"off", false, 0},
+		{"off", true, 0},
+	}
+
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	for _, test := range tests {
+		mockClientProviderPtr := provider.DefaultMockClientProvider()
+		comments := 0
+
+		mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			return provider.NewTestResponse(200, provider.MockContentResponse("path: pom.xml\ncomments: "+test.comments))
+		})
+		mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			if test.tagFails {
+				return provider.NewTestResponse(422, `{"message": "Validation Failed"}`)
+			}
+			return defaultFn(req)
+		})
+		mockClientProviderPtr.OverrideResponseFn("ADD_COMMENT", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			comments++
+			return defaultFn(req)
+		})
+
+		ActionPush(&p, mockClientProviderPtr)
+
+		if comments != test.expectedComments {
+			t.Errorf("comments %q, tag fails %v: expected %d comments, got %d", test.comments, test.tagFails, test.expectedComments, comments)
+		}
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+	tagged := false
+	var message string
+
+	mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		return provider.NewTestResponse(200, provider.MockContentResponse("path: pom.xml\ndry_run: true"))
+	})
+	mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		tagged = true
+		return defaultFn(req)
+	})
+	mockClientProviderPtr.OverrideResponseFn("ADD_COMMENT", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		message = fmt.Sprintf("%v", provider.GetBodyJson(req)["body"])
+		return defaultFn(req)
+	})
+
+	ActionPush(&p, mockClientProviderPtr)
+
+	if tagged {
+		t.Errorf("dry run created a tag")
+	}
+	if expected := `Dry run: tag "v5" would be created`; message != expected {
+		t.Errorf("Wrong comment! expected: %q, got: %q", expected, message)
+	}
+}
+
+func TestRenderCommentFallback(t *testing.T) {
+	content := TagContent{Version: "1.0", Tag: "v1.0"}
+	if comment := renderComment("", "default", content); comment != "default" {
+		t.Errorf("expected default comment, got %q", comment)
+	}
+	if comment := renderComment("{{.Unknown}}", "default", content); comment != "default" {
+		t.Errorf("expected default comment for broken template, got %q", comment)
+	}
+}
+
+func TestLatestReleasePath(t *testing.T) {
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+	var tag string
+
+	mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		return provider.NewTestResponse(200, provider.MockContentResponse("path: \":latest-release\"\ntemplate: \"release-{{.Version}}\""))
+	})
+	mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		tag = fmt.Sprintf("%v", provider.GetBodyJson(req)["tag"])
+		return defaultFn(req)
+	})
+
+	ActionPush(&p, mockClientProviderPtr)
+
+	if tag != "release-v2.0.0" {
+		t.Errorf("Wrong tag! expected: %s, got: %s", "release-v2.0.0", tag)
+	}
+}
+
+func TestCreateReleaseWithTagBody(t *testing.T) {
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+	var release map[string]interface{}
+
+	mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		return provider.NewTestResponse(200, provider.MockContentResponse(`
+path: pom.xml
+create_release: true
+prerelease: true
+tag_body: "Release {{.Tag}} of {{.Repository}}"`))
+	})
+	mockClientProviderPtr.OverrideResponseFn("CREATE_RELEASE", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		release = provider.GetBodyJson(req)
+		return defaultFn(req)
+	})
+
+	ActionPush(&p, mockClientProviderPtr)
+
+	if release == nil {
+		t.Fatalf("release wasn't created")
+	}
+	if release["tag_name"] != "v5" || release["body"] != "Release v5 of Codertocat/Hello-World" || release["prerelease"] != true {
+		t.Errorf("Wrong release: %v", release)
+	}
+}
+
+func TestCreateReleaseWithTagBodyFile(t *testing.T) {
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+	var body interface{}
+	mockClientProviderPtr.OverrideResponseFn("CREATE_RELEASE", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		body = provider.GetBodyJson(req)["body"]
+		return defaultFn(req)
+	})
+	client := mockClientProviderPtr.Get("", context.Background())
+
+	cp := provider.MockContentProvider{Content: "## Changes\n- fixes"}
+	setting := &settings.AtcSettings{TagBodyFile: "RELEASE_NOTES.md"}
+	if err := createRelease(client, "owner", "repo", &cp, setting, TagContent{Version: "1.0", Tag: "v1.0"}, "sha"); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if body != "## Changes\n- fixes" {
+		t.Errorf("Wrong release body: %v", body)
+	}
+
+	cp = provider.MockContentProvider{Err: provider.ErrGeneral}
+	if err := createRelease(client, "owner", "repo", &cp, setting, TagContent{Tag: "v1.0"}, "sha"); err != provider.ErrGeneral {
+		t.Errorf("expected %v, got %v", provider.ErrGeneral, err)
+	}
+}
+
+func TestTagAllCommits(t *testing.T) {
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	versions := map[string]string{"1111": "4", "2222": "5", "3333": "5", "4444": "6"}
+	for _, id := range []string{"1111", "2222", "3333", "4444"} {
+		id := id
+		p.Commits = append(p.Commits, &github.WebHookCommit{ID: &id})
+	}
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+	tags := map[string]string{}
+
+	mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		return provider.NewTestResponse(200, provider.MockContentResponse("path: pom.xml\ntag_all_commits: true"))
+	})
+	mockClientProviderPtr.OverrideResponseFn("GET_OLD_VERSION_MAVEN", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		version, ok := versions[req.URL.Query().Get("ref")]
+		if !ok {
+			return defaultFn(req)
+		}
+		return provider.NewTestResponse(200, provider.MockContentResponse(fmt.Sprintf("<project><version>%s</version></project>", version)))
+	})
+	mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		j := provider.GetBodyJson(req)
+		tags[fmt.Sprintf("%v", j["object"])] = fmt.Sprintf("%v", j["tag"])
+		return defaultFn(req)
+	})
+
+	ActionPush(&p, mockClientProviderPtr)
+
+	expected := map[string]string{"2222": "v5", "4444": "v6"}
+	if fmt.Sprint(tags) != fmt.Sprint(expected) {
+		t.Errorf("Wrong tags! expected: %v, got: %v", expected, tags)
+	}
+}
+
+func TestConfiguredFetcherYAMLPath(t *testing.T) {
+	var tests = []struct {
+		setting  settings.AtcSettings
+		expected string
+	}{
+		{settings.AtcSettings{Path: "charts/atc/Chart.yaml", YAMLPath: "appVersion"}, "*yamlpath.Fetcher"},
+		{settings.AtcSettings{Path: "pubspec.yaml", YAMLPath: "version"}, "*yamlpath.Fetcher"},
+		{settings.AtcSettings{Path: "pubspec.yaml"}, "*pubspecyaml.Fetcher"},
+		{settings.AtcSettings{Path: "charts/atc/Chart.yaml"}, "<nil>"},
+	}
+	for _, test := range tests {
+		if f := fmt.Sprintf("%T", uninstrumented(configuredFetcher(&test.setting))); f != test.expected {
+			t.Errorf("settings %+v: expected %s, got %s", test.setting, test.expected, f)
+		}
+	}
+}
+
+func TestReleaseJiraVersion(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if _, token, _ := r.BasicAuth(); token != "env-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"id": "10001"}`))
+	}))
+	defer server.Close()
+	os.Setenv(envvars.JiraAPIToken, "env-token")
+	defer os.Unsetenv(envvars.JiraAPIToken)
+	t.Setenv(envvars.JiraBaseURLs, "https://jira.example.com, "+server.URL+"/")
+
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+	var message string
+	mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		return provider.NewTestResponse(200, provider.MockContentResponse(fmt.Sprintf(`
+path: pom.xml
+jira:
+  base_url: %s
+  project_key: ATC
+  username: bot@example.com`, server.URL)))
+	})
+	mockClientProviderPtr.OverrideResponseFn("ADD_COMMENT", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		message = fmt.Sprintf("%v", provider.GetBodyJson(req)["body"])
+		return defaultFn(req)
+	})
+
+	ActionPush(&p, mockClientProviderPtr)
+
+	expected := []string{"POST /rest/api/3/version", "PUT /rest/api/3/version/10001"}
+	if fmt.Sprint(requests) != fmt.Sprint(expected) {
+		t.Errorf("Wrong JIRA requests! expected: %v, got: %v", expected, requests)
+	}
+	if message != `Added a new version for "Codertocat/Hello-World": "v5"` {
+		t.Errorf("Wrong comment: %q", message)
+	}
+
+	// JIRA failures don't stop the push action
+	os.Setenv(envvars.JiraAPIToken, "wrong")
+	requests, message = nil, ""
+	ActionPush(&p, mockClientProviderPtr)
+	if len(requests) != 1 || message == "" {
+		t.Errorf("expected one failed JIRA request and a comment, got %v, %q", requests, message)
+	}
+
+	// the server token isn't sent to other base URLs
+	os.Setenv(envvars.JiraAPIToken, "env-token")
+	t.Setenv(envvars.JiraBaseURLs, "https://jira.example.com")
+	requests, message = nil, ""
+	ActionPush(&p, mockClientProviderPtr)
+	if len(requests) != 0 || message == "" {
+		t.Errorf("expected no JIRA request and a comment, got %v, %q", requests, message)
+	}
+}
+
+func TestTagAnnotationTemplate(t *testing.T) {
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+	var message string
+	mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		return provider.NewTestResponse(200, provider.MockContentResponse(`
+path: pom.xml
+tag_annotation_template: |
+  Release {{.Tag}}
+
+  Repository: {{.Repository}}
+  Version: {{.Version}}`))
+	})
+	mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		message = fmt.Sprintf("%v", provider.GetBodyJson(req)["message"])
+		return defaultFn(req)
+	})
+
+	ActionPush(&p, mockClientProviderPtr)
+
+	expected := "Release v5\n\nRepository: Codertocat/Hello-World\nVersion: 5"
+	if message != expected {
+		t.Errorf("Wrong tag message! expected: %q, got: %q", expected, message)
+	}
+}
+
+func TestTagAnnotation(t *testing.T) {
+	content := TagContent{Version: "1.0", Tag: "v1.0"}
+	if message := tagAnnotation("{{.Unknown}}", content); message != "v1.0" {
+		t.Errorf("expected tag name for broken template, got %q", message)
+	}
+	long := strings.Repeat("a", maxTagMessageLen-1) + "é"
+	if message := tagAnnotation(long, content); len(message) != maxTagMessageLen-1 {
+		t.Errorf("expected truncation before the split rune, got %d bytes", len(message))
+	}
+}
+
+func TestWaitForStatusBeforeTagging(t *testing.T) {
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	for _, state := range []string{"success", "failure"} {
+		mockClientProviderPtr := provider.DefaultMockClientProvider()
+		tagged := false
+		mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			return provider.NewTestResponse(200, provider.MockContentResponse("path: pom.xml\nwait_for_status: ci/jenkins/branch"))
+		})
+		mockClientProviderPtr.OverrideResponseFn("GET_COMBINED_STATUS", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			return provider.NewTestResponse(200, statusResponse(state))
+		})
+		mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			tagged = true
+			return defaultFn(req)
+		})
+
+		ActionPush(&p, mockClientProviderPtr)
+
+		if tagged != (state == "success") {
+			t.Errorf("status %s: tagged %v", state, tagged)
+		}
+	}
+}
+
+func TestNotifyURL(t *testing.T) {
+	var body map[string]interface{}
+	var header string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("X-Repo")
+		json.NewDecoder(r.Body).Decode(&body)
+	}))
+	defer server.Close()
+
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+	mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		return provider.NewTestResponse(200, provider.MockContentResponse(fmt.Sprintf(`
+path: pom.xml
+notify_url: %s
+notify_headers:
+  X-Repo: "{{.Repo}}"`, server.URL)))
+	})
+
+	ActionPush(&p, mockClientProviderPtr)
+
+	expected := map[string]interface{}{
+		"repo": "Codertocat/Hello-World", "tag": "v5", "sha": p.GetAfter(), "version": "5", "pusher": "Codertocat",
+	}
+	if fmt.Sprint(body) != fmt.Sprint(expected) {
+		t.Errorf("Wrong notification! expected: %v, got: %v", expected, body)
+	}
+	if header != "Codertocat/Hello-World" {
+		t.Errorf("Wrong header: %q", header)
+	}
+}
+
+func TestCustomFiles(t *testing.T) {
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+	mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		return provider.NewTestResponse(200, provider.MockContentResponse(`
+custom_files:
+  - path: release.ini
+    regexstr: "release = (.+)"
+  - path: pom.xml
+    regexstr: "<(version)>(?P<version>\\d+)</version>"`))
+	})
+	tag := ""
+	mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		tag = fmt.Sprint(provider.GetBodyJson(req)["tag"])
+		return defaultFn(req)
+	})
+
+	ActionPush(&p, mockClientProviderPtr)
+
+	if tag != "v5" {
+		t.Errorf("expected tag v5 from the second custom file, got %q", tag)
+	}
+}
+
+func TestSignedTag(t *testing.T) {
+	_, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+	block, err := ssh.MarshalPrivateKey(privateKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	var tests = []struct {
+		key           string
+		messagePrefix string
+		comment       string
+	}{
+		{string(pem.EncodeToMemory(block)), "v5\n-----BEGIN SSH SIGNATURE-----\n", `Added a new version for "Codertocat/Hello-World": "v5"`},
+		{"not a key", "", `tag "v5" wasn't created: signing key: `},
+	}
+	for _, test := range tests {
+		t.Setenv(envvars.TagSigningKey, test.key)
+		mockClientProviderPtr := provider.DefaultMockClientProvider()
+		mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			return provider.NewTestResponse(200, provider.MockContentResponse("path: pom.xml"))
+		})
+		message, comment := "", ""
+		mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			message = fmt.Sprint(provider.GetBodyJson(req)["message"])
+			return defaultFn(req)
+		})
+		mockClientProviderPtr.OverrideResponseFn("ADD_COMMENT", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			comment = fmt.Sprint(provider.GetBodyJson(req)["body"])
+			return defaultFn(req)
+		})
+
+		ActionPush(&p, mockClientProviderPtr)
+
+		if !strings.HasPrefix(message, test.messagePrefix) || test.messagePrefix == "" && message != "" {
+			t.Errorf("expected tag message prefix %q, got %q", test.messagePrefix, message)
+		}
+		if !strings.HasPrefix(comment, test.comment) {
+			t.Errorf("expected comment prefix %q, got %q", test.comment, comment)
+		}
+	}
+}
+
+func TestNotifications(t *testing.T) {
+	var slack, webhook map[string]string
+	slackServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&slack)
+	}))
+	defer slackServer.Close()
+	webhookServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&webhook)
+	}))
+	defer webhookServer.Close()
+
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	for _, tagFails := range []bool{false, true} {
+		slack, webhook = nil, nil
+		mockClientProviderPtr := provider.DefaultMockClientProvider()
+		mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			return provider.NewTestResponse(200, provider.MockContentResponse(fmt.Sprintf(`
+path: pom.xml
+notifications:
+  - type: slack
+    url: %s
+  - type: webhook
+    url: %s`, slackServer.URL, webhookServer.URL)))
+		})
+		if tagFails {
+			mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+				return provider.NewTestResponse(422, `{"message": "Reference already exists"}`)
+			})
+		}
+
+		ActionPush(&p, mockClientProviderPtr)
+
+		expectedText := "Tag v5 created in Codertocat/Hello-World at 0000000 by Codertocat"
+		expectedEvent := "created"
+		if tagFails {
+			expectedText = "Tag v5 wasn't created in Codertocat/Hello-World at 0000000: "
+			expectedEvent = "failed"
+		}
+		if !strings.HasPrefix(slack["text"], expectedText) {
+			t.Errorf("Wrong slack message! expected prefix: %q, got: %q", expectedText, slack["text"])
+		}
+		if webhook["event"] != expectedEvent || webhook["tag"] != "v5" {
+			t.Errorf("Wrong webhook notification! expected %s event for v5, got: %v", expectedEvent, webhook)
+		}
+		if tagFails == (webhook["error"] == "") {
+			t.Errorf("Wrong webhook error: %q", webhook["error"])
+		}
+	}
+}
+
+func TestCheckVersion(t *testing.T) {
+	var tests = []struct {
+		versionFormat string
+		version       string
+		err           string
+	}{
+		{"", "anything", "<nil>"},
+		{`^\d+\.\d+\.\d+$`, "1.2.3", "<nil>"},
+		{`^\d+\.\d+\.\d+$`, "1.2.3-SNAPSHOT", `version "1.2.3-SNAPSHOT" doesn't match version_format ^\d+\.\d+\.\d+$`},
+		{`^\d{4}\.\d{2}\.\d{2}$`, "2024.01.15", "<nil>"},
+		{`[`, "1.0", "wrong version_format: error parsing regexp: missing closing ]: `[`"},
+	}
+	for _, test := range tests {
+		err := checkVersion(&settings.AtcSettings{Path: "pom.xml", VersionFormat: test.versionFormat}, test.version)
+		if fmt.Sprint(err) != test.err {
+			t.Errorf("format %q, version %q: expected %s, got %v", test.versionFormat, test.version, test.err, err)
+		}
+	}
+}
+
+func TestVersionFormatBlocksTag(t *testing.T) {
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+	tagged := false
+	var message string
+	mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		return provider.NewTestResponse(200, provider.MockContentResponse(`
+path: pom.xml
+version_format: '^\d+\.\d+\.\d+$'`))
+	})
+	mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		tagged = true
+		return defaultFn(req)
+	})
+	mockClientProviderPtr.OverrideResponseFn("ADD_COMMENT", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		message = fmt.Sprintf("%v", provider.GetBodyJson(req)["body"])
+		return defaultFn(req)
+	})
+
+	ActionPush(&p, mockClientProviderPtr)
+
+	if tagged {
+		t.Errorf("version 5 doesn't match the format and shouldn't be tagged")
+	}
+	expected := `tag wasn't created: version "5" doesn't match version_format ^\d+\.\d+\.\d+$`
+	if message != expected {
+		t.Errorf("Wrong comment! expected: %q, got: %q", expected, message)
+	}
+}
+
+func TestCheckVersionLength(t *testing.T) {
+	var tests = []struct {
+		maxVersionLength int
+		version          string
+		err              string
+	}{
+		{0, strings.Repeat("1", 50), "<nil>"},
+		{0, strings.Repeat("1", 51), `version from "pom.xml" is 51 characters long, max_version_length is 50`},
+		{5, "1.2.3", "<nil>"},
+		{5, "1.2.3-rc1", `version from "pom.xml" is 9 characters long, max_version_length is 5`},
+		{200, strings.Repeat("1", 51), "<nil>"},
+	}
+	for _, test := range tests {
+		err := checkVersion(&settings.AtcSettings{Path: "pom.xml", MaxVersionLength: test.maxVersionLength}, test.version)
+		if fmt.Sprint(err) != test.err {
+			t.Errorf("max %d, version %q: expected %s, got %v", test.maxVersionLength, test.version, test.err, err)
+		}
+	}
+}
+
+func TestCrossRepoTagging(t *testing.T) {
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+	var mu sync.Mutex
+	tags := map[string]string{}
+	installations := map[string]bool{}
+	mockClientProviderPtr.OverrideResponseFn("GET_REPO_INSTALLATION", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		mu.Lock()
+		defer mu.Unlock()
+		installations[strings.TrimSuffix(strings.TrimPrefix(req.URL.Path, "/repos/"), "/installation")] = true
+		return defaultFn(req)
+	})
+	mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		switch {
+		case strings.HasPrefix(req.URL.Path, "/repos/Codertocat/app/"):
+			return provider.NewTestResponse(200, provider.MockContentResponse(`cross_repo_sources: ["codertocat/hello-world"]`))
+		case strings.HasPrefix(req.URL.Path, "/repos/Codertocat/service/"):
+			// doesn't accept tags of Hello-World
+			return provider.NewTestResponse(200, provider.MockContentResponse(`cross_repo_sources: ["Codertocat/other"]`))
+		}
+		return provider.NewTestResponse(200, provider.MockContentResponse(`
+path: pom.xml
+cross_repo_tagging:
+  - repo: Codertocat/app
+    tag_template: "lib-{{.Version}}"
+  - repo: Codertocat/legacy
+    tag_template: "lib-{{.Version}}"
+    condition: "v4*"
+  - repo: Codertocat/service
+    tag_template: "hello-{{.Version}}"
+    condition: "v5*"`))
+	})
+	mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		mu.Lock()
+		defer mu.Unlock()
+		j := provider.GetBodyJson(req)
+		repo := strings.Split(strings.TrimPrefix(req.URL.Path, "/repos/"), "/git/")[0]
+		tags[repo] = fmt.Sprintf("%v@%v", j["tag"], j["object"])
+		return defaultFn(req)
+	})
+
+	ActionPush(&p, mockClientProviderPtr)
+
+	expected := map[string]string{
+		"Codertocat/Hello-World": "v5@" + p.GetAfter(),
+		"Codertocat/app":         "lib-5@1234567890123456789012345678901234567890",
+	}
+	if fmt.Sprint(tags) != fmt.Sprint(expected) {
+		t.Errorf("Wrong tags! expected: %v, got: %v", expected, tags)
+	}
+	if expected := map[string]bool{"Codertocat/app": true, "Codertocat/service": true}; fmt.Sprint(installations) != fmt.Sprint(expected) {
+		t.Errorf("Wrong installations! expected: %v, got: %v", expected, installations)
+	}
+}
+
+func TestCrossRepoTaggingSettings(t *testing.T) {
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+	var mu sync.Mutex
+	refs := map[string]string{}
+	mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		if strings.HasPrefix(req.URL.Path, "/repos/Codertocat/app/") {
+			return provider.NewTestResponse(200, provider.MockContentResponse(`cross_repo_sources: ["Codertocat/Hello-World"]`))
+		}
+		return provider.NewTestResponse(200, provider.MockContentResponse(`
+path: pom.xml
+tag_type: lightweight
+tag_ref_format: refs/tags/releases/%s
+cross_repo_tagging:
+  - repo: Codertocat/app
+    tag_template: "lib-{{.Version}}"`))
+	})
+	mockClientProviderPtr.OverrideResponseFn("ADD_REF", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		mu.Lock()
+		defer mu.Unlock()
+		j := provider.GetBodyJson(req)
+		repo := strings.Split(strings.TrimPrefix(req.URL.Path, "/repos/"), "/git/")[0]
+		refs[repo] = fmt.Sprintf("%v@%v", j["ref"], j["sha"])
+		return defaultFn(req)
+	})
+	tags := 0
+	mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		tags++
+		return defaultFn(req)
+	})
+
+	ActionPush(&p, mockClientProviderPtr)
+
+	expected := map[string]string{
+		"Codertocat/Hello-World": "refs/tags/releases/v5@" + p.GetAfter(),
+		"Codertocat/app":         "refs/tags/releases/lib-5@1234567890123456789012345678901234567890",
+	}
+	if fmt.Sprint(refs) != fmt.Sprint(expected) || tags != 0 {
+		t.Errorf("Wrong refs! expected: %v and no tag objects, got: %v and %d", expected, refs, tags)
+	}
+}
+
+func TestCrossRepoTaggingDryRun(t *testing.T) {
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+	requests := 0
+	mockClientProviderPtr.OverrideResponseFn("GET_REPO_INSTALLATION", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		requests++
+		return defaultFn(req)
+	})
+	setting := &settings.AtcSettings{
+		DryRun:           true,
+		CrossRepoTagging: []settings.CrossRepoTarget{{Repo: "Codertocat/app", TagTemplate: "lib-{{.Version}}"}},
+	}
+	tagCrossRepoTargets(context.Background(), mockClientProviderPtr, setting,
+		TagContent{Version: "5", Tag: "v5", Repository: "Codertocat/Hello-World"}, &github.CommitAuthor{}, nil)
+	if requests != 0 {
+		t.Errorf("dry run looked up %d installations", requests)
+	}
+}
+
+func TestRepositoryTopicsOverrideSettings(t *testing.T) {
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+	tag := ""
+	mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		return provider.NewTestResponse(200, provider.MockContentResponse("path: pom.xml"))
+	})
+	mockClientProviderPtr.OverrideResponseFn("GET_REPO", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		return provider.NewTestResponse(200, `{"full_name": "Codertocat/Hello-World", "default_branch": "main", "topics": ["java", "atc:template:release-{{.Version}}"]}`)
+	})
+	mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		j := provider.GetBodyJson(req)
+		tag = fmt.Sprintf("%v", j["tag"])
+		return defaultFn(req)
+	})
+
+	ActionPush(&p, mockClientProviderPtr)
+
+	if tag != "release-5" {
+		t.Errorf("Wrong tag! expected: %s, got: %s", "release-5", tag)
+	}
+}
+
+func TestSkipBotPush(t *testing.T) {
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+	botName := "github-actions[bot]"
+	p.Pusher.Name = &botName
+
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+	tagged := false
+	mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		tagged = true
+		return defaultFn(req)
+	})
+
+	ActionPush(&p, mockClientProviderPtr)
+
+	if tagged {
+		t.Errorf("push of %q was tagged", botName)
+	}
+}
+
+func TestAndroidVersionsInTemplate(t *testing.T) {
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+	tag := ""
+	mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		return provider.NewTestResponse(200, provider.MockContentResponse(`
+path: build.gradle
+template: "v{{.Version}}-{{.VersionName}}-build{{.VersionCode}}"`))
+	})
+	gradleRequests := 0
+	mockClientProviderPtr.OverrideResponseFn("GET_NEW_VERSION_GRADLE", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		gradleRequests++
+		return defaultFn(req)
+	})
+	mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		j := provider.GetBodyJson(req)
+		tag = fmt.Sprintf("%v", j["tag"])
+		return defaultFn(req)
+	})
+
+	ActionPush(&p, mockClientProviderPtr)
+
+	if tag != "v5-5-build1" {
+		t.Errorf("Wrong tag! expected: %s, got: %s", "v5-5-build1", tag)
+	}
+	if gradleRequests != 1 {
+		t.Errorf("expected build.gradle to be fetched once, got %d", gradleRequests)
+	}
+}
+
+func TestFetcherForExtensionType(t *testing.T) {
+	if _, ok := uninstrumented(fetcherForType("atc.cabal")).(*cabal.Fetcher); !ok {
+		t.Errorf("expected the cabal fetcher for atc.cabal")
+	}
+	if _, ok := uninstrumented(fetcherForType("image.auto.pkrvars.hcl")).(*packervars.Fetcher); !ok {
+		t.Errorf("expected the packer variables fetcher for image.auto.pkrvars.hcl")
+	}
+	if f := fetcherForType("variables.pkr.hcl"); f != nil {
+		t.Errorf("expected no fetcher for variables.pkr.hcl, got %T", f)
+	}
+	if f := fetcherForType("atc.version"); f != nil {
+		t.Errorf("expected no fetcher for atc.version, got %T", f)
+	}
+}
+
+// uninstrumented returns the fetcher wrapped for the metrics.
+func uninstrumented(f fetcher.VersionFetcher) fetcher.VersionFetcher {
+	if instrumented, ok := f.(*fetcher.InstrumentedVersionFetcher); ok {
+		return instrumented.VersionFetcher
+	}
+	return f
+}
+
+func TestFetchersInstrumented(t *testing.T) {
+	for name, f := range autoFetchers {
+		if instrumented, ok := f.(*fetcher.InstrumentedVersionFetcher); !ok || instrumented.Name != name {
+			t.Errorf("%s: expected an instrumented fetcher, got %T", name, f)
+		}
+	}
+}
+
+func TestFetchDurationLabel(t *testing.T) {
+	count := func(label string) uint64 {
+		m := &dto.Metric{}
+		metrics.VersionFetchDuration.WithLabelValues(label).(prometheus.Histogram).Write(m)
+		return m.GetHistogram().GetSampleCount()
+	}
+	var tests = []struct {
+		setting settings.AtcSettings
+		label   string
+	}{
+		{settings.AtcSettings{Path: "src/Atc.csproj"}, ".csproj"},
+		{settings.AtcSettings{Path: "atc.gemspec"}, ".gemspec"},
+		{settings.AtcSettings{Path: "build/Directory.Build.props"}, "Directory.Build.props"},
+		{settings.AtcSettings{Path: "VERSION.txt", RegexStr: `(\S+)`}, "custom"},
+	}
+	for _, test := range tests {
+		before := count(test.label)
+		fetchVersion(&test.setting, &provider.MockContentProvider{Content: "1.0.0"})
+		if fetches := count(test.label) - before; fetches != 1 {
+			t.Errorf("path %q: expected 1 fetch recorded as %q, got %d", test.setting.Path, test.label, fetches)
+		}
+	}
+}
+
+func TestFetchDurationRecorded(t *testing.T) {
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	count := func() uint64 {
+		m := &dto.Metric{}
+		metrics.VersionFetchDuration.WithLabelValues("pom.xml").(prometheus.Histogram).Write(m)
+		return m.GetHistogram().GetSampleCount()
+	}
+	before := count()
+
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+	mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		return provider.NewTestResponse(200, provider.MockContentResponse("path: pom.xml"))
+	})
+	ActionPush(&p, mockClientProviderPtr)
+
+	// the old and the new version
+	if fetches := count() - before; fetches != 2 {
+		t.Errorf("expected 2 fetches of pom.xml recorded, got %d", fetches)
+	}
+}
+
+func TestAutoFetcherOrder(t *testing.T) {
+	seen := map[string]bool{}
+	for _, name := range autoFetcherOrder {
+		if _, ok := autoFetchers[name]; !ok || seen[name] {
+			t.Errorf("%s: unknown or repeated in autoFetcherOrder", name)
+		}
+		seen[name] = true
+	}
+	if len(seen) != len(autoFetchers) {
+		t.Errorf("autoFetcherOrder has %d of %d fetchers", len(seen), len(autoFetchers))
+	}
+
+	cp := provider.MockFilesContentProvider{
+		"pom.xml":      "<project><version>1.0.0</version></project>",
+		"package.json": `{"version": "2.0.0"}`,
+		"Cargo.toml":   "[package]\nversion = \"3.0.0\"",
+	}
+	for i := 0; i < 10; i++ {
+		if version, err := fetchVersion(&settings.AtcSettings{}, cp); err != nil || version != "1.0.0" {
+			t.Fatalf("expected the version of pom.xml, got %q, %v", version, err)
+		}
+	}
+}
+
+func TestNormalizeVersion(t *testing.T) {
+	var tests = []struct {
+		config     string
+		oldVersion string
+		newVersion string
+		tag        string
+	}{
+		{"normalize_version: true", "1.2", "1.02.003", "v1.2.3"},
+		{"normalize_version: true", "1.2", "1.2.0", ""},
+		{"normalize_version: false", "1.2", "1.2.0", "v1.2.0"},
+	}
+
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	for _, test := range tests {
+		mockClientProviderPtr := provider.DefaultMockClientProvider()
+		tag := ""
+		config := "path: pom.xml\ntemplate: v{{.Version}}\n" + test.config
+		mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			return provider.NewTestResponse(200, provider.MockContentResponse(config))
+		})
+		mockClientProviderPtr.OverrideResponseFn("GET_OLD_VERSION_MAVEN", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			return provider.NewTestResponse(200, provider.MockContentResponse("<project><version>"+test.oldVersion+"</version></project>"))
+		})
+		mockClientProviderPtr.OverrideResponseFn("GET_NEW_VERSION_MAVEN", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			return provider.NewTestResponse(200, provider.MockContentResponse("<project><version>"+test.newVersion+"</version></project>"))
+		})
+		mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			tag = fmt.Sprintf("%v", provider.GetBodyJson(req)["tag"])
+			return defaultFn(req)
+		})
+
+		ActionPush(&p, mockClientProviderPtr)
+
+		if tag != test.tag {
+			t.Errorf("%s, %q -> %q: expected tag %q, got %q", test.config, test.oldVersion, test.newVersion, test.tag, tag)
+		}
+	}
+}
+
+func TestTagAnnotatorLogin(t *testing.T) {
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+	var tagger interface{}
+	mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		return provider.NewTestResponse(200, provider.MockContentResponse("path: pom.xml\ntag_annotator_login: release-bot"))
+	})
+	mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		tagger = provider.GetBodyJson(req)["tagger"]
+		return defaultFn(req)
+	})
+
+	ActionPush(&p, mockClientProviderPtr)
+
+	author := tagger.(map[string]interface{})
+	if author["name"] != "release-bot" || author["email"] != "12345+release-bot@users.noreply.github.com" {
+		t.Errorf("expected tagger %q <%s>, got %q <%v>", "release-bot", "12345+release-bot@users.noreply.github.com", author["name"], author["email"])
+	}
+}
+
+func TestTaggerIdentity(t *testing.T) {
+	var tests = []struct {
+		config     string
+		botMissing bool
+		name       string
+		email      string
+	}{
+		{"", false, "atc[bot]", "41898282+atc[bot]@users.noreply.github.com"},
+		{"", true, "Codertocat", "21031067+Codertocat@users.noreply.github.com"},
+		{"tagger_name: Release Bot\ntagger_email: release-bot@example.com", false, "Release Bot", "release-bot@example.com"},
+		{"tagger_email: release-bot@example.com", false, "atc[bot]", "release-bot@example.com"},
+		{"tagger_name: Release Bot", false, "Release Bot", "41898282+atc[bot]@users.noreply.github.com"},
+		{"tagger_email: release-bot@example.com", true, "Codertocat", "release-bot@example.com"},
+		{"tagger_name: Release Bot", true, "Release Bot", "21031067+Codertocat@users.noreply.github.com"},
+	}
+
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	for _, test := range tests {
+		appBot.author = nil
+		mockClientProviderPtr := provider.DefaultMockClientProvider()
+		var tagger map[string]interface{}
+		mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			return provider.NewTestResponse(200, provider.MockContentResponse("path: pom.xml\n"+test.config))
+		})
+		if test.botMissing {
+			mockClientProviderPtr.OverrideResponseFn("GET_APP_BOT_USER", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+				return provider.NewTestResponse(404, `{"message": "Not Found"}`)
+			})
+		}
+		mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			tagger, _ = provider.GetBodyJson(req)["tagger"].(map[string]interface{})
+			return provider.NewTestResponse(201, `{}`)
+		})
+
+		ActionPush(&p, mockClientProviderPtr)
+
+		if tagger["name"] != test.name || tagger["email"] != test.email {
+			t.Errorf("config %q, bot missing %v: expected tagger %q <%s>, got %v", test.config, test.botMissing, test.name, test.email, tagger)
+		}
+	}
+	appBot.author = nil
+}
+
+func TestTagRefFormat(t *testing.T) {
+	var tests = []struct {
+		config string
+		ref    string
+	}{
+		{"", "refs/tags/v5"},
+		{"tag_ref_format: refs/environments/prod/%s", "refs/environments/prod/v5"},
+	}
+
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	for _, test := range tests {
+		mockClientProviderPtr := provider.DefaultMockClientProvider()
+		ref := ""
+		config := "path: pom.xml\n" + test.config
+		mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			return provider.NewTestResponse(200, provider.MockContentResponse(config))
+		})
+		mockClientProviderPtr.OverrideResponseFn("ADD_REF", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			ref = fmt.Sprintf("%v", provider.GetBodyJson(req)["ref"])
+			return defaultFn(req)
+		})
+
+		ActionPush(&p, mockClientProviderPtr)
+
+		if ref != test.ref {
+			t.Errorf("config %q: expected ref %q, got %q", test.config, test.ref, ref)
+		}
+	}
+}
+
+func TestMonorepoTargets(t *testing.T) {
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+	var tags []string
+	mockClientProviderPtr.OverrideResponseFn("GET_ATC_CONFIG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		return provider.NewTestResponse(200, provider.MockContentResponse(`
+targets:
+  - path: pom.xml
+    tag_prefix: service-a/
+  - path: package.json
+    template: "release-{{.Version}}"
+    tag_prefix: service-b/`))
+	})
+	mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		tags = append(tags, fmt.Sprintf("%v", provider.GetBodyJson(req)["tag"]))
+		return defaultFn(req)
+	})
+
+	ActionPush(&p, mockClientProviderPtr)
+
+	if len(tags) != 2 || !strings.HasPrefix(tags[0], "service-a/v") || !strings.HasPrefix(tags[1], "service-b/release-") {
+		t.Errorf("expected a tag per target, got %q", tags)
+	}
+}
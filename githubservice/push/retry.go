@@ -0,0 +1,132 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/google/go-github/v39/github"
+
+	"github.com/smartforce-io/atc/envvars"
+	"github.com/smartforce-io/atc/githubservice/accesstoken"
+	"github.com/smartforce-io/atc/githubservice/gitutil"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/retryqueue"
+	"github.com/smartforce-io/atc/githubservice/settings"
+	"github.com/smartforce-io/atc/logger"
+)
+
+// TagRetryQueue retries the tags the webhook server failed to create for
+// a transient GitHub error. It's nil, no retries, unless main sets it.
+var TagRetryQueue *retryqueue.Queue
+
+// tagJob is the payload of a queued tag.
+type tagJob struct {
+	InstallationID int64       `json:"installation_id"`
+	Owner          string      `json:"owner"`
+	Repo           string      `json:"repo"`
+	SHA            string      `json:"sha"`
+	Tag            *github.Tag `json:"tag"`
+	TagType        string      `json:"tag_type,omitempty"`
+	RefFormat      string      `json:"ref_format"`
+	// Comments is the comments setting, SuccessComment the comment of the
+	// created tag.
+	Comments       string `json:"comments,omitempty"`
+	SuccessComment string `json:"success_comment"`
+	// Branch is the environment branch fast-forwarded to SHA, see
+	// propagateToEnvironments.
+	Branch string `json:"branch,omitempty"`
+}
+
+// NewTagRetryQueue returns the queue of tags to retry, persisted to path if
+// it isn't empty. The installation tokens come from clientProvider.
+func NewTagRetryQueue(path string, clientProvider provider.ClientProvider) (*retryqueue.Queue, error) {
+	client := func(job *tagJob) (*github.Client, error) {
+		token, err := accesstoken.GetAccessToken(job.InstallationID, clientProvider)
+		if err != nil {
+			return nil, err
+		}
+		return clientProvider.Get(token, context.Background()), nil
+	}
+	handler := func(ctx context.Context, queued retryqueue.Job) error {
+		job := &tagJob{}
+		if err := json.Unmarshal(queued.Payload, job); err != nil {
+			return err
+		}
+		client, err := client(job)
+		if err != nil {
+			return err
+		}
+		if err := runTagJob(client, job); err != nil {
+			return err
+		}
+		if job.SuccessComment != "" {
+			addInfoComment(client, &settings.AtcSettings{Comments: job.Comments}, job.Owner, job.Repo, job.SHA, job.SuccessComment)
+		}
+		return nil
+	}
+	onGiveUp := func(ctx context.Context, queued retryqueue.Job, err error) {
+		job := &tagJob{}
+		if json.Unmarshal(queued.Payload, job) != nil {
+			return
+		}
+		client, clientErr := client(job)
+		if clientErr != nil {
+			logger.Errorf("getAccessToken Error: %v", clientErr)
+			return
+		}
+		addComment(client, &settings.AtcSettings{Comments: job.Comments}, job.Owner, job.Repo, job.SHA,
+			fmt.Sprintf("can't add tag to commit after %d attempts, error : %v", queued.Attempts+1, err))
+	}
+	return retryqueue.New(path, handler, onGiveUp)
+}
+
+// runTagJob moves the branch of job, if any, and creates its tag. The branch
+// goes first as moving it again to the same commit is harmless when the tag
+// is retried.
+func runTagJob(client *github.Client, job *tagJob) error {
+	if job.Branch != "" {
+		if err := gitutil.UpdateBranch(client, job.Owner, job.Repo, job.Branch, job.SHA); err != nil {
+			return fmt.Errorf("can't move branch %q to commit: %w", job.Branch, err)
+		}
+	}
+	signer, err := tagSigner(envvars.TagSigningKey, envvars.TagSigningKeyPassphrase)
+	if err != nil {
+		return err
+	}
+	return addTag(client, job.Owner, job.Repo, job.TagType, signer, job.Tag, job.SHA, job.RefFormat)
+}
+
+// queueTag queues tag for a retry if TagRetryQueue is set and err is
+// transient, and reports whether it did.
+func queueTag(ctx context.Context, err error, job *tagJob) bool {
+	if TagRetryQueue == nil || !isTransient(err) {
+		return false
+	}
+	id := fmt.Sprintf("tag:%s/%s:%s:%s", job.Owner, job.Repo, job.Tag.GetTag(), job.SHA)
+	if err := TagRetryQueue.Add(id, job); err != nil {
+		logger.FromContext(ctx).Errorf("retry queue error for %q: %v", id, err)
+		return false
+	}
+	return true
+}
+
+// isTransient reports whether err of the GitHub API may go away on a retry:
+// a server error, a rate limit or a network error.
+func isTransient(err error) bool {
+	var errorResponse *github.ErrorResponse
+	var rateLimitError *github.RateLimitError
+	var abuseRateLimitError *github.AbuseRateLimitError
+	var netError net.Error
+	switch {
+	case errors.As(err, &rateLimitError), errors.As(err, &abuseRateLimitError), errors.As(err, &netError):
+		return true
+	case errors.As(err, &errorResponse):
+		status := errorResponse.Response.StatusCode
+		return status >= http.StatusInternalServerError || status == http.StatusTooManyRequests
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v39/github"
+
+	"github.com/smartforce-io/atc/envvars"
+	"github.com/smartforce-io/atc/githubservice/provider"
+)
+
+func TestTagRetryQueue(t *testing.T) {
+	p := github.WebHookPayload{}
+	json.Unmarshal([]byte(testWebhookPayload), &p)
+	os.Setenv(envvars.PemData, testRsaKey)
+
+	tagStatus := http.StatusBadGateway
+	var mu sync.Mutex
+	var comments []string
+	commented := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string{}, comments...)
+	}
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+	mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		if tagStatus != http.StatusCreated {
+			return provider.NewTestResponse(tagStatus, `{"message": "Server Error"}`)
+		}
+		return defaultFn(req)
+	})
+	mockClientProviderPtr.OverrideResponseFn("ADD_COMMENT", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		mu.Lock()
+		comments = append(comments, fmt.Sprint(provider.GetBodyJson(req)["body"]))
+		mu.Unlock()
+		return defaultFn(req)
+	})
+
+	queue, err := NewTagRetryQueue("", mockClientProviderPtr)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	queue.BaseDelay, queue.MaxDelay = time.Millisecond, time.Millisecond
+	TagRetryQueue = queue
+	defer func() { TagRetryQueue = nil }()
+
+	ActionPush(&p, mockClientProviderPtr)
+
+	if queue.Len() != 1 || len(commented()) != 0 {
+		t.Fatalf("expected a queued tag without comments, got %d, %q", queue.Len(), commented())
+	}
+
+	tagStatus = http.StatusCreated
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go queue.Run(ctx)
+	for len(commented()) == 0 && ctx.Err() == nil {
+		time.Sleep(time.Millisecond)
+	}
+	expected := `Added a new version for "Codertocat/Hello-World": "v5"`
+	if comments := commented(); len(comments) != 1 || !strings.HasSuffix(comments[0], expected) || queue.Len() != 0 {
+		t.Errorf("expected comment %q and an empty queue, got %q, %d", expected, comments, queue.Len())
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	response := func(status int) error {
+		return &github.ErrorResponse{Response: &http.Response{StatusCode: status}}
+	}
+	var tests = []struct {
+		err       error
+		transient bool
+	}{
+		{response(http.StatusBadGateway), true},
+		{response(http.StatusTooManyRequests), true},
+		{fmt.Errorf("create tag: %w", response(http.StatusServiceUnavailable)), true},
+		{&github.RateLimitError{}, true},
+		{response(http.StatusUnprocessableEntity), false},
+		{errors.New("signing error"), false},
+	}
+	for _, test := range tests {
+		if transient := isTransient(test.err); transient != test.transient {
+			t.Errorf("%v: expected %v, got %v", test.err, test.transient, transient)
+		}
+	}
+}
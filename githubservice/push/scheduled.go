@@ -0,0 +1,112 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/go-github/v39/github"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+	"github.com/smartforce-io/atc/logger"
+)
+
+// CIScheduledAction tags the head of a branch with tagTemplate even if the
+// version didn't change, e.g. for nightly builds. The branch is taken from
+// BRANCH and defaults to the repository's default branch.
+func CIScheduledAction(ctx context.Context, tagTemplate string) error {
+	fullname := os.Getenv("GITHUB_REPOSITORY")
+	s := strings.Split(fullname, "/")
+	if len(s) != 2 {
+		return fmt.Errorf("wrong GITHUB_REPOSITORY %q", fullname)
+	}
+	client := (&provider.GithubClientProvider{}).Get(os.Getenv("GITHUB_TOKEN"), ctx)
+
+	return scheduledTag(ctx, client, s[0], s[1], os.Getenv("BRANCH"), getCISettings(), tagTemplate)
+}
+
+func scheduledTag(ctx context.Context, client *github.Client, owner, repo, branch string,
+	atcs *settings.AtcSettings, tagTemplate string) error {
+	fullname := owner + "/" + repo
+	if tagTemplate == "" {
+		return errors.New("tag template is empty")
+	}
+	if branch == "" {
+		r, _, err := client.Repositories.Get(ctx, owner, repo)
+		if err != nil {
+			return fmt.Errorf("error getting repository %q: %v", fullname, err)
+		}
+		branch = r.GetDefaultBranch()
+	}
+
+	b, _, err := client.Repositories.GetBranch(ctx, owner, repo, branch, true)
+	if err != nil {
+		return fmt.Errorf("error getting branch %q: %v", branch, err)
+	}
+	sha := b.GetCommit().GetSHA()
+
+	cp := &provider.GhContentProvider{
+		Owner:    owner,
+		Repo:     repo,
+		Ref:      sha,
+		Ctx:      ctx,
+		GhClient: client,
+	}
+	version, err := fetchVersion(atcs, cp)
+	if err != nil {
+		return fmt.Errorf("fetch version error: %v", err)
+	}
+
+	if err := checkVersion(atcs, version); err != nil {
+		return err
+	}
+
+	caption, err := renderTemplate(tagTemplate, TagContent{Version: version, Repository: fullname})
+	if err != nil {
+		return fmt.Errorf("error in go templates: %v", err)
+	}
+
+	if atcs.DryRun {
+		logger.Infof("Dry run: scheduled tag %q would be added to %s of %q", caption, sha, fullname)
+		return nil
+	}
+
+	tagger := NewTagger(client, owner, repo, nil, cp)
+	tagger.Settings = atcs
+	if tagger.Signer, err = tagSigner("TAG_SIGNING_KEY", "TAG_SIGNING_KEY_PASSPHRASE"); err != nil {
+		return fmt.Errorf("tag signing key error: %v", err)
+	}
+	err = tagger.CreateTag(TagContent{Version: version, Repository: fullname, Tag: caption}, sha, b.GetCommit().GetCommit().GetAuthor())
+	if errors.Is(err, ErrTagOnCommit) || errors.Is(err, ErrTagExists) && atcs.OnExistingTag != settings.OnExistingTagError {
+		logger.Infof("Scheduled tag %q already exists in %q, skipped", caption, fullname)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error when adding tag to commit %q: %v", fullname, err)
+	}
+
+	logger.Infof("Added a scheduled tag for %q: %q", fullname, caption)
+	return nil
+}
+
+// fetchVersion reads the current version with the fetcher for atcs.Path or
+// custom_files or, if both are empty, with the first default fetcher that
+// finds one.
+func fetchVersion(atcs *settings.AtcSettings, cp provider.ContentProvider) (string, error) {
+	if settingsFetchType(atcs) == "" {
+		for _, defaultPath := range autoFetcherOrder {
+			if version, err := autoFetchers[defaultPath].GetVersionUsingDefaultPath(cp); err == nil {
+				return version, nil
+			}
+		}
+		return "", errors.New("unable to fetch version using known methods")
+	}
+	af, err := resolveFetcher(atcs, cp)
+	if err != nil {
+		return "", err
+	}
+	return instrumented(af).GetVersion(cp, *atcs)
+}
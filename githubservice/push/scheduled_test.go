@@ -0,0 +1,96 @@
+package push
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+func TestScheduledTag(t *testing.T) {
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+	var tag, object string
+	mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		j := provider.GetBodyJson(req)
+		tag = fmt.Sprintf("%v", j["tag"])
+		object = fmt.Sprintf("%v", j["object"])
+		return defaultFn(req)
+	})
+	client := mockClientProviderPtr.Get("", context.Background())
+
+	err := scheduledTag(context.Background(), client, "Codertocat", "Hello-World", "main",
+		&settings.AtcSettings{Path: "pom.xml"}, `nightly-{{.Version}}-{{Time.Format "2006-01-02"}}`)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	expected := "nightly-4-" + time.Now().Format("2006-01-02")
+	if tag != expected {
+		t.Errorf("Wrong tag! expected: %s, got: %s", expected, tag)
+	}
+	if object != "1234567890123456789012345678901234567890" {
+		t.Errorf("Wrong tagged commit: %s", object)
+	}
+}
+
+func TestScheduledTagErrors(t *testing.T) {
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+	mockClientProviderPtr.OverrideResponseFn("GET_BRANCH", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		return provider.NewTestResponse(404, `{"message": "Branch not found"}`)
+	})
+	client := mockClientProviderPtr.Get("", context.Background())
+	atcs := &settings.AtcSettings{Path: "pom.xml"}
+
+	if err := scheduledTag(context.Background(), client, "Codertocat", "Hello-World", "main", atcs, ""); err == nil {
+		t.Errorf("expected error for empty template")
+	}
+	if err := scheduledTag(context.Background(), client, "Codertocat", "Hello-World", "missing", atcs, "nightly-{{.Version}}"); err == nil {
+		t.Errorf("expected error for missing branch")
+	}
+}
+
+func TestScheduledTagSettings(t *testing.T) {
+	var tests = []struct {
+		atcs         settings.AtcSettings
+		matchingRefs string
+		expectedTags int
+		expectedRef  string
+	}{
+		{settings.AtcSettings{Path: "pom.xml"}, `[]`, 1, "refs/tags/nightly-4"},
+		{settings.AtcSettings{Path: "pom.xml", DryRun: true}, `[]`, 0, ""},
+		{settings.AtcSettings{Path: "pom.xml", TagType: settings.TagTypeLightweight}, `[]`, 0, "refs/tags/nightly-4"},
+		{settings.AtcSettings{Path: "pom.xml", TagRefFormat: "refs/environments/nightly/%s"}, `[]`, 1, "refs/environments/nightly/nightly-4"},
+		{settings.AtcSettings{Path: "pom.xml", OnlyIfNoExistingTag: true},
+			`[{"ref": "refs/tags/nightly-4", "object": {"sha": "1234567890123456789012345678901234567890", "type": "commit"}}]`, 0, ""},
+		{settings.AtcSettings{Path: "pom.xml", OnExistingTag: settings.OnExistingTagSkip},
+			`[{"ref": "refs/tags/nightly-4", "object": {"sha": "0000000000000000000000000000000000000000", "type": "commit"}}]`, 0, ""},
+	}
+	for _, test := range tests {
+		mockClientProviderPtr := provider.DefaultMockClientProvider()
+		tags, ref := 0, ""
+		mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			tags++
+			return defaultFn(req)
+		})
+		mockClientProviderPtr.OverrideResponseFn("ADD_REF", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			ref = fmt.Sprintf("%v", provider.GetBodyJson(req)["ref"])
+			return defaultFn(req)
+		})
+		matchingRefs := test.matchingRefs
+		mockClientProviderPtr.OverrideResponseFn("GET_MATCHING_REFS", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			return provider.NewTestResponse(200, matchingRefs)
+		})
+		client := mockClientProviderPtr.Get("", context.Background())
+
+		atcs := test.atcs
+		if err := scheduledTag(context.Background(), client, "Codertocat", "Hello-World", "main", &atcs, "nightly-{{.Version}}"); err != nil {
+			t.Errorf("settings %+v: unexpected error %v", test.atcs, err)
+		}
+		if tags != test.expectedTags || ref != test.expectedRef {
+			t.Errorf("settings %+v: expected %d tags and ref %q, got %d and %q", test.atcs, test.expectedTags, test.expectedRef, tags, ref)
+		}
+	}
+}
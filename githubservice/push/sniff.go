@@ -0,0 +1,45 @@
+package push
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher"
+	"github.com/smartforce-io/atc/githubservice/provider"
+)
+
+const sniffLen = 512
+
+var (
+	sniffXMLVersionRegex  = regexp.MustCompile(`<version>`)
+	sniffJSONVersionRegex = regexp.MustCompile(`"version"\s*:`)
+	sniffTOMLVersionRegex = regexp.MustCompile(`(?m)^\s*version\s*=`)
+)
+
+// sniffContent guesses the package manager file type from the first bytes of
+// content. It returns "" if the content isn't recognized.
+func sniffContent(content string) string {
+	if len(content) > sniffLen {
+		content = content[:sniffLen]
+	}
+	content = strings.TrimSpace(content)
+	switch {
+	case strings.HasPrefix(content, "<") && sniffXMLVersionRegex.MatchString(content):
+		return "pom.xml"
+	case strings.HasPrefix(content, "{") && sniffJSONVersionRegex.MatchString(content):
+		return "package.json"
+	case sniffTOMLVersionRegex.MatchString(content):
+		return "Cargo.toml"
+	}
+	return ""
+}
+
+// sniffFetcher reads path and returns the fetcher for its detected type, or
+// nil if the type can't be detected or has no fetcher.
+func sniffFetcher(cp provider.ContentProvider, path string) fetcher.VersionFetcher {
+	content, err := cp.GetContents(path)
+	if err != nil {
+		return nil
+	}
+	return fetcherForType(sniffContent(content))
+}
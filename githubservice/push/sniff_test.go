@@ -0,0 +1,46 @@
+package push
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/fetcher/cargotoml"
+	"github.com/smartforce-io/atc/githubservice/fetcher/packagejson"
+	"github.com/smartforce-io/atc/githubservice/provider"
+)
+
+func TestSniffContent(t *testing.T) {
+	var tests = []struct {
+		content  string
+		expected string
+	}{
+		{"<?xml version=\"1.0\"?>\n<project>\n  <version>1.0</version>\n</project>", "pom.xml"},
+		{"  {\n  \"name\": \"atc\",\n  \"version\": \"1.0.0\"\n}", "package.json"},
+		{"[package]\nname = \"atc\"\nversion = \"0.1.0\"", "Cargo.toml"},
+		{"{\"name\": \"atc\"}", ""},
+		{"<project></project>", ""},
+		{"1.0.0", ""},
+		{"", ""},
+		{"<project>" + strings.Repeat(" ", sniffLen) + "<version>1.0</version></project>", ""},
+	}
+	for _, test := range tests {
+		if fetchType := sniffContent(test.content); fetchType != test.expected {
+			t.Errorf("content %q: expected %q, got %q", test.content, test.expected, fetchType)
+		}
+	}
+}
+
+func TestSniffFetcher(t *testing.T) {
+	cp := provider.MockContentProvider{Content: `{"version": "1.2.3"}`}
+	if _, ok := uninstrumented(sniffFetcher(&cp, "services/api/version-file")).(*packagejson.Fetcher); !ok {
+		t.Errorf("expected package.json fetcher")
+	}
+	cp = provider.MockContentProvider{Err: provider.ErrGeneral}
+	if f := sniffFetcher(&cp, "services/api/version-file"); f != nil {
+		t.Errorf("expected no fetcher, got %T", f)
+	}
+	cp = provider.MockContentProvider{Content: "[package]\nversion = \"1.0\""}
+	if _, ok := uninstrumented(sniffFetcher(&cp, "services/api/version-file")).(*cargotoml.Fetcher); !ok {
+		t.Errorf("expected Cargo.toml fetcher")
+	}
+}
@@ -0,0 +1,64 @@
+package push
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v39/github"
+)
+
+const defaultWaitForStatusTimeout = 10 * time.Minute
+
+var statusPollInterval = 10 * time.Second
+
+// waitForStatus polls the combined status of sha until statusContext reports
+// success. It fails when the status reports failure or error, or when timeout
+// passes first.
+func waitForStatus(client *github.Client, owner, repo, sha, statusContext string, timeout time.Duration) error {
+	if timeout == 0 {
+		timeout = defaultWaitForStatusTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	for {
+		state, err := commitStatusState(ctx, client, owner, repo, sha, statusContext)
+		if err != nil {
+			return fmt.Errorf("can't get status %q of %s: %v", statusContext, sha, err)
+		}
+		switch state {
+		case "success":
+			return nil
+		case "failure", "error":
+			return fmt.Errorf("status %q of %s reported %s", statusContext, sha, state)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("status %q of %s isn't successful after %v", statusContext, sha, timeout)
+		case <-time.After(statusPollInterval):
+		}
+	}
+}
+
+// commitStatusState returns the state of statusContext, or "" if it hasn't
+// been reported yet.
+func commitStatusState(ctx context.Context, client *github.Client, owner, repo, sha, statusContext string) (string, error) {
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		combined, resp, err := client.Repositories.GetCombinedStatus(ctx, owner, repo, sha, opts)
+		if err != nil {
+			return "", err
+		}
+		for _, status := range combined.Statuses {
+			if status.GetContext() == statusContext {
+				return status.GetState(), nil
+			}
+		}
+		if resp.NextPage == 0 {
+			return "", nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
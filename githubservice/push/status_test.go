@@ -0,0 +1,63 @@
+package push
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+)
+
+func statusResponse(state string) string {
+	return fmt.Sprintf(`{"statuses": [{"context": "ci/lint", "state": "success"}, {"context": "ci/jenkins/branch", "state": "%s"}]}`, state)
+}
+
+func TestWaitForStatus(t *testing.T) {
+	savedInterval := statusPollInterval
+	statusPollInterval = time.Millisecond
+	defer func() { statusPollInterval = savedInterval }()
+
+	var tests = []struct {
+		states  []string
+		timeout time.Duration
+		err     string
+	}{
+		{[]string{"success"}, 0, ""},
+		{[]string{"pending", "pending", "success"}, 0, ""},
+		{[]string{"pending", "failure"}, 0, "reported failure"},
+		{[]string{"error"}, 0, "reported error"},
+		{[]string{"pending"}, 20 * time.Millisecond, "isn't successful after"},
+	}
+	for _, test := range tests {
+		mockClientProviderPtr := provider.DefaultMockClientProvider()
+		polls := 0
+		mockClientProviderPtr.OverrideResponseFn("GET_COMBINED_STATUS", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+			state := test.states[len(test.states)-1]
+			if polls < len(test.states) {
+				state = test.states[polls]
+			}
+			polls++
+			return provider.NewTestResponse(200, statusResponse(state))
+		})
+		client := mockClientProviderPtr.Get("", context.Background())
+
+		err := waitForStatus(client, "owner", "repo", "sha", "ci/jenkins/branch", test.timeout)
+		if test.err == "" && err != nil || test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+			t.Errorf("states %v: expected error %q, got %v", test.states, test.err, err)
+		}
+	}
+}
+
+func TestWaitForMissingStatus(t *testing.T) {
+	savedInterval := statusPollInterval
+	statusPollInterval = time.Millisecond
+	defer func() { statusPollInterval = savedInterval }()
+
+	client := provider.DefaultMockClientProvider().Get("", context.Background())
+	if err := waitForStatus(client, "owner", "repo", "sha", "ci/jenkins/branch", 10*time.Millisecond); err == nil {
+		t.Errorf("expected timeout when the status is never reported")
+	}
+}
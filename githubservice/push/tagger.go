@@ -0,0 +1,184 @@
+package push
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/go-github/v39/github"
+
+	"github.com/smartforce-io/atc/githubservice/gitutil"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+	"github.com/smartforce-io/atc/githubservice/tagsign"
+	"github.com/smartforce-io/atc/logger"
+)
+
+// VersionChange is a version change DetectVersionChange found. OldVersion is
+// empty when there is no previous commit or version file.
+type VersionChange struct {
+	OldVersion string
+	NewVersion string
+}
+
+// Tagger is the tagging pipeline of ATC for Go programs embedding it without
+// the webhook server or the environment variables of CI mode:
+//
+//	tagger := push.NewTagger(client, owner, repo, oldCp, newCp)
+//	if _, err := tagger.LoadSettings(); err != nil { ... }
+//	change, err := tagger.DetectVersionChange()
+//	// change is nil when there is no new version to tag
+//	name, err := tagger.RenderTag(change.NewVersion)
+//	err = tagger.CreateTag(push.TagContent{Tag: name, Version: change.NewVersion}, sha, author)
+type Tagger struct {
+	Client *github.Client
+	Owner  string
+	Repo   string
+	// OldContentProvider reads the repository before the change, nil if
+	// there is no previous commit. NewContentProvider reads it after.
+	OldContentProvider provider.ContentProvider
+	NewContentProvider provider.ContentProvider
+	// Settings are set by LoadSettings or by the caller.
+	Settings *settings.AtcSettings
+	// Signer signs annotated tags, they're unsigned if it's nil.
+	Signer tagsign.Signer
+}
+
+var (
+	ErrNoSettings = errors.New("tagger settings aren't loaded")
+	// ErrTagExists is returned by CreateTag for a tag that exists already
+	// when on_existing_tag is set.
+	ErrTagExists = errors.New("tag already exists")
+	// ErrTagOnCommit is returned by CreateTag for a tag that already points
+	// at the commit when only_if_no_existing_tag is set.
+	ErrTagOnCommit = errors.New("tag already points at the commit")
+)
+
+func NewTagger(client *github.Client, owner, repo string, oldContentProvider, newContentProvider provider.ContentProvider) *Tagger {
+	return &Tagger{
+		Client:             client,
+		Owner:              owner,
+		Repo:               repo,
+		OldContentProvider: oldContentProvider,
+		NewContentProvider: newContentProvider,
+	}
+}
+
+func (tagger *Tagger) fullName() string {
+	return tagger.Owner + "/" + tagger.Repo
+}
+
+// LoadSettings reads .atc.yaml with NewContentProvider, the defaults are used
+// when it's missing.
+func (tagger *Tagger) LoadSettings() (*settings.AtcSettings, error) {
+	atcSettings, err := settings.GetAtcSetting(tagger.NewContentProvider)
+	if err != nil {
+		return nil, err
+	}
+	tagger.Settings = atcSettings
+	return atcSettings, nil
+}
+
+// DetectVersionChange fetches the old and new versions and returns them if
+// the new one is tagged with the settings, or nil otherwise.
+func (tagger *Tagger) DetectVersionChange() (*VersionChange, error) {
+	if tagger.Settings == nil {
+		return nil, ErrNoSettings
+	}
+	return detectVersionChange(tagger.Settings, tagger.OldContentProvider, tagger.NewContentProvider, tagger.fullName())
+}
+
+// RenderTag renders the tag name of version with the template of the settings.
+func (tagger *Tagger) RenderTag(version string) (string, error) {
+	if tagger.Settings == nil {
+		return "", ErrNoSettings
+	}
+	caption, err := renderTemplate(tagger.Settings.Template, TagContent{
+		Version:    version,
+		PreRelease: tagger.Settings.PreRelease,
+		Repository: tagger.fullName(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("error in go templates: %v", err)
+	}
+	return caption, nil
+}
+
+// CreateTag creates the tag tagContent.Tag at sha with the tag type, ref
+// format, annotation template and tagger of the settings. author is the
+// tagger of an annotated tag unless tagger_name and tagger_email are set.
+// The other fields of tagContent are for the annotation template. With
+// only_if_no_existing_tag set, a tag already pointing at sha isn't created
+// again and ErrTagOnCommit is returned. With on_existing_tag set, an
+// existing tag isn't replaced and ErrTagExists is returned.
+func (tagger *Tagger) CreateTag(tagContent TagContent, sha string, author *github.CommitAuthor) error {
+	_, err := tagger.createTag(tagContent, sha, author)
+	return err
+}
+
+// createTag is CreateTag returning the tag object before it's signed, e.g.
+// to retry it.
+func (tagger *Tagger) createTag(tagContent TagContent, sha string, author *github.CommitAuthor) (*github.Tag, error) {
+	if tagger.Settings == nil {
+		return nil, ErrNoSettings
+	}
+	if tagContent.Repository == "" {
+		tagContent.Repository = tagger.fullName()
+	}
+	if tagContent.SHA == "" {
+		tagContent.SHA = sha
+	}
+	if tagger.Settings.OnlyIfNoExistingTag {
+		exists, err := gitutil.TagExistsOnCommit(tagger.Client, tagger.Owner, tagger.Repo, tagContent.Tag, sha, tagger.refFormat())
+		if err != nil {
+			logger.Errorf("check existing tag error for %q: %v", tagger.fullName(), err)
+		} else if exists {
+			return nil, fmt.Errorf("%w: %q", ErrTagOnCommit, tagContent.Tag)
+		}
+	}
+	if tagger.Settings.OnExistingTag != "" {
+		exists, err := gitutil.RefExists(tagger.Client, tagger.Owner, tagger.Repo, fmt.Sprintf(tagger.refFormat(), tagContent.Tag))
+		if err != nil {
+			logger.Errorf("check existing tag error for %q: %v", tagger.fullName(), err)
+		} else if exists {
+			return nil, fmt.Errorf("%w: %q", ErrTagExists, tagContent.Tag)
+		}
+	}
+	tag := tagger.tagObject(tagContent, sha, author)
+	unsignedTag := *tag
+	return &unsignedTag, addTag(tagger.Client, tagger.Owner, tagger.Repo, tagger.Settings.TagType, tagger.Signer,
+		tag, sha, tagger.refFormat())
+}
+
+// tagObject returns the annotated tag object of tagContent at sha.
+func (tagger *Tagger) tagObject(tagContent TagContent, sha string, author *github.CommitAuthor) *github.Tag {
+	if author == nil {
+		author = &github.CommitAuthor{}
+	}
+	if tagger.Settings.TaggerName != "" || tagger.Settings.TaggerEmail != "" {
+		// a copy, the author may be the one of a commit
+		author = &github.CommitAuthor{Name: author.Name, Email: author.Email, Date: author.Date}
+		if tagger.Settings.TaggerName != "" {
+			author.Name = &tagger.Settings.TaggerName
+		}
+		if tagger.Settings.TaggerEmail != "" {
+			author.Email = &tagger.Settings.TaggerEmail
+		}
+	}
+	tag := newTag(tagContent.Tag, sha, author)
+	if tagger.Settings.TagAnnotationTemplate != "" {
+		message := tagAnnotation(tagger.Settings.TagAnnotationTemplate, tagContent)
+		tag.Message = &message
+	} else if tagContent.Changelog != "" {
+		message := tagAnnotation("{{.Tag}}\n\n{{.Changelog}}", tagContent)
+		tag.Message = &message
+	}
+	return tag
+}
+
+// refFormat returns the tag ref format of the settings.
+func (tagger *Tagger) refFormat() string {
+	if tagger.Settings.TagRefFormat == "" {
+		return settings.DefaultTagRefFormat
+	}
+	return tagger.Settings.TagRefFormat
+}
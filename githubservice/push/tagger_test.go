@@ -0,0 +1,103 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v39/github"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+func TestTagger(t *testing.T) {
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+	var refBody map[string]interface{}
+	mockClientProviderPtr.OverrideResponseFn("ADD_REF", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		refBody = provider.GetBodyJson(req)
+		return defaultFn(req)
+	})
+	client := mockClientProviderPtr.Get("", context.Background())
+
+	tagger := NewTagger(client, "Codertocat", "Hello-World",
+		provider.MockFilesContentProvider{"pom.xml": "<project><version>1.0</version></project>"},
+		provider.MockFilesContentProvider{
+			".atc.yaml": "path: pom.xml\ntemplate: release-{{.Version}}{{if .PreRelease}}-rc{{end}}\nprerelease: true",
+			"pom.xml":   "<project><version>1.1</version></project>",
+		})
+
+	if _, err := tagger.DetectVersionChange(); err != ErrNoSettings {
+		t.Errorf("expected ErrNoSettings before LoadSettings, got %v", err)
+	}
+	if _, err := tagger.LoadSettings(); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	change, err := tagger.DetectVersionChange()
+	if err != nil || change == nil || *change != (VersionChange{OldVersion: "1.0", NewVersion: "1.1"}) {
+		t.Fatalf("wrong version change %v, %v", change, err)
+	}
+	name, err := tagger.RenderTag(change.NewVersion)
+	if err != nil || name != "release-1.1-rc" {
+		t.Errorf("expected tag %q, got %q, %v", "release-1.1-rc", name, err)
+	}
+	name, sha := "release-1.1-rc", "0000000000000000000000000000000000000000"
+	if err := tagger.CreateTag(TagContent{Tag: name, Version: "1.1"}, sha, &github.CommitAuthor{Name: github.String("Codertocat")}); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if refBody["ref"] != "refs/tags/"+name || refBody["sha"] != "940bd336248efae0f9ee5bc7b2d5c985887b16ac" {
+		t.Errorf("wrong ref %v", refBody)
+	}
+}
+
+func TestTaggerUnchangedVersion(t *testing.T) {
+	pom := provider.MockFilesContentProvider{".atc.yaml": "path: pom.xml", "pom.xml": "<project><version>1.0</version></project>"}
+	tagger := NewTagger(nil, "Codertocat", "Hello-World", pom, pom)
+	if _, err := tagger.LoadSettings(); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if change, err := tagger.DetectVersionChange(); change != nil || err != nil {
+		t.Errorf("expected no change, got %v, %v", change, err)
+	}
+}
+
+func TestTaggerCreateTagSettings(t *testing.T) {
+	mockClientProviderPtr := provider.DefaultMockClientProvider()
+	var tagBody map[string]interface{}
+	mockClientProviderPtr.OverrideResponseFn("ADD_TAG", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		tagBody = provider.GetBodyJson(req)
+		return defaultFn(req)
+	})
+	refs := `[]`
+	mockClientProviderPtr.OverrideResponseFn("GET_MATCHING_REFS", func(req *http.Request, defaultFn provider.RoundTripFunc) *http.Response {
+		return provider.NewTestResponse(200, refs)
+	})
+
+	tagger := NewTagger(mockClientProviderPtr.Get("", context.Background()), "Codertocat", "Hello-World", nil, nil)
+	tagger.Settings = &settings.AtcSettings{
+		TagAnnotationTemplate: "Release {{.Version}} of {{.Repository}}",
+		TaggerName:            "Release Bot",
+		TaggerEmail:           "release-bot@example.com",
+		OnExistingTag:         settings.OnExistingTagSkip,
+	}
+	sha := "0000000000000000000000000000000000000000"
+	if err := tagger.CreateTag(TagContent{Tag: "v1.1", Version: "1.1"}, sha, &github.CommitAuthor{Name: github.String("Codertocat")}); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if tagBody["message"] != "Release 1.1 of Codertocat/Hello-World" {
+		t.Errorf("wrong message %v", tagBody["message"])
+	}
+	if author := tagBody["tagger"].(map[string]interface{}); author["name"] != "Release Bot" || author["email"] != "release-bot@example.com" {
+		t.Errorf("wrong tagger %v", author)
+	}
+
+	tagBody = nil
+	refs = `[{"ref": "refs/tags/v1.1"}]`
+	if err := tagger.CreateTag(TagContent{Tag: "v1.1", Version: "1.1"}, sha, nil); !errors.Is(err, ErrTagExists) {
+		t.Errorf("expected ErrTagExists, got %v", err)
+	}
+	if tagBody != nil {
+		t.Errorf("existing tag was created again: %v", tagBody)
+	}
+}
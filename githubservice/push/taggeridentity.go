@@ -0,0 +1,73 @@
+package push
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/go-github/v39/github"
+
+	"github.com/smartforce-io/atc/githubservice/accesstoken"
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+// appBot caches the bot user of the app, it's the same for every
+// installation.
+var appBot struct {
+	sync.Mutex
+	author *github.CommitAuthor
+}
+
+// appBotAuthor returns the name and noreply email of the bot user of the
+// app, e.g. "atc[bot]" <41898282+atc[bot]@users.noreply.github.com>, the
+// identity of the commits the app makes itself.
+func appBotAuthor(ctx context.Context, clientProvider provider.ClientProvider, client *github.Client) (*github.CommitAuthor, error) {
+	appBot.Lock()
+	defer appBot.Unlock()
+	if appBot.author != nil {
+		return appBot.author, nil
+	}
+	app, err := accesstoken.GetApp(ctx, clientProvider)
+	if err != nil {
+		return nil, err
+	}
+	author, err := userAuthor(ctx, client, app.GetSlug()+"[bot]")
+	if err != nil {
+		return nil, fmt.Errorf("bot %w", err)
+	}
+	appBot.author = author
+	return appBot.author, nil
+}
+
+// userAuthor returns the login and noreply email of the GitHub user login,
+// the identity GitHub attributes to the user.
+func userAuthor(ctx context.Context, client *github.Client, login string) (*github.CommitAuthor, error) {
+	user, _, err := client.Users.Get(ctx, login)
+	if err != nil {
+		return nil, fmt.Errorf("user %q: %w", login, err)
+	}
+	email := fmt.Sprintf("%d+%s@users.noreply.github.com", user.GetID(), user.GetLogin())
+	return &github.CommitAuthor{Name: user.Login, Email: &email}, nil
+}
+
+// pushTagger returns the tagger of the tags of push: tagger_name and
+// tagger_email if they're set, else the name and email of the pusher, each
+// on its own so the email is never empty.
+func pushTagger(push *github.WebHookPayload, setting *settings.AtcSettings) *github.CommitAuthor {
+	if setting.TaggerName == "" && setting.TaggerEmail == "" {
+		return &github.CommitAuthor{
+			Name:  push.GetPusher().Name,
+			Email: push.GetPusher().Email,
+			Login: push.GetPusher().Login,
+		}
+	}
+	tagger := &github.CommitAuthor{Name: push.GetPusher().Name, Email: push.GetPusher().Email}
+	if setting.TaggerName != "" {
+		tagger.Name = &setting.TaggerName
+	}
+	if setting.TaggerEmail != "" {
+		tagger.Email = &setting.TaggerEmail
+	}
+	return tagger
+}
@@ -0,0 +1,101 @@
+package push
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+// sampleTagContent renders the templates of a validated config.
+var sampleTagContent = TagContent{
+	Version:    "1.2.3",
+	Tag:        "v1.2.3",
+	Repository: "owner/repo",
+	Actor:      "octocat",
+	SHA:        "0123456789abcdef0123456789abcdef01234567",
+	Branch:     "main",
+}
+
+// configField is a setting checked by ValidateConfig, key is its name in
+// the diagnostics.
+type configField struct {
+	key, value string
+}
+
+// ValidateConfig checks the content of a .atc.yaml without a repository:
+// the settings are validated like on a push, the templates are rendered
+// with a sample version and the regexes compiled.
+func ValidateConfig(content []byte) []settings.Diagnostic {
+	setting, diagnostics := settings.Validate(content)
+	if setting == nil {
+		return diagnostics
+	}
+	errorAt := func(key, format string, args ...interface{}) {
+		diagnostics = append(diagnostics, settings.Diagnostic{
+			Line:     settings.KeyLine(content, key),
+			Severity: settings.SeverityError,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	branches := make([]string, 0, len(setting.Branches))
+	for name := range setting.Branches {
+		branches = append(branches, name)
+	}
+	sort.Strings(branches)
+	channels := make([]string, 0, len(setting.Channels))
+	for name := range setting.Channels {
+		channels = append(channels, name)
+	}
+	sort.Strings(channels)
+
+	templates := []configField{
+		{"template", setting.Template},
+		{"success_comment_template", setting.SuccessCommentTemplate},
+		{"error_comment_template", setting.ErrorCommentTemplate},
+		{"tag_annotation_template", setting.TagAnnotationTemplate},
+		{"tag_body", setting.TagBody},
+		{"wiki_page_template", setting.WikiPageTemplate},
+	}
+	for i, target := range setting.Targets {
+		templates = append(templates, configField{fmt.Sprintf("targets[%d].template", i), target.Template})
+	}
+	for i, target := range setting.CrossRepoTagging {
+		templates = append(templates, configField{fmt.Sprintf("cross_repo_tagging[%d].tag_template", i), target.TagTemplate})
+	}
+	for i, environment := range setting.PropagateToEnvironments {
+		templates = append(templates, configField{fmt.Sprintf("propagate_to_environments[%d].template", i), environment.Template})
+	}
+	for _, name := range branches {
+		templates = append(templates, configField{fmt.Sprintf("branches[%q].template", name), setting.Branches[name].Template})
+	}
+	for _, name := range channels {
+		templates = append(templates, configField{fmt.Sprintf("channels[%q].template", name), setting.Channels[name].Template})
+	}
+	for _, field := range templates {
+		if field.value == "" {
+			continue
+		}
+		if _, err := renderTemplate(field.value, sampleTagContent); err != nil {
+			errorAt(field.key, "%s can't be rendered: %v", field.key, err)
+		}
+	}
+
+	regexes := []configField{{"regexstr", setting.RegexStr}}
+	for _, name := range branches {
+		regexes = append(regexes, configField{fmt.Sprintf("branches[%q].regexstr", name), setting.Branches[name].RegexStr})
+	}
+	for _, field := range regexes {
+		if field.value == "" {
+			continue
+		}
+		if regex, err := regexp.Compile(field.value); err != nil {
+			errorAt(field.key, "%s isn't a valid regex: %v", field.key, err)
+		} else if regex.NumSubexp() == 0 {
+			errorAt(field.key, "%s doesn't have a group for the version", field.key)
+		}
+	}
+	return diagnostics
+}
@@ -0,0 +1,43 @@
+package push
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+func TestValidateConfig(t *testing.T) {
+	var tests = []struct {
+		content  string
+		expected []settings.Diagnostic
+	}{
+		{"path: VERSION\nregexstr: \"v(.+)\"\ntemplate: \"{{.Version}}-{{shortSHA .SHA}}\"\n", nil},
+		{"path: pom.xml\nsuccess_comment_template: \"{{.Tag\"\n", []settings.Diagnostic{{
+			Line: 2, Severity: settings.SeverityError,
+			Message: `success_comment_template can't be rendered: template: template tagContent:1: unclosed action`,
+		}}},
+		{"path: pom.xml\ntemplate: \"{{.Version}}{{.Revision}}\"\n", []settings.Diagnostic{{
+			Line: 2, Severity: settings.SeverityError,
+			Message: `template can't be rendered: template: template tagContent:1:14: executing "template tagContent" at <.Revision>: can't evaluate field Revision in type push.TagContent`,
+		}}},
+		{"path: VERSION\nregexstr: \"v(.+\"\n", []settings.Diagnostic{{
+			Line: 2, Severity: settings.SeverityError,
+			Message: "regexstr isn't a valid regex: error parsing regexp: missing closing ): `v(.+`",
+		}}},
+		{"path: VERSION\nbranches:\n  \"release/*\":\n    regexstr: \"v.+\"\n", []settings.Diagnostic{{
+			Line: 2, Severity: settings.SeverityError,
+			Message: `branches["release/*"].regexstr doesn't have a group for the version`,
+		}}},
+		{"targets:\n  - path: a/pom.xml\n    template: \"{{.Version}}{{upper}}\"\n", []settings.Diagnostic{{
+			Line: 1, Severity: settings.SeverityError,
+			Message: `targets[0].template can't be rendered: template: template tagContent:1:14: executing "template tagContent" at <upper>: wrong number of args for upper: want 1 got 0`,
+		}}},
+	}
+	for _, test := range tests {
+		diagnostics := ValidateConfig([]byte(test.content))
+		if !reflect.DeepEqual(diagnostics, test.expected) {
+			t.Errorf("content %q:\nexpected %+v\ngot      %+v", test.content, test.expected, diagnostics)
+		}
+	}
+}
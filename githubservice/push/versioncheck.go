@@ -0,0 +1,38 @@
+package push
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/smartforce-io/atc/githubservice/semver"
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+var errVersionDowngrade = errors.New("version downgrade")
+
+// isVersionBump reports whether newVersion after oldVersion is tagged with
+// the version_check of setting. With "semver" a lower version is
+// errVersionDowngrade and a version of the same precedence isn't tagged.
+func isVersionBump(setting *settings.AtcSettings, oldVersion, newVersion string) (bool, error) {
+	if newVersion == oldVersion {
+		return false, nil
+	}
+	if setting.VersionCheck != settings.VersionCheckSemver || oldVersion == "" {
+		return true, nil
+	}
+	newV, err := semver.Parse(newVersion)
+	if err != nil {
+		return false, err
+	}
+	oldV, err := semver.Parse(oldVersion)
+	if err != nil {
+		return false, err
+	}
+	switch semver.Compare(newV, oldV) {
+	case 1:
+		return true, nil
+	case -1:
+		return false, fmt.Errorf("%w from %q to %q", errVersionDowngrade, oldVersion, newVersion)
+	}
+	return false, nil
+}
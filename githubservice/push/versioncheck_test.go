@@ -0,0 +1,37 @@
+package push
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/semver"
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+func TestIsVersionBump(t *testing.T) {
+	var tests = []struct {
+		check      string
+		oldVersion string
+		newVersion string
+		expected   bool
+		err        error
+	}{
+		{"", "1.2.0", "1.1.0", true, nil},
+		{"", "1.2.0", "1.2.0", false, nil},
+		{settings.VersionCheckChanged, "1.2.0", "1.1.0", true, nil},
+		{settings.VersionCheckSemver, "1.2.0", "1.3.0", true, nil},
+		{settings.VersionCheckSemver, "", "1.3.0", true, nil},
+		{settings.VersionCheckSemver, "1.2.0-rc.1", "1.2.0", true, nil},
+		{settings.VersionCheckSemver, "1.2.0", "1.1.0", false, errVersionDowngrade},
+		{settings.VersionCheckSemver, "1.2.0", "1.2.0-rc.1", false, errVersionDowngrade},
+		{settings.VersionCheckSemver, "1.2", "1.2.0", false, nil},
+		{settings.VersionCheckSemver, "1.2.0", "next", false, semver.ErrNotSemver},
+	}
+	for _, test := range tests {
+		bump, err := isVersionBump(&settings.AtcSettings{VersionCheck: test.check}, test.oldVersion, test.newVersion)
+		if bump != test.expected || !errors.Is(err, test.err) {
+			t.Errorf("%q from %q to %q: expected %v, %v, got %v, %v", test.check, test.oldVersion, test.newVersion,
+				test.expected, test.err, bump, err)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package push
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+const wikiPageTitleTemplate = "Release {{.Version}}"
+
+// wikiURL is the git remote of the repository wiki. GitHub has no API for
+// wikis, so the page is pushed with git.
+var wikiURL = func(owner, repo string) string {
+	return fmt.Sprintf("https://github.com/%s/%s.wiki.git", owner, repo)
+}
+
+// wikiPageFile returns the file of the wiki page title, GitHub shows the
+// dashes of the file name as spaces.
+func wikiPageFile(title string) string {
+	return strings.ReplaceAll(title, " ", "-") + ".md"
+}
+
+// updateWikiPage creates or updates the "Release <version>" page in the
+// wiki of owner/repo with the rendered WikiPageTemplate. The wiki needs at
+// least one page, GitHub doesn't create its repository before that.
+func updateWikiPage(owner, repo, token string, setting *settings.AtcSettings, tagContent TagContent) error {
+	title, err := renderTemplate(wikiPageTitleTemplate, tagContent)
+	if err != nil {
+		return err
+	}
+	body := renderComment(setting.WikiPageTemplate,
+		fmt.Sprintf("Version %s of %s is tagged %q.", tagContent.Version, tagContent.Repository, tagContent.Tag), tagContent)
+
+	dir, err := os.MkdirTemp("", "atc-wiki-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	// the token goes in the environment to keep it out of the command lines
+	auth := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + token))
+	env := append(os.Environ(),
+		"GIT_CONFIG_COUNT=1",
+		"GIT_CONFIG_KEY_0=http.extraHeader",
+		"GIT_CONFIG_VALUE_0=Authorization: basic "+auth,
+	)
+	git := func(arg ...string) error {
+		cmd := execCommand("git", arg...)
+		cmd.Dir = dir
+		cmd.Env = env
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("git %s: %w: %s", strings.Join(arg, " "), err, out)
+		}
+		return nil
+	}
+
+	if err := git("clone", "--depth", "1", wikiURL(owner, repo), "."); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, wikiPageFile(title)), []byte(body), 0644); err != nil {
+		return err
+	}
+	if err := git("add", "--all"); err != nil {
+		return err
+	}
+	err = git("diff", "--cached", "--quiet")
+	if err == nil {
+		return nil // the page is up to date
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return err
+	}
+	if err := git("-c", "user.name="+settings.TaggerName, "-c", "user.email=atc@users.noreply.github.com",
+		"commit", "-m", title); err != nil {
+		return err
+	}
+	return git("push", "origin", "HEAD")
+}
@@ -0,0 +1,48 @@
+package push
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+func gitRun(t *testing.T, dir string, arg ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, arg...)...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v: %s", arg, err, out)
+	}
+	return string(out)
+}
+
+func TestUpdateWikiPage(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed")
+	}
+	defer func(f func(owner, repo string) string) { wikiURL = f }(wikiURL)
+	remote := filepath.Join(t.TempDir(), "Hello-World.wiki.git")
+	work := t.TempDir()
+	gitRun(t, work, "init", "--bare", remote)
+	gitRun(t, work, "clone", remote, "home")
+	gitRun(t, filepath.Join(work, "home"), "commit", "--allow-empty", "-m", "Home")
+	gitRun(t, filepath.Join(work, "home"), "push", "origin", "HEAD")
+	wikiURL = func(owner, repo string) string { return remote }
+
+	setting := &settings.AtcSettings{WikiPageTemplate: "# {{.Tag}}\n\nPre-release: {{.PreRelease}}"}
+	tagContent := TagContent{Version: "1.2.3", Tag: "v1.2.3", Repository: "Codertocat/Hello-World"}
+	for i := 0; i < 2; i++ { // the second update has nothing to commit
+		if err := updateWikiPage("Codertocat", "Hello-World", "ghs_token", setting, tagContent); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if page := gitRun(t, remote, "show", "HEAD:Release-1.2.3.md"); page != "# v1.2.3\n\nPre-release: false" {
+		t.Errorf("wrong page %q", page)
+	}
+	if log := gitRun(t, remote, "log", "--format=%s %an"); log != "Release 1.2.3 atc[bot]\nHome test\n" {
+		t.Errorf("wrong log %q", log)
+	}
+}
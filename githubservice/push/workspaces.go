@@ -0,0 +1,139 @@
+package push
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+	"github.com/smartforce-io/atc/logger"
+)
+
+// maxWorkspacePackages limits how many workspace packages are tagged, each
+// one reads its package.json before and after the push.
+const maxWorkspacePackages = 50
+
+// workspacesManifest is the part of a root package.json or lerna.json
+// listing the package directories. Workspaces is either a list of patterns
+// or, for yarn, an object with the patterns in "packages".
+type workspacesManifest struct {
+	Workspaces json.RawMessage `json:"workspaces"`
+	Packages   []string        `json:"packages"`
+}
+
+type workspacePackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// workspacePatterns returns the package directory patterns of the root
+// package.json, or else of lerna.json.
+func workspacePatterns(cp provider.ContentProvider) ([]string, error) {
+	content, err := cp.GetContents("package.json")
+	if err != nil {
+		return nil, fmt.Errorf("can't read package.json: %w", err)
+	}
+	manifest := workspacesManifest{}
+	if err := json.Unmarshal([]byte(content), &manifest); err != nil {
+		return nil, fmt.Errorf("can't parse package.json: %v", err)
+	}
+	if len(manifest.Workspaces) > 0 {
+		var patterns []string
+		if err := json.Unmarshal(manifest.Workspaces, &patterns); err == nil {
+			return patterns, nil
+		}
+		yarnWorkspaces := workspacesManifest{}
+		if err := json.Unmarshal(manifest.Workspaces, &yarnWorkspaces); err != nil {
+			return nil, fmt.Errorf("package.json workspaces isn't a list or an object with packages")
+		}
+		return yarnWorkspaces.Packages, nil
+	}
+
+	content, err = cp.GetContents("lerna.json")
+	if err != nil {
+		return nil, fmt.Errorf("package.json has no workspaces and lerna.json can't be read: %w", err)
+	}
+	lerna := workspacesManifest{}
+	if err := json.Unmarshal([]byte(content), &lerna); err != nil {
+		return nil, fmt.Errorf("can't parse lerna.json: %v", err)
+	}
+	if len(lerna.Packages) == 0 {
+		// lerna's default
+		return []string{"packages/*"}, nil
+	}
+	return lerna.Packages, nil
+}
+
+// npmWorkspaceTargets returns a target for the package.json of every
+// workspace package, its tags are "<name>@<version>". Patterns starting
+// with "!" exclude packages and node_modules is never searched.
+func npmWorkspaceTargets(cp provider.ContentProvider) ([]settings.Target, error) {
+	treeProvider, ok := cp.(provider.TreeProvider)
+	if !ok {
+		return nil, provider.ErrNoTreeProvider
+	}
+	patterns, err := workspacePatterns(cp)
+	if err != nil {
+		return nil, err
+	}
+	files, err := treeProvider.ListFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	targets := []settings.Target{}
+	for _, file := range files {
+		if path.Base(file) != "package.json" || file == "package.json" || strings.Contains("/"+file, "/node_modules/") {
+			continue
+		}
+		included, err := matchWorkspace(patterns, path.Dir(file))
+		if err != nil {
+			return nil, err
+		}
+		if !included {
+			continue
+		}
+		content, err := cp.GetContents(file)
+		if err != nil {
+			return nil, err
+		}
+		pkg := workspacePackage{}
+		if err := json.Unmarshal([]byte(content), &pkg); err != nil {
+			return nil, fmt.Errorf("can't parse %s: %v", file, err)
+		}
+		if pkg.Name == "" {
+			logger.Debugf("workspace package %s has no name, skipped", file)
+			continue
+		}
+		if len(targets) == maxWorkspacePackages {
+			logger.Warnf("more than %d workspace packages, the others aren't tagged", maxWorkspacePackages)
+			break
+		}
+		targets = append(targets, settings.Target{
+			Path:      file,
+			Template:  "{{.Version}}",
+			TagPrefix: pkg.Name + "@",
+		})
+	}
+	return targets, nil
+}
+
+// matchWorkspace reports whether dir matches one of patterns and none of
+// the "!" patterns after it.
+func matchWorkspace(patterns []string, dir string) (bool, error) {
+	included := false
+	for _, pattern := range patterns {
+		exclude := strings.HasPrefix(pattern, "!")
+		pattern = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(pattern, "!"), "./"), "/")
+		matched, err := matchGlob(pattern, dir)
+		if err != nil {
+			return false, fmt.Errorf("wrong workspace pattern %q: %w", pattern, err)
+		}
+		if matched {
+			included = !exclude
+		}
+	}
+	return included, nil
+}
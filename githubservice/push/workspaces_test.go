@@ -0,0 +1,72 @@
+package push
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+func TestNPMWorkspaceTargets(t *testing.T) {
+	pkg := func(name string) string {
+		return fmt.Sprintf(`{"name": %q, "version": "1.2.3"}`, name)
+	}
+	packages := map[string]string{
+		"packages/core/package.json":                  pkg("@acme/core"),
+		"packages/cli/package.json":                   pkg("@acme/cli"),
+		"packages/cli/node_modules/left/package.json": pkg("left"),
+		"packages/internal/package.json":              pkg("@acme/internal"),
+		"apps/web/package.json":                       pkg("web"),
+		"apps/docs/package.json":                      `{"private": true}`,
+		"tools/package.json":                          pkg("tools"),
+		"packages/core/README.md":                     "# core",
+	}
+	files := func(root map[string]string) provider.MockFilesContentProvider {
+		cp := provider.MockFilesContentProvider{}
+		for file, content := range packages {
+			cp[file] = content
+		}
+		for file, content := range root {
+			cp[file] = content
+		}
+		return cp
+	}
+	target := func(path, name string) settings.Target {
+		return settings.Target{Path: path, Template: "{{.Version}}", TagPrefix: name + "@"}
+	}
+
+	var tests = []struct {
+		name     string
+		cp       provider.ContentProvider
+		expected []settings.Target
+	}{
+		{"npm", files(map[string]string{"package.json": `{"private": true, "workspaces": ["packages/*", "!packages/internal", "./apps/*/"]}`}),
+			[]settings.Target{target("apps/web/package.json", "web"), target("packages/cli/package.json", "@acme/cli"), target("packages/core/package.json", "@acme/core")}},
+		{"yarn", files(map[string]string{"package.json": `{"workspaces": {"packages": ["tools"]}}`}),
+			[]settings.Target{target("tools/package.json", "tools")}},
+		{"lerna", files(map[string]string{"package.json": `{"private": true}`, "lerna.json": `{"packages": ["apps/**"]}`}),
+			[]settings.Target{target("apps/web/package.json", "web")}},
+		{"lerna default", files(map[string]string{"package.json": `{}`, "lerna.json": `{"version": "independent"}`}),
+			[]settings.Target{target("packages/cli/package.json", "@acme/cli"), target("packages/core/package.json", "@acme/core"), target("packages/internal/package.json", "@acme/internal")}},
+	}
+	for _, test := range tests {
+		targets, err := npmWorkspaceTargets(test.cp)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(targets, test.expected) {
+			t.Errorf("%s: expected %+v, got %+v", test.name, test.expected, targets)
+		}
+	}
+
+	if _, err := npmWorkspaceTargets(files(map[string]string{"package.json": `{}`})); !errors.Is(err, provider.ErrHttpStatusCode) {
+		t.Errorf("expected an error without workspaces and lerna.json, got %v", err)
+	}
+	if _, err := npmWorkspaceTargets(&provider.MockContentProvider{}); !errors.Is(err, provider.ErrNoTreeProvider) {
+		t.Errorf("expected ErrNoTreeProvider, got %v", err)
+	}
+}
@@ -0,0 +1,229 @@
+// Package retryqueue retries failed operations in the background with an
+// exponential backoff, optionally persisted to a file so a restart doesn't
+// lose them.
+package retryqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/logger"
+)
+
+const (
+	DefaultMaxAttempts = 5
+	DefaultBaseDelay   = 30 * time.Second
+	DefaultMaxDelay    = 30 * time.Minute
+)
+
+// Job is a queued operation. Payload is the JSON of what the handler needs.
+type Job struct {
+	ID          string          `json:"id"`
+	Payload     json.RawMessage `json:"payload"`
+	Attempts    int             `json:"attempts"`
+	NextAttempt time.Time       `json:"next_attempt"`
+	LastError   string          `json:"last_error,omitempty"`
+}
+
+// Handler runs a job, an error retries it later.
+type Handler func(ctx context.Context, job Job) error
+
+// GiveUpHandler is called with the last error when a job failed
+// MaxAttempts times.
+type GiveUpHandler func(ctx context.Context, job Job, err error)
+
+// Queue runs its jobs with Run, create it with New. The exported fields
+// can be changed before Run.
+type Queue struct {
+	// MaxAttempts is how many times the handler runs a job.
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	// Path is the JSON file the jobs are persisted to, "" keeps them only in
+	// memory.
+	Path     string
+	Handler  Handler
+	OnGiveUp GiveUpHandler
+
+	mu   sync.Mutex
+	jobs []Job
+	// running are the jobs runDue took out of jobs, they're still saved
+	running []Job
+	wake    chan struct{}
+	now     func() time.Time
+}
+
+// New returns a queue with the default limits, loading the jobs persisted
+// to path if it isn't empty.
+func New(path string, handler Handler, onGiveUp GiveUpHandler) (*Queue, error) {
+	queue := &Queue{
+		MaxAttempts: DefaultMaxAttempts,
+		BaseDelay:   DefaultBaseDelay,
+		MaxDelay:    DefaultMaxDelay,
+		Path:        path,
+		Handler:     handler,
+		OnGiveUp:    onGiveUp,
+		wake:        make(chan struct{}, 1),
+		now:         time.Now,
+	}
+	if path == "" {
+		return queue, nil
+	}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return queue, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, &queue.jobs); err != nil {
+		return nil, err
+	}
+	return queue, nil
+}
+
+// Add queues payload to run after the first backoff delay.
+func (queue *Queue) Add(id string, payload interface{}) error {
+	return queue.AddAt(id, payload, queue.now().Add(provider.Backoff(0, queue.BaseDelay, queue.MaxDelay)))
+}
+
+// AddAt queues payload to run at at, e.g. an operation that has to wait.
+// Failed runs are retried with the backoff delays like the jobs of Add.
+func (queue *Queue) AddAt(id string, payload interface{}, at time.Time) error {
+	content, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	queue.mu.Lock()
+	queue.jobs = append(queue.jobs, Job{
+		ID:          id,
+		Payload:     content,
+		NextAttempt: at,
+	})
+	err = queue.save()
+	queue.mu.Unlock()
+
+	select {
+	case queue.wake <- struct{}{}:
+	default:
+	}
+	return err
+}
+
+// Len returns the number of queued jobs.
+func (queue *Queue) Len() int {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	return len(queue.jobs)
+}
+
+// Run runs the due jobs until ctx is done.
+func (queue *Queue) Run(ctx context.Context) {
+	for {
+		wait := queue.runDue(ctx)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-queue.wake:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// idleWait is how long Run waits when the queue is empty, Add wakes it up.
+const idleWait = time.Hour
+
+// runDue runs the jobs due now and returns the wait until the next one.
+func (queue *Queue) runDue(ctx context.Context) time.Duration {
+	queue.mu.Lock()
+	var due []Job
+	pending := queue.jobs[:0]
+	for _, job := range queue.jobs {
+		if !job.NextAttempt.After(queue.now()) {
+			due = append(due, job)
+		} else {
+			pending = append(pending, job)
+		}
+	}
+	queue.jobs = pending
+	queue.running = due
+	queue.mu.Unlock()
+
+	for i, job := range due {
+		err := queue.Handler(ctx, job)
+		queue.mu.Lock()
+		queue.running = due[i+1:]
+		queue.mu.Unlock()
+		if err == nil {
+			logger.Infof("retried job %q succeeded", job.ID)
+			continue
+		}
+		job.Attempts++
+		job.LastError = err.Error()
+		if job.Attempts >= queue.MaxAttempts {
+			logger.Errorf("retried job %q failed %d times, giving up: %v", job.ID, job.Attempts, err)
+			if queue.OnGiveUp != nil {
+				queue.OnGiveUp(ctx, job, err)
+			}
+			continue
+		}
+		job.NextAttempt = queue.now().Add(provider.Backoff(job.Attempts, queue.BaseDelay, queue.MaxDelay))
+		logger.Warnf("retried job %q failed, attempt %d at %s: %v", job.ID, job.Attempts+1, job.NextAttempt.Format(time.RFC3339), err)
+		queue.mu.Lock()
+		queue.jobs = append(queue.jobs, job)
+		queue.mu.Unlock()
+	}
+
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	queue.running = nil
+	if len(due) > 0 {
+		if err := queue.save(); err != nil {
+			logger.Errorf("retry queue save error: %v", err)
+		}
+	}
+	wait := idleWait
+	for _, job := range queue.jobs {
+		if d := job.NextAttempt.Sub(queue.now()); d < wait {
+			wait = d
+		}
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	return wait
+}
+
+// save writes the jobs to Path, the caller holds mu.
+func (queue *Queue) save() error {
+	if queue.Path == "" {
+		return nil
+	}
+	jobs := append(append([]Job{}, queue.running...), queue.jobs...)
+	content, err := json.Marshal(jobs)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(queue.Path), filepath.Base(queue.Path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), queue.Path)
+}
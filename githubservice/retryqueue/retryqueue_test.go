@@ -0,0 +1,89 @@
+package retryqueue
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestQueue(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	runs := map[string]int{}
+	var gaveUp []string
+	queue, err := New("", func(ctx context.Context, job Job) error {
+		runs[job.ID]++
+		if job.ID == "flaky" && runs[job.ID] == 2 {
+			return nil
+		}
+		return errors.New("502 Bad Gateway")
+	}, func(ctx context.Context, job Job, err error) {
+		gaveUp = append(gaveUp, job.ID)
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	queue.MaxAttempts = 3
+	queue.now = func() time.Time { return now }
+
+	queue.Add("flaky", "payload")
+	queue.Add("broken", "payload")
+	if wait := queue.runDue(context.Background()); wait <= 0 || wait > queue.BaseDelay || len(runs) != 0 {
+		t.Errorf("jobs ran before the first delay: wait %v, runs %v", wait, runs)
+	}
+	for i := 0; i < 5; i++ {
+		now = now.Add(queue.MaxDelay)
+		queue.runDue(context.Background())
+	}
+
+	if runs["flaky"] != 2 || runs["broken"] != 3 {
+		t.Errorf("wrong runs %v", runs)
+	}
+	if len(gaveUp) != 1 || gaveUp[0] != "broken" || queue.Len() != 0 {
+		t.Errorf("wrong jobs given up %v, %d left", gaveUp, queue.Len())
+	}
+}
+
+func TestQueuePersisted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.json")
+	handler := func(ctx context.Context, job Job) error { return errors.New("timeout") }
+	queue, err := New(path, handler, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if err := queue.Add("tag", map[string]string{"tag": "v5"}); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	restarted, err := New(path, handler, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if restarted.Len() != 1 || restarted.jobs[0].ID != "tag" || string(restarted.jobs[0].Payload) != `{"tag":"v5"}` {
+		t.Errorf("wrong jobs after restart %+v", restarted.jobs)
+	}
+}
+
+func TestQueueAddAt(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	runs := 0
+	queue, err := New("", func(ctx context.Context, job Job) error {
+		runs++
+		return nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	queue.now = func() time.Time { return now }
+
+	queue.AddAt("environment", "payload", now.Add(time.Hour))
+	if wait := queue.runDue(context.Background()); wait != time.Hour || runs != 0 {
+		t.Errorf("job ran before its time: wait %v, runs %d", wait, runs)
+	}
+	now = now.Add(time.Hour)
+	queue.runDue(context.Background())
+	if runs != 1 || queue.Len() != 0 {
+		t.Errorf("expected one run and an empty queue, got %d runs, %d left", runs, queue.Len())
+	}
+}
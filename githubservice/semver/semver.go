@@ -0,0 +1,127 @@
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var ErrNotSemver = errors.New("version isn't semver")
+
+// versionRegex accepts "1", "1.2" and "1.2.3" with an optional "v" prefix,
+// pre-release and build metadata.
+var versionRegex = regexp.MustCompile(`^[vV]?(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:-([0-9A-Za-z.-]+))?(?:\+([0-9A-Za-z.-]+))?$`)
+
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	PreRelease string
+	Build      string
+}
+
+// Parse reads a semantic version. Missing minor and patch numbers are 0.
+func Parse(version string) (Version, error) {
+	res := versionRegex.FindStringSubmatch(strings.TrimSpace(version))
+	if res == nil {
+		return Version{}, fmt.Errorf("%w: %q", ErrNotSemver, version)
+	}
+	v := Version{PreRelease: res[4], Build: res[5]}
+	numbers := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, number := range res[1:4] {
+		if number == "" {
+			continue
+		}
+		n, err := strconv.Atoi(number)
+		if err != nil {
+			return Version{}, fmt.Errorf("%w: %q", ErrNotSemver, version)
+		}
+		*numbers[i] = n
+	}
+	return v, nil
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.PreRelease != "" {
+		s += "-" + v.PreRelease
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// BumpMajor returns the next major version, e.g. 2.0.0 for 1.4.2-rc1.
+func (v Version) BumpMajor() Version {
+	return Version{Major: v.Major + 1}
+}
+
+// Normalize returns version as "major.minor.patch" without leading zeros in
+// the numbers, e.g. "v1.2.0-rc.01" for "v01.2-rc.01". The "v" prefix,
+// pre-release and build metadata are kept. Other versions are returned as is.
+func Normalize(version string) string {
+	v, err := Parse(version)
+	if err != nil {
+		return version
+	}
+	prefix := ""
+	if trimmed := strings.TrimSpace(version); trimmed[0] == 'v' || trimmed[0] == 'V' {
+		prefix = trimmed[:1]
+	}
+	return prefix + v.String()
+}
+
+// Compare returns -1, 0 or 1 when a has a lower, equal or higher precedence
+// than b. Build metadata is ignored and a pre-release is lower than its
+// release, e.g. 1.0.0-rc.1 < 1.0.0.
+func Compare(a, b Version) int {
+	for _, d := range []int{a.Major - b.Major, a.Minor - b.Minor, a.Patch - b.Patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+	switch {
+	case a.PreRelease == b.PreRelease:
+		return 0
+	case a.PreRelease == "":
+		return 1
+	case b.PreRelease == "":
+		return -1
+	}
+	aIds, bIds := strings.Split(a.PreRelease, "."), strings.Split(b.PreRelease, ".")
+	for i := 0; i < len(aIds) && i < len(bIds); i++ {
+		if c := compareIdentifier(aIds[i], bIds[i]); c != 0 {
+			return c
+		}
+	}
+	return sign(len(aIds) - len(bIds))
+}
+
+// compareIdentifier compares pre-release identifiers, numeric identifiers
+// are lower than alphanumeric ones.
+func compareIdentifier(a, b string) int {
+	aNum, aErr := strconv.Atoi(a)
+	bNum, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return sign(aNum - bNum)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,88 @@
+package semver
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	var tests = []struct {
+		version  string
+		expected Version
+		str      string
+	}{
+		{"1.2.3", Version{1, 2, 3, "", ""}, "1.2.3"},
+		{"v2.0", Version{2, 0, 0, "", ""}, "2.0.0"},
+		{"7", Version{7, 0, 0, "", ""}, "7.0.0"},
+		{"1.02.003", Version{1, 2, 3, "", ""}, "1.2.3"},
+		{"1.0.0-rc.1+build.5", Version{1, 0, 0, "rc.1", "build.5"}, "1.0.0-rc.1+build.5"},
+	}
+	for _, test := range tests {
+		v, err := Parse(test.version)
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", test.version, err)
+		}
+		if v != test.expected {
+			t.Errorf("%q: expected %+v, got %+v", test.version, test.expected, v)
+		}
+		if v.String() != test.str {
+			t.Errorf("%q: expected %q, got %q", test.version, test.str, v.String())
+		}
+	}
+
+	for _, version := range []string{"", "1.2.3.4", "release-1", "1.x"} {
+		if _, err := Parse(version); !errors.Is(err, ErrNotSemver) {
+			t.Errorf("%q: expected %v, got %v", version, ErrNotSemver, err)
+		}
+	}
+}
+
+func TestBumpMajor(t *testing.T) {
+	v, _ := Parse("1.4.2-rc1+b7")
+	if bumped := v.BumpMajor().String(); bumped != "2.0.0" {
+		t.Errorf("expected %q, got %q", "2.0.0", bumped)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	var tests = []struct {
+		version  string
+		expected string
+	}{
+		{"1.02.003", "1.2.3"},
+		{"1.2", "1.2.0"},
+		{"v01.2-rc.01", "v1.2.0-rc.01"},
+		{"2.0.0+build.7", "2.0.0+build.7"},
+		{"release-1", "release-1"},
+		{"", ""},
+	}
+	for _, test := range tests {
+		if normalized := Normalize(test.version); normalized != test.expected {
+			t.Errorf("%q: expected %q, got %q", test.version, test.expected, normalized)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	var tests = []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"1.2.3", "v1.2.3+build.7", 0},
+		{"1.10.0", "1.9.9", 1},
+		{"1.9.9", "2", -1},
+		{"1.0.0-rc.1", "1.0.0", -1},
+		{"1.0.0-rc.2", "1.0.0-rc.10", -1},
+		{"1.0.0-alpha", "1.0.0-1", 1},
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"1.0.0-beta", "1.0.0-alpha.9", 1},
+	}
+	for _, test := range tests {
+		a, _ := Parse(test.a)
+		b, _ := Parse(test.b)
+		if c := Compare(a, b); c != test.expected {
+			t.Errorf("Compare(%q, %q): expected %d, got %d", test.a, test.b, test.expected, c)
+		}
+	}
+}
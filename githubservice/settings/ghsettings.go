@@ -2,10 +2,15 @@ package settings
 
 import (
 	"errors"
-	"log"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/smartforce-io/atc/githubservice/provider"
+	"github.com/smartforce-io/atc/logger"
 
 	"gopkg.in/yaml.v2"
 )
@@ -14,8 +19,52 @@ const (
 	BehaviorBefore = "before"
 	BehaviorAfter  = "after"
 	pathPrefix     = "/"
+
+	VersionFieldVersion     = "version"
+	VersionFieldVersionName = "versionName"
+	VersionFieldVersionCode = "versionCode"
+
+	MergeStrategyMerge  = "merge"
+	MergeStrategySquash = "squash"
+	MergeStrategyRebase = "rebase"
+
+	VersionCheckChanged = "changed"
+	VersionCheckSemver  = "semver"
+
+	OnExistingTagSkip    = "skip"
+	OnExistingTagComment = "comment"
+	OnExistingTagError   = "error"
+
+	TagTypeAnnotated   = "annotated"
+	TagTypeLightweight = "lightweight"
+
+	CommentsOff        = "off"
+	CommentsErrorsOnly = "errors_only"
+	CommentsAlways     = "always"
+
+	TriggerPush    = "push"
+	TriggerPRMerge = "pr_merge"
+
+	NotificationTypeSlack   = "slack"
+	NotificationTypeDiscord = "discord"
+	NotificationTypeWebhook = "webhook"
+
+	DefaultMaxVersionLength = 50
+
+	DefaultTagRefFormat = "refs/tags/%s"
+
+	// TaggerName is the pusher name of pushes made by the ATC app itself,
+	// e.g. when propagate_to_environments moves a branch.
+	TaggerName = "atc[bot]"
 )
 
+// DefaultBotNames are the pushers ignored when BotNames isn't set.
+var DefaultBotNames = []string{"github-actions[bot]", "dependabot[bot]", TaggerName}
+
+// githubLoginRegex follows the GitHub username rules: up to 39 letters,
+// digits and single hyphens, not at the start or the end.
+var githubLoginRegex = regexp.MustCompile(`^[A-Za-z0-9](?:-?[A-Za-z0-9]){0,38}$`)
+
 var unmarshal = func(content []byte, atcSettingsPtr *AtcSettings) error {
 	return yaml.Unmarshal([]byte(content), atcSettingsPtr)
 }
@@ -26,6 +75,310 @@ type AtcSettings struct {
 	Template string `yaml:"template"`
 	Branch   string `yaml:"branch"`
 	RegexStr string `yaml:"regexstr"`
+	// CustomFiles are read with their own regexstr when Path is empty, the
+	// first one matching has the version.
+	CustomFiles []CustomFile `yaml:"custom_files"`
+	// VersionField selects the Gradle field the version is read from:
+	// "version" (default), "versionName" or "versionCode".
+	VersionField string `yaml:"version_field"`
+	// VersionCode is a shorthand for VersionField "versionCode", the integer
+	// Android build number.
+	VersionCode bool `yaml:"version_code"`
+	// Branches override settings for the branches matching their glob
+	// pattern, e.g. "release/*". Matching branches are tagged besides Branch.
+	Branches map[string]BranchSettings `yaml:"branches"`
+	// Channels map a stream name (e.g. "beta") to the branches it's built from.
+	Channels   map[string]ChannelConfig `yaml:"channels"`
+	PreRelease bool                     `yaml:"prerelease"`
+	// PropagateToEnvironments are tagged on the same commit after the version tag.
+	PropagateToEnvironments []EnvironmentPropagation `yaml:"propagate_to_environments"`
+	// OnlyIfNoExistingTag skips tagging when the tag already points at the commit.
+	OnlyIfNoExistingTag bool `yaml:"only_if_no_existing_tag"`
+	// CommitStatusContextSkip is the context of the success commit status
+	// posted when OnlyIfNoExistingTag skips tagging, no status if empty.
+	CommitStatusContextSkip string `yaml:"commit_status_context_skip"`
+	// Comments is which commit comments ATC adds: "always" (default),
+	// "errors_only" for failures or "off".
+	Comments string `yaml:"comments"`
+	// Trigger is which webhook event tags a version: "push" (default) or
+	// "pr_merge" for the merge commit of a merged pull request.
+	Trigger string `yaml:"trigger"`
+	// SuccessCommentTemplate and ErrorCommentTemplate replace the default commit comments.
+	SuccessCommentTemplate string `yaml:"success_comment_template"`
+	ErrorCommentTemplate   string `yaml:"error_comment_template"`
+	// CreateRelease publishes a GitHub Release for the new tag. Its body is
+	// TagBody rendered as a template, or the content of TagBodyFile.
+	CreateRelease bool   `yaml:"create_release"`
+	TagBody       string `yaml:"tag_body"`
+	TagBodyFile   string `yaml:"tag_body_file"`
+	// TagAllCommits tags every version bump among the pushed commits instead
+	// of comparing only the before and after commits.
+	TagAllCommits bool `yaml:"tag_all_commits"`
+	// YAMLPath is the dot-separated key path of the version in a YAML file
+	// at Path, e.g. "spec.appVersion".
+	YAMLPath string `yaml:"yaml_path"`
+	// JIRAConfig, if set, releases a JIRA version named after each new tag.
+	JIRAConfig *JIRAConfig `yaml:"jira"`
+	// TagAnnotationTemplate renders the annotated tag message, which may span
+	// several lines. The tag name is used when it's empty.
+	TagAnnotationTemplate string `yaml:"tag_annotation_template"`
+	// DependencyName selects the dependency whose version is read from a
+	// Helm requirements.yaml.
+	DependencyName string `yaml:"dependency_name"`
+	// WaitForStatus is a commit status context that has to report success
+	// before the commit is tagged, waiting at most WaitForStatusTimeout.
+	WaitForStatus        string        `yaml:"wait_for_status"`
+	WaitForStatusTimeout time.Duration `yaml:"wait_for_status_timeout"`
+	// NotifyURL receives a JSON POST about each new tag. NotifyHeaders values
+	// are templates, e.g. "Bearer {{.Token}}".
+	NotifyURL     string            `yaml:"notify_url"`
+	NotifyHeaders map[string]string `yaml:"notify_headers"`
+	// Notifications are chats and webhooks told about each created tag and
+	// each failed tagging.
+	Notifications []NotificationTarget `yaml:"notifications"`
+	// VersionFormat is a regex new versions have to match to be tagged.
+	VersionFormat string `yaml:"version_format"`
+	// MaxVersionLength limits how long a version can be, 0 means
+	// DefaultMaxVersionLength.
+	MaxVersionLength int `yaml:"max_version_length"`
+	// CrossRepoTagging tags dependent repositories after the version tag.
+	CrossRepoTagging []CrossRepoTarget `yaml:"cross_repo_tagging"`
+	// CrossRepoSources are the repositories, "owner/name", whose
+	// cross_repo_tagging may tag this repository.
+	CrossRepoSources []string `yaml:"cross_repo_sources"`
+	// GemName selects the gem whose version is read from a Gemfile.lock.
+	GemName string `yaml:"gem_name"`
+	// PackageName selects the package whose version is read from a
+	// composer.lock.
+	PackageName string `yaml:"package_name"`
+	// LockPackageName selects the package whose resolved version is read
+	// from a Dart pubspec.lock.
+	LockPackageName string `yaml:"lock_package_name"`
+	// BotNames are pushers whose pushes are ignored, DefaultBotNames if nil.
+	// An empty list turns the check off.
+	BotNames []string `yaml:"bot_names"`
+	// MergeStrategy is how pull requests are merged into the branch: "merge"
+	// (default), "squash" or "rebase". It selects the commit to tag.
+	MergeStrategy string `yaml:"merge_strategy"`
+	// SyncNPMPackage publishes the package in NPMPackageDir of the checked out
+	// repository to the GitHub Packages registry of NPMScope after tagging.
+	// It needs a checkout, so they're action inputs of CI mode, not .atc.yaml
+	// keys.
+	SyncNPMPackage bool   `yaml:"-"`
+	NPMScope       string `yaml:"-"`
+	NPMPackageDir  string `yaml:"-"`
+	// TagProtectionBypass is set when the tags are covered by tag protection
+	// rules. ATC then checks that it may create them before tagging.
+	TagProtectionBypass bool `yaml:"tag_protection_bypass"`
+	// LabelOverrides applies the atc:skip, atc:major and atc:pre-release
+	// labels of the merged pull request.
+	LabelOverrides bool `yaml:"label_overrides"`
+	// CommitDirectives reads "[atc skip]" and "[atc tag]" in the head commit
+	// message: skip tagging a new version, or tag the version even if it
+	// didn't change.
+	CommitDirectives bool `yaml:"commit_directives"`
+	// WikiReleasePage creates or updates the "Release <version>" wiki page
+	// with the rendered WikiPageTemplate after tagging.
+	WikiReleasePage  bool   `yaml:"wiki_release_page"`
+	WikiPageTemplate string `yaml:"wiki_page_template"`
+	// NormalizeVersion compares and tags the versions as "major.minor.patch"
+	// without leading zeros, e.g. 1.2.3 for 1.02.003 and 1.2.0 for 1.2.
+	NormalizeVersion bool `yaml:"normalize_version"`
+	// VersionCheck is when a version is tagged: "changed" (default) for any
+	// other version, "semver" only for a higher semantic version.
+	// CommentOnDowngrade comments on the commits lowering the version.
+	VersionCheck       string `yaml:"version_check"`
+	CommentOnDowngrade bool   `yaml:"comment_on_downgrade"`
+	// VersionBumpCheck is the commit status context set on pull requests,
+	// failing when files matching the VersionBumpPaths patterns (any file if
+	// empty) changed without a version bump. Empty disables the check.
+	VersionBumpCheck string   `yaml:"version_bump_check"`
+	VersionBumpPaths []string `yaml:"version_bump_paths"`
+	// DryRun fetches the version and renders the tag, then only logs and
+	// comments the tag that would be created.
+	DryRun bool `yaml:"dry_run"`
+	// OnExistingTag is what happens when the tag ref already exists: "skip"
+	// quietly, "comment" on the commit or report an "error". Empty doesn't
+	// check before tagging.
+	OnExistingTag string `yaml:"on_existing_tag"`
+	// TagType is "annotated" (default) for a tag object with the tagger and
+	// message or "lightweight" for a ref pointing at the commit.
+	TagType string `yaml:"tag_type"`
+	// Changelog lists the commits since the tag of the previous version in
+	// the tag message and the release body, or as {{.Changelog}} in their
+	// templates.
+	Changelog bool `yaml:"changelog"`
+	// TagAnnotatorLogin is the GitHub user, e.g. a service account, whose
+	// login and noreply email are the tagger instead of the bot user of the
+	// app. It can't be used with TaggerName and TaggerEmail.
+	TagAnnotatorLogin string `yaml:"tag_annotator_login"`
+	// TaggerName and TaggerEmail are the tagger of the tags instead of the
+	// bot user of the app, or the pusher when the bot can't be looked up.
+	// Either can be set alone; the other one comes from the bot.
+	TaggerName  string `yaml:"tagger_name"`
+	TaggerEmail string `yaml:"tagger_email"`
+	// TagRefFormat is the ref created for the tag, formatted with the tag
+	// name. Empty means DefaultTagRefFormat.
+	TagRefFormat string `yaml:"tag_ref_format"`
+	// Targets are the version files of a monorepo, each tagged on its own.
+	// They replace Path.
+	Targets []Target `yaml:"targets"`
+	// NPMWorkspaces adds a target for every package of the npm workspaces
+	// or lerna packages of the root package.json, tagged "<name>@<version>".
+	NPMWorkspaces bool `yaml:"npm_workspaces"`
+}
+
+// Target is a version file of a monorepo. Its tags are TagPrefix followed by
+// the rendered Template, or the template of the settings if it's empty.
+type Target struct {
+	Path      string `yaml:"path"`
+	Template  string `yaml:"template"`
+	TagPrefix string `yaml:"tag_prefix"`
+}
+
+// CustomFile is a version file read with RegexStr, its version is the
+// "version" named group or else the first group.
+type CustomFile struct {
+	Path     string `yaml:"path"`
+	RegexStr string `yaml:"regexstr"`
+}
+
+// NotificationTarget is a Slack or Discord incoming webhook, or a generic
+// webhook receiving JSON.
+type NotificationTarget struct {
+	Type string `yaml:"type"`
+	URL  string `yaml:"url"`
+}
+
+type CrossRepoTarget struct {
+	// Repo is the full name of the repository, "owner/name".
+	Repo        string `yaml:"repo"`
+	TagTemplate string `yaml:"tag_template"`
+	// Condition is a tag name pattern, the target is tagged only if the new
+	// tag matches it. An empty condition matches every tag.
+	Condition string `yaml:"condition"`
+}
+
+type JIRAConfig struct {
+	BaseURL    string `yaml:"base_url"`
+	ProjectKey string `yaml:"project_key"`
+	Username   string `yaml:"username"`
+	// APIToken falls back to the ATC_JIRA_API_TOKEN environment variable so
+	// the token doesn't have to be committed.
+	APIToken string `yaml:"api_token"`
+}
+
+type EnvironmentPropagation struct {
+	// Branch, if set, is fast-forwarded to the tagged commit.
+	Branch   string        `yaml:"branch"`
+	Template string        `yaml:"template"`
+	Delay    time.Duration `yaml:"delay"`
+}
+
+// BranchSettings are the settings a branch pattern overrides, empty fields
+// keep the global value.
+type BranchSettings struct {
+	Path       string `yaml:"path"`
+	Template   string `yaml:"template"`
+	Behavior   string `yaml:"behavior"`
+	RegexStr   string `yaml:"regexstr"`
+	PreRelease bool   `yaml:"prerelease"`
+}
+
+type ChannelConfig struct {
+	BranchPattern string `yaml:"branch_pattern"`
+	Template      string `yaml:"template"`
+	PreRelease    bool   `yaml:"prerelease"`
+}
+
+// IsYAMLPath reports whether filePath has a YAML extension.
+func IsYAMLPath(filePath string) bool {
+	ext := strings.ToLower(path.Ext(filePath))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// ChannelForBranch returns the first channel, in name order, whose
+// BranchPattern matches branch. It returns nil if there is none.
+func (settings *AtcSettings) ChannelForBranch(branch string) (string, *ChannelConfig) {
+	names := make([]string, 0, len(settings.Channels))
+	for name := range settings.Channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		channel := settings.Channels[name]
+		if matched, _ := path.Match(channel.BranchPattern, branch); matched {
+			return name, &channel
+		}
+	}
+	return "", nil
+}
+
+// UseBranch applies the overrides of the Branches pattern matching branch
+// and reports whether there is one. The longest matching pattern wins, so
+// "release/1.x" beats "release/*".
+func (settings *AtcSettings) UseBranch(branch string) bool {
+	best := ""
+	found := false
+	for pattern := range settings.Branches {
+		if matched, _ := path.Match(pattern, branch); matched && (!found || len(pattern) > len(best) ||
+			len(pattern) == len(best) && pattern < best) {
+			best, found = pattern, true
+		}
+	}
+	if !found {
+		return false
+	}
+	override := settings.Branches[best]
+	if override.Path != "" {
+		settings.Path = override.Path
+	}
+	if override.Template != "" {
+		settings.Template = override.Template
+	}
+	if override.Behavior != "" {
+		settings.Behavior = override.Behavior
+	}
+	if override.RegexStr != "" {
+		settings.RegexStr = override.RegexStr
+	}
+	if override.PreRelease {
+		settings.PreRelease = true
+	}
+	return true
+}
+
+// IsBot reports whether pushes of pusher are ignored.
+func (settings *AtcSettings) IsBot(pusher string) bool {
+	botNames := settings.BotNames
+	if botNames == nil {
+		botNames = DefaultBotNames
+	}
+	for _, name := range botNames {
+		if strings.EqualFold(name, pusher) {
+			return true
+		}
+	}
+	return false
+}
+
+// AcceptsCrossRepoTag reports whether source, "owner/name", is one of the
+// cross_repo_sources allowed to tag the repository.
+func (settings *AtcSettings) AcceptsCrossRepoTag(source string) bool {
+	for _, name := range settings.CrossRepoSources {
+		if strings.EqualFold(name, source) {
+			return true
+		}
+	}
+	return false
+}
+
+// UseChannel applies the template and pre-release flag of the channel.
+func (settings *AtcSettings) UseChannel(channel *ChannelConfig) {
+	if channel.Template != "" {
+		settings.Template = channel.Template
+	}
+	settings.PreRelease = channel.PreRelease
 }
 
 func validateSettings(settings *AtcSettings) error {
@@ -45,6 +398,232 @@ func validateSettings(settings *AtcSettings) error {
 	if !strings.Contains(settings.Template, `{{.Version}}`) {
 		return errors.New(`error config file .atc.yaml: template doesn't contain "{{.Version}}"`)
 	}
+	//check VersionCode:
+	if settings.VersionCode && settings.VersionField != "" && settings.VersionField != VersionFieldVersionCode {
+		return fmt.Errorf("error config file .atc.yaml: version_code can't be used with version_field %q", settings.VersionField)
+	}
+	//check VersionField:
+	switch settings.VersionField {
+	case "", VersionFieldVersion, VersionFieldVersionName, VersionFieldVersionCode:
+	default:
+		return errors.New(`error config file .atc.yaml: version_field isn't "version", "versionName" or "versionCode"`)
+	}
+	//check TagAnnotatorLogin:
+	if settings.TagAnnotatorLogin != "" && !githubLoginRegex.MatchString(settings.TagAnnotatorLogin) {
+		return fmt.Errorf("error config file .atc.yaml: tag_annotator_login %q isn't a GitHub login", settings.TagAnnotatorLogin)
+	}
+	if settings.TagAnnotatorLogin != "" && (settings.TaggerName != "" || settings.TaggerEmail != "") {
+		return errors.New(`error config file .atc.yaml: tag_annotator_login can't be used with tagger_name and tagger_email`)
+	}
+	//check TaggerEmail:
+	if settings.TaggerEmail != "" && !strings.Contains(settings.TaggerEmail, "@") {
+		return fmt.Errorf("error config file .atc.yaml: tagger_email %q isn't an email address", settings.TaggerEmail)
+	}
+	//check TagRefFormat:
+	if settings.TagRefFormat != "" {
+		if strings.Count(settings.TagRefFormat, "%s") != 1 || strings.Count(settings.TagRefFormat, "%") != 1 {
+			return errors.New(`error config file .atc.yaml: tag_ref_format needs exactly one "%s" and no other verbs`)
+		}
+		if !strings.HasPrefix(settings.TagRefFormat, "refs/") {
+			return errors.New(`error config file .atc.yaml: tag_ref_format has to start with "refs/"`)
+		}
+	}
+	//check Targets:
+	if len(settings.Targets) > 0 && settings.Path != "" {
+		return errors.New(`error config file .atc.yaml: path can't be used with targets`)
+	}
+	if settings.NPMWorkspaces && settings.Path != "" {
+		return errors.New(`error config file .atc.yaml: path can't be used with npm_workspaces`)
+	}
+	for i, target := range settings.Targets {
+		if target.Path == "" {
+			return fmt.Errorf("error config file .atc.yaml: targets[%d] needs path", i)
+		}
+		if err := validateSettings(settings.forTarget(target)); err != nil {
+			return fmt.Errorf("%v (targets[%d])", err, i)
+		}
+	}
+	//check MergeStrategy:
+	switch settings.MergeStrategy {
+	case "", MergeStrategyMerge, MergeStrategySquash, MergeStrategyRebase:
+	default:
+		return errors.New(`error config file .atc.yaml: merge_strategy isn't "merge", "squash" or "rebase"`)
+	}
+	//check VersionCheck:
+	switch settings.VersionCheck {
+	case "", VersionCheckChanged, VersionCheckSemver:
+	default:
+		return errors.New(`error config file .atc.yaml: version_check isn't "changed" or "semver"`)
+	}
+	//check VersionBumpPaths:
+	for _, pattern := range settings.VersionBumpPaths {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("error config file .atc.yaml: wrong version_bump_paths pattern %q: %v", pattern, err)
+		}
+	}
+	//check OnExistingTag:
+	switch settings.OnExistingTag {
+	case "", OnExistingTagSkip, OnExistingTagComment, OnExistingTagError:
+	default:
+		return errors.New(`error config file .atc.yaml: on_existing_tag isn't "skip", "comment" or "error"`)
+	}
+	//check Comments:
+	switch settings.Comments {
+	case "", CommentsOff, CommentsErrorsOnly, CommentsAlways:
+	default:
+		return errors.New(`error config file .atc.yaml: comments isn't "off", "errors_only" or "always"`)
+	}
+	//check Trigger:
+	switch settings.Trigger {
+	case "", TriggerPush, TriggerPRMerge:
+	default:
+		return errors.New(`error config file .atc.yaml: trigger isn't "push" or "pr_merge"`)
+	}
+	//check TagType:
+	switch settings.TagType {
+	case "", TagTypeAnnotated, TagTypeLightweight:
+	default:
+		return errors.New(`error config file .atc.yaml: tag_type isn't "annotated" or "lightweight"`)
+	}
+	//check Notifications:
+	for i, target := range settings.Notifications {
+		switch target.Type {
+		case NotificationTypeSlack, NotificationTypeDiscord, NotificationTypeWebhook:
+		default:
+			return fmt.Errorf(`error config file .atc.yaml: notifications[%d] type isn't "slack", "discord" or "webhook"`, i)
+		}
+		if target.URL == "" {
+			return fmt.Errorf("error config file .atc.yaml: notifications[%d] doesn't have url", i)
+		}
+	}
+	//check Channels:
+	for name, channel := range settings.Channels {
+		if channel.BranchPattern == "" {
+			return fmt.Errorf("error config file .atc.yaml: channel %q doesn't have branch_pattern", name)
+		}
+		if _, err := path.Match(channel.BranchPattern, ""); err != nil {
+			return fmt.Errorf("error config file .atc.yaml: channel %q has wrong branch_pattern: %v", name, err)
+		}
+		if channel.Template != "" && !strings.Contains(channel.Template, `{{.Version}}`) {
+			return fmt.Errorf(`error config file .atc.yaml: channel %q template doesn't contain "{{.Version}}"`, name)
+		}
+	}
+	//check Branches:
+	for pattern, branch := range settings.Branches {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("error config file .atc.yaml: wrong branches pattern %q: %v", pattern, err)
+		}
+		if branch.Template != "" && !strings.Contains(branch.Template, `{{.Version}}`) {
+			return fmt.Errorf(`error config file .atc.yaml: branches %q template doesn't contain "{{.Version}}"`, pattern)
+		}
+		if b := strings.ToLower(branch.Behavior); b != "" && b != BehaviorAfter && b != BehaviorBefore {
+			return fmt.Errorf(`error config file .atc.yaml: branches %q behavior isn't "before" or "after"`, pattern)
+		}
+		if strings.HasPrefix(branch.Path, pathPrefix) {
+			return fmt.Errorf(`error config file .atc.yaml: branches %q path has prefix "/"`, pattern)
+		}
+	}
+	//check PropagateToEnvironments:
+	for i, env := range settings.PropagateToEnvironments {
+		if !strings.Contains(env.Template, `{{.Version}}`) {
+			return fmt.Errorf(`error config file .atc.yaml: propagate_to_environments[%d] template doesn't contain "{{.Version}}"`, i)
+		}
+		if env.Delay < 0 {
+			return fmt.Errorf("error config file .atc.yaml: propagate_to_environments[%d] has negative delay", i)
+		}
+	}
+	//check TagBody:
+	if settings.TagBody != "" && settings.TagBodyFile != "" {
+		return errors.New(`error config file .atc.yaml: tag_body and tag_body_file can't be used together`)
+	}
+	//check JIRAConfig:
+	if settings.JIRAConfig != nil && (settings.JIRAConfig.BaseURL == "" || settings.JIRAConfig.ProjectKey == "") {
+		return errors.New(`error config file .atc.yaml: jira needs base_url and project_key`)
+	}
+	//check VersionFormat:
+	if _, err := regexp.Compile(settings.VersionFormat); err != nil {
+		return fmt.Errorf("error config file .atc.yaml: wrong version_format: %v", err)
+	}
+	//check CrossRepoTagging:
+	for i, target := range settings.CrossRepoTagging {
+		if s := strings.Split(target.Repo, "/"); len(s) != 2 || s[0] == "" || s[1] == "" {
+			return fmt.Errorf(`error config file .atc.yaml: cross_repo_tagging[%d] repo isn't "owner/name"`, i)
+		}
+		if !strings.Contains(target.TagTemplate, `{{.Version}}`) {
+			return fmt.Errorf(`error config file .atc.yaml: cross_repo_tagging[%d] tag_template doesn't contain "{{.Version}}"`, i)
+		}
+		if _, err := path.Match(target.Condition, ""); err != nil {
+			return fmt.Errorf("error config file .atc.yaml: cross_repo_tagging[%d] has wrong condition: %v", i, err)
+		}
+	}
+	//check CrossRepoSources:
+	for i, source := range settings.CrossRepoSources {
+		if s := strings.Split(source, "/"); len(s) != 2 || s[0] == "" || s[1] == "" {
+			return fmt.Errorf(`error config file .atc.yaml: cross_repo_sources[%d] isn't "owner/name"`, i)
+		}
+	}
+	//check MaxVersionLength:
+	if settings.MaxVersionLength < 0 {
+		return errors.New("error config file .atc.yaml: max_version_length is negative")
+	}
+	//check WaitForStatusTimeout:
+	if settings.WaitForStatusTimeout < 0 {
+		return errors.New("error config file .atc.yaml: wait_for_status_timeout is negative")
+	}
+	//check DependencyName:
+	isRequirementsYaml := path.Base(settings.Path) == "requirements.yaml"
+	if settings.DependencyName != "" && !isRequirementsYaml {
+		return errors.New(`error config file .atc.yaml: dependency_name needs a path to requirements.yaml`)
+	}
+	if isRequirementsYaml && settings.DependencyName == "" && settings.YAMLPath == "" {
+		return errors.New(`error config file .atc.yaml: requirements.yaml needs dependency_name`)
+	}
+	//check GemName:
+	isGemfileLock := path.Base(settings.Path) == "Gemfile.lock"
+	if settings.GemName != "" && !isGemfileLock {
+		return errors.New(`error config file .atc.yaml: gem_name needs a path to Gemfile.lock`)
+	}
+	if isGemfileLock && settings.GemName == "" {
+		return errors.New(`error config file .atc.yaml: Gemfile.lock needs gem_name`)
+	}
+	//check PackageName:
+	isComposerLock := path.Base(settings.Path) == "composer.lock"
+	if settings.PackageName != "" && !isComposerLock {
+		return errors.New(`error config file .atc.yaml: package_name needs a path to composer.lock`)
+	}
+	if isComposerLock && settings.PackageName == "" {
+		return errors.New(`error config file .atc.yaml: composer.lock needs package_name`)
+	}
+	//check LockPackageName:
+	isPubspecLock := path.Base(settings.Path) == "pubspec.lock"
+	if settings.LockPackageName != "" && !isPubspecLock {
+		return errors.New(`error config file .atc.yaml: lock_package_name needs a path to pubspec.lock`)
+	}
+	if isPubspecLock && settings.LockPackageName == "" {
+		return errors.New(`error config file .atc.yaml: pubspec.lock needs lock_package_name`)
+	}
+	//check CustomFiles:
+	for i, file := range settings.CustomFiles {
+		if file.Path == "" || file.RegexStr == "" {
+			return fmt.Errorf("error config file .atc.yaml: custom_files[%d] needs path and regexstr", i)
+		}
+		regex, err := regexp.Compile(file.RegexStr)
+		if err != nil {
+			return fmt.Errorf("error config file .atc.yaml: custom_files[%d] has wrong regexstr: %v", i, err)
+		}
+		if regex.NumSubexp() == 0 {
+			return fmt.Errorf("error config file .atc.yaml: custom_files[%d] regexstr doesn't have a group", i)
+		}
+	}
+	//check YAMLPath:
+	if settings.YAMLPath != "" {
+		if !IsYAMLPath(settings.Path) {
+			return errors.New(`error config file .atc.yaml: yaml_path needs a path to a ".yaml" or ".yml" file`)
+		}
+		if strings.HasPrefix(settings.YAMLPath, ".") || strings.HasSuffix(settings.YAMLPath, ".") || strings.Contains(settings.YAMLPath, "..") {
+			return errors.New(`error config file .atc.yaml: yaml_path has an empty key`)
+		}
+	}
 	//check Path:
 	pathPrefix := "/"
 
@@ -57,15 +636,42 @@ func validateSettings(settings *AtcSettings) error {
 	if strings.Contains(settings.Path, "//") {
 		return errors.New(`error config file .atc.yaml; path has "//"`)
 	}
+	if _, err := path.Match(settings.Path, ""); err != nil {
+		return fmt.Errorf("error config file .atc.yaml; wrong path pattern: %v", err)
+	}
 	return nil
 }
 
+func (settings *AtcSettings) forTarget(target Target) *AtcSettings {
+	targetSettings := *settings
+	targetSettings.Targets = nil
+	targetSettings.Path = target.Path
+	if target.Template != "" {
+		targetSettings.Template = target.Template
+	}
+	targetSettings.Template = target.TagPrefix + targetSettings.Template
+	return &targetSettings
+}
+
+// TargetSettings returns the settings of every target, or only settings if
+// there are no targets.
+func (settings *AtcSettings) TargetSettings() []*AtcSettings {
+	if len(settings.Targets) == 0 {
+		return []*AtcSettings{settings}
+	}
+	targetSettings := make([]*AtcSettings, 0, len(settings.Targets))
+	for _, target := range settings.Targets {
+		targetSettings = append(targetSettings, settings.forTarget(target))
+	}
+	return targetSettings
+}
+
 func GetAtcSetting(ghcp provider.ContentProvider) (*AtcSettings, error) {
 	settings := &AtcSettings{}
 
 	content, err := ghcp.GetContents(".atc.yaml")
 	if err != nil {
-		log.Printf("get .atc.yaml error: %s. Used default settings", err)
+		logger.Warnf("get .atc.yaml error: %s. Used default settings", err)
 		return &AtcSettings{Behavior: "after", Template: "v{{.Version}}"}, nil
 	}
 
@@ -76,5 +682,14 @@ func GetAtcSetting(ghcp provider.ContentProvider) (*AtcSettings, error) {
 	if err := validateSettings(settings); err != nil {
 		return nil, err
 	}
+	settings.normalize()
 	return settings, nil
 }
+
+// normalize replaces the shorthands of valid settings with the settings they
+// stand for.
+func (settings *AtcSettings) normalize() {
+	if settings.VersionCode {
+		settings.VersionField = VersionFieldVersionCode
+	}
+}
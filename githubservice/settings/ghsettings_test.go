@@ -3,6 +3,7 @@ package settings
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/smartforce-io/atc/githubservice/provider"
@@ -72,22 +73,34 @@ func TestCheckSettingsForErrors(t *testing.T) {
 		template         string
 		branch           string
 		regexstr         string
+		versionField     string
 		expectedErrorStr string
 	}{
-		{"/contents/pom.xml", "", "", "", "", `error config file .atc.yaml; path has prefix "/"`},
-		{"contents//asd.txt", "", "", "", "", `error config file .atc.yaml; path has "//"`},
-		{"contents/asd.txt", "", "", "", "", fmt.Sprint(nil)},
-		{"contents/pom.xml/", "bef", "", "", "", `error config file .atc.yaml: behavior doesn't contain "before" or "after"`},
-		{"package.json", "after", "{.version}", "", "", `error config file .atc.yaml: template doesn't contain "{{.Version}}"`},
-		{"pubspec.yaml", "before", ".vers", "", "", `error config file .atc.yaml: template doesn't contain "{{.Version}}"`},
-		{"contents/pom.xml", "before", "v{{.Version}}V", "testbranch", "", fmt.Sprint(nil)},
+		{"/contents/pom.xml", "", "", "", "", "", `error config file .atc.yaml; path has prefix "/"`},
+		{"contents//asd.txt", "", "", "", "", "", `error config file .atc.yaml; path has "//"`},
+		{"contents/asd.txt", "", "", "", "", "", fmt.Sprint(nil)},
+		{"**/pom.xml", "", "", "", "", "", fmt.Sprint(nil)},
+		{"modules/[a-/pom.xml", "", "", "", "", "", `error config file .atc.yaml; wrong path pattern: syntax error in pattern`},
+		{"contents/pom.xml/", "bef", "", "", "", "", `error config file .atc.yaml: behavior doesn't contain "before" or "after"`},
+		{"package.json", "after", "{.version}", "", "", "", `error config file .atc.yaml: template doesn't contain "{{.Version}}"`},
+		{"pubspec.yaml", "before", ".vers", "", "", "", `error config file .atc.yaml: template doesn't contain "{{.Version}}"`},
+		{"contents/pom.xml", "before", "v{{.Version}}V", "testbranch", "", "", fmt.Sprint(nil)},
+		{"build.gradle", "", "", "", "", "versionCode", fmt.Sprint(nil)},
+		{"build.gradle", "", "", "", "", "versionNumber", `error config file .atc.yaml: version_field isn't "version", "versionName" or "versionCode"`},
 	}
 
 	for _, test := range tests {
-		settings := &AtcSettings{test.path, test.behavior, test.template, test.branch, test.regexstr}
+		settings := &AtcSettings{
+			Path:         test.path,
+			Behavior:     test.behavior,
+			Template:     test.template,
+			Branch:       test.branch,
+			RegexStr:     test.regexstr,
+			VersionField: test.versionField,
+		}
 		err := validateSettings(settings)
 		if fmt.Sprint(err) != test.expectedErrorStr {
-			t.Errorf("no takes error settings:%s\nexpected: %s, got: %s", settings, test.expectedErrorStr, err)
+			t.Errorf("no takes error settings:%+v\nexpected: %s, got: %s", settings, test.expectedErrorStr, err)
 		}
 	}
 }
@@ -153,3 +166,541 @@ func TestAtcSettingUnmarshalError(t *testing.T) {
 
 	unmarshal = unmarshalcp
 }
+
+func TestUseBranch(t *testing.T) {
+	var tests = []struct {
+		branch   string
+		matched  bool
+		path     string
+		template string
+	}{
+		{"main", true, "pom.xml", "main-{{.Version}}"},
+		{"release/2.0", true, "app/pom.xml", "rc-{{.Version}}"},
+		{"release/1.x", true, "pom.xml", "legacy-{{.Version}}"},
+		{"feature/x", false, "pom.xml", "v{{.Version}}"},
+	}
+	for _, test := range tests {
+		settings := &AtcSettings{Path: "pom.xml", Template: "v{{.Version}}", Branches: map[string]BranchSettings{
+			"main":        {Template: "main-{{.Version}}"},
+			"release/*":   {Path: "app/pom.xml", Template: "rc-{{.Version}}", PreRelease: true},
+			"release/1.x": {Template: "legacy-{{.Version}}"},
+		}}
+		if matched := settings.UseBranch(test.branch); matched != test.matched {
+			t.Errorf("branch %q: expected matched %v, got %v", test.branch, test.matched, matched)
+		}
+		if settings.Path != test.path || settings.Template != test.template {
+			t.Errorf("branch %q: expected %q, %q, got %q, %q", test.branch, test.path, test.template, settings.Path, settings.Template)
+		}
+	}
+}
+
+func TestCheckBranchesForErrors(t *testing.T) {
+	var tests = []struct {
+		branches map[string]BranchSettings
+		expected string
+	}{
+		{map[string]BranchSettings{"release/*": {Template: "rc-{{.Version}}", Behavior: "before"}}, "<nil>"},
+		{map[string]BranchSettings{"release/[": {}}, `error config file .atc.yaml: wrong branches pattern "release/[": syntax error in pattern`},
+		{map[string]BranchSettings{"main": {Template: "rc"}}, `error config file .atc.yaml: branches "main" template doesn't contain "{{.Version}}"`},
+		{map[string]BranchSettings{"main": {Behavior: "later"}}, `error config file .atc.yaml: branches "main" behavior isn't "before" or "after"`},
+		{map[string]BranchSettings{"main": {Path: "/pom.xml"}}, `error config file .atc.yaml: branches "main" path has prefix "/"`},
+	}
+	for _, test := range tests {
+		if err := validateSettings(&AtcSettings{Branches: test.branches}); fmt.Sprint(err) != test.expected {
+			t.Errorf("expected %q, got %v", test.expected, err)
+		}
+	}
+}
+
+func TestChannelForBranch(t *testing.T) {
+	cp := provider.MockContentProvider{Content: `
+path: pom.xml
+channels:
+  stable:
+    branch_pattern: "main"
+  beta:
+    branch_pattern: "release/*-beta"
+    template: "v{{.Version}}-beta"
+    prerelease: true`}
+	settings, err := GetAtcSetting(&cp)
+	if err != nil {
+		t.Errorf("Unexpected error %v", err)
+		return
+	}
+
+	var tests = []struct {
+		branch  string
+		channel string
+	}{
+		{"main", "stable"},
+		{"release/2.0-beta", "beta"},
+		{"release/2.0", ""},
+	}
+	for _, test := range tests {
+		name, channel := settings.ChannelForBranch(test.branch)
+		if name != test.channel {
+			t.Errorf("branch %q: expected channel %q, got %q", test.branch, test.channel, name)
+		}
+		if (channel == nil) != (test.channel == "") {
+			t.Errorf("branch %q: wrong channel config %v", test.branch, channel)
+		}
+	}
+}
+
+func TestCheckChannelsForErrors(t *testing.T) {
+	var tests = []struct {
+		channel          ChannelConfig
+		expectedErrorStr string
+	}{
+		{ChannelConfig{BranchPattern: "release/*"}, fmt.Sprint(nil)},
+		{ChannelConfig{}, `error config file .atc.yaml: channel "beta" doesn't have branch_pattern`},
+		{ChannelConfig{BranchPattern: "release/["}, `error config file .atc.yaml: channel "beta" has wrong branch_pattern: syntax error in pattern`},
+		{ChannelConfig{BranchPattern: "beta", Template: "beta"}, `error config file .atc.yaml: channel "beta" template doesn't contain "{{.Version}}"`},
+	}
+	for _, test := range tests {
+		settings := &AtcSettings{Channels: map[string]ChannelConfig{"beta": test.channel}}
+		if err := validateSettings(settings); fmt.Sprint(err) != test.expectedErrorStr {
+			t.Errorf("channel %v\nexpected: %s, got: %s", test.channel, test.expectedErrorStr, err)
+		}
+	}
+}
+
+func TestCheckTagBodyForErrors(t *testing.T) {
+	settings := &AtcSettings{CreateRelease: true, TagBody: "{{.Tag}}", TagBodyFile: "RELEASE_NOTES.md"}
+	expected := `error config file .atc.yaml: tag_body and tag_body_file can't be used together`
+	if err := validateSettings(settings); fmt.Sprint(err) != expected {
+		t.Errorf("expected: %s, got: %s", expected, err)
+	}
+}
+
+func TestCheckYAMLPathForErrors(t *testing.T) {
+	var tests = []struct {
+		path             string
+		yamlPath         string
+		expectedErrorStr string
+	}{
+		{"charts/atc/Chart.yaml", "appVersion", fmt.Sprint(nil)},
+		{"version.yml", "spec.version", fmt.Sprint(nil)},
+		{"pom.xml", "project.version", `error config file .atc.yaml: yaml_path needs a path to a ".yaml" or ".yml" file`},
+		{"Chart.yaml", "spec..version", `error config file .atc.yaml: yaml_path has an empty key`},
+		{"Chart.yaml", "spec.", `error config file .atc.yaml: yaml_path has an empty key`},
+	}
+	for _, test := range tests {
+		settings := &AtcSettings{Path: test.path, YAMLPath: test.yamlPath}
+		if err := validateSettings(settings); fmt.Sprint(err) != test.expectedErrorStr {
+			t.Errorf("path %q, yaml_path %q\nexpected: %s, got: %s", test.path, test.yamlPath, test.expectedErrorStr, err)
+		}
+	}
+}
+
+func TestCheckJIRAConfigForErrors(t *testing.T) {
+	settings := &AtcSettings{JIRAConfig: &JIRAConfig{BaseURL: "https://example.atlassian.net"}}
+	expected := `error config file .atc.yaml: jira needs base_url and project_key`
+	if err := validateSettings(settings); fmt.Sprint(err) != expected {
+		t.Errorf("expected: %s, got: %s", expected, err)
+	}
+	settings.JIRAConfig.ProjectKey = "ATC"
+	if err := validateSettings(settings); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+func TestCheckDependencyNameForErrors(t *testing.T) {
+	var tests = []struct {
+		settings         AtcSettings
+		expectedErrorStr string
+	}{
+		{AtcSettings{Path: "chart/requirements.yaml", DependencyName: "atc"}, fmt.Sprint(nil)},
+		{AtcSettings{Path: "requirements.yaml", YAMLPath: "dependencies.0.version"}, fmt.Sprint(nil)},
+		{AtcSettings{Path: "requirements.yaml"}, `error config file .atc.yaml: requirements.yaml needs dependency_name`},
+		{AtcSettings{Path: "Chart.yaml", DependencyName: "atc"}, `error config file .atc.yaml: dependency_name needs a path to requirements.yaml`},
+	}
+	for _, test := range tests {
+		if err := validateSettings(&test.settings); fmt.Sprint(err) != test.expectedErrorStr {
+			t.Errorf("settings %+v\nexpected: %s, got: %s", test.settings, test.expectedErrorStr, err)
+		}
+	}
+}
+
+func TestCheckVersionFormatForErrors(t *testing.T) {
+	settings := &AtcSettings{VersionFormat: `^\d+(`}
+	expected := "error config file .atc.yaml: wrong version_format: error parsing regexp: missing closing ): `^\\d+(`"
+	if err := validateSettings(settings); fmt.Sprint(err) != expected {
+		t.Errorf("expected: %s, got: %s", expected, err)
+	}
+}
+
+func TestCheckMaxVersionLengthForErrors(t *testing.T) {
+	settings := &AtcSettings{MaxVersionLength: -1}
+	expected := `error config file .atc.yaml: max_version_length is negative`
+	if err := validateSettings(settings); fmt.Sprint(err) != expected {
+		t.Errorf("expected: %s, got: %s", expected, err)
+	}
+}
+
+func TestCheckCrossRepoTaggingForErrors(t *testing.T) {
+	var tests = []struct {
+		target           CrossRepoTarget
+		expectedErrorStr string
+	}{
+		{CrossRepoTarget{Repo: "owner/app", TagTemplate: "lib-{{.Version}}", Condition: "v1.*"}, fmt.Sprint(nil)},
+		{CrossRepoTarget{Repo: "app", TagTemplate: "lib-{{.Version}}"}, `error config file .atc.yaml: cross_repo_tagging[0] repo isn't "owner/name"`},
+		{CrossRepoTarget{Repo: "owner/app", TagTemplate: "lib"}, `error config file .atc.yaml: cross_repo_tagging[0] tag_template doesn't contain "{{.Version}}"`},
+		{CrossRepoTarget{Repo: "owner/app", TagTemplate: "lib-{{.Version}}", Condition: "v["}, `error config file .atc.yaml: cross_repo_tagging[0] has wrong condition: syntax error in pattern`},
+	}
+	for _, test := range tests {
+		settings := &AtcSettings{CrossRepoTagging: []CrossRepoTarget{test.target}}
+		if err := validateSettings(settings); fmt.Sprint(err) != test.expectedErrorStr {
+			t.Errorf("target %+v\nexpected: %s, got: %s", test.target, test.expectedErrorStr, err)
+		}
+	}
+}
+
+func TestCheckCrossRepoSourcesForErrors(t *testing.T) {
+	var tests = []struct {
+		sources          []string
+		expectedErrorStr string
+	}{
+		{[]string{"owner/lib"}, fmt.Sprint(nil)},
+		{[]string{"owner/lib", "lib"}, `error config file .atc.yaml: cross_repo_sources[1] isn't "owner/name"`},
+		{[]string{"owner/"}, `error config file .atc.yaml: cross_repo_sources[0] isn't "owner/name"`},
+	}
+	for _, test := range tests {
+		settings := &AtcSettings{CrossRepoSources: test.sources}
+		if err := validateSettings(settings); fmt.Sprint(err) != test.expectedErrorStr {
+			t.Errorf("sources %v\nexpected: %s, got: %s", test.sources, test.expectedErrorStr, err)
+		}
+	}
+}
+
+func TestAcceptsCrossRepoTag(t *testing.T) {
+	settings := &AtcSettings{CrossRepoSources: []string{"Owner/Lib"}}
+	if !settings.AcceptsCrossRepoTag("owner/lib") {
+		t.Errorf("owner/lib isn't accepted")
+	}
+	if settings.AcceptsCrossRepoTag("owner/other") || (&AtcSettings{}).AcceptsCrossRepoTag("owner/lib") {
+		t.Errorf("unlisted source is accepted")
+	}
+}
+
+func TestCheckGemNameForErrors(t *testing.T) {
+	var tests = []struct {
+		settings         AtcSettings
+		expectedErrorStr string
+	}{
+		{AtcSettings{Path: "Gemfile.lock", GemName: "myapp"}, fmt.Sprint(nil)},
+		{AtcSettings{Path: "Gemfile.lock"}, `error config file .atc.yaml: Gemfile.lock needs gem_name`},
+		{AtcSettings{Path: "Gemfile", GemName: "myapp"}, `error config file .atc.yaml: gem_name needs a path to Gemfile.lock`},
+	}
+	for _, test := range tests {
+		if err := validateSettings(&test.settings); fmt.Sprint(err) != test.expectedErrorStr {
+			t.Errorf("settings %+v\nexpected: %s, got: %s", test.settings, test.expectedErrorStr, err)
+		}
+	}
+}
+
+func TestCheckPackageNameForErrors(t *testing.T) {
+	var tests = []struct {
+		settings         AtcSettings
+		expectedErrorStr string
+	}{
+		{AtcSettings{Path: "composer.lock", PackageName: "acme/wp-core"}, fmt.Sprint(nil)},
+		{AtcSettings{Path: "composer.lock"}, `error config file .atc.yaml: composer.lock needs package_name`},
+		{AtcSettings{Path: "composer.json", PackageName: "acme/wp-core"}, `error config file .atc.yaml: package_name needs a path to composer.lock`},
+	}
+	for _, test := range tests {
+		if err := validateSettings(&test.settings); fmt.Sprint(err) != test.expectedErrorStr {
+			t.Errorf("settings %+v\nexpected: %s, got: %s", test.settings, test.expectedErrorStr, err)
+		}
+	}
+}
+
+func TestCheckLockPackageNameForErrors(t *testing.T) {
+	var tests = []struct {
+		settings         AtcSettings
+		expectedErrorStr string
+	}{
+		{AtcSettings{Path: "app/pubspec.lock", LockPackageName: "http"}, fmt.Sprint(nil)},
+		{AtcSettings{Path: "pubspec.lock"}, `error config file .atc.yaml: pubspec.lock needs lock_package_name`},
+		{AtcSettings{Path: "pubspec.yaml", LockPackageName: "http"}, `error config file .atc.yaml: lock_package_name needs a path to pubspec.lock`},
+	}
+	for _, test := range tests {
+		if err := validateSettings(&test.settings); fmt.Sprint(err) != test.expectedErrorStr {
+			t.Errorf("settings %+v\nexpected: %s, got: %s", test.settings, test.expectedErrorStr, err)
+		}
+	}
+}
+
+func TestIsBot(t *testing.T) {
+	var tests = []struct {
+		config string
+		pusher string
+		isBot  bool
+	}{
+		{"path: pom.xml", "github-actions[bot]", true},
+		{"path: pom.xml", "Dependabot[bot]", true},
+		{"path: pom.xml", TaggerName, true},
+		{"path: pom.xml", "Codertocat", false},
+		{"path: pom.xml\nbot_names: [release-bot]", "release-bot", true},
+		{"path: pom.xml\nbot_names: [release-bot]", "github-actions[bot]", false},
+		{"path: pom.xml\nbot_names: []", "github-actions[bot]", false},
+	}
+	for _, test := range tests {
+		cp := provider.MockContentProvider{Content: test.config}
+		settings, err := GetAtcSetting(&cp)
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+		if isBot := settings.IsBot(test.pusher); isBot != test.isBot {
+			t.Errorf("config %q, pusher %q: expected %v, got %v", test.config, test.pusher, test.isBot, isBot)
+		}
+	}
+}
+
+func TestCheckMergeStrategyForErrors(t *testing.T) {
+	for _, strategy := range []string{"", MergeStrategyMerge, MergeStrategySquash, MergeStrategyRebase} {
+		if err := validateSettings(&AtcSettings{MergeStrategy: strategy}); err != nil {
+			t.Errorf("merge_strategy %q: unexpected error %v", strategy, err)
+		}
+	}
+	expected := `error config file .atc.yaml: merge_strategy isn't "merge", "squash" or "rebase"`
+	if err := validateSettings(&AtcSettings{MergeStrategy: "fast-forward"}); fmt.Sprint(err) != expected {
+		t.Errorf("expected: %s, got: %v", expected, err)
+	}
+}
+
+func TestCheckOnExistingTagForErrors(t *testing.T) {
+	for _, onExistingTag := range []string{"", OnExistingTagSkip, OnExistingTagComment, OnExistingTagError} {
+		if err := validateSettings(&AtcSettings{OnExistingTag: onExistingTag}); err != nil {
+			t.Errorf("on_existing_tag %q: unexpected error %v", onExistingTag, err)
+		}
+	}
+	expected := `error config file .atc.yaml: on_existing_tag isn't "skip", "comment" or "error"`
+	if err := validateSettings(&AtcSettings{OnExistingTag: "overwrite"}); fmt.Sprint(err) != expected {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+}
+
+func TestCheckTagTypeForErrors(t *testing.T) {
+	for _, tagType := range []string{"", TagTypeAnnotated, TagTypeLightweight} {
+		if err := validateSettings(&AtcSettings{TagType: tagType}); err != nil {
+			t.Errorf("tag_type %q: unexpected error %v", tagType, err)
+		}
+	}
+	expected := `error config file .atc.yaml: tag_type isn't "annotated" or "lightweight"`
+	if err := validateSettings(&AtcSettings{TagType: "signed"}); fmt.Sprint(err) != expected {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+}
+
+func TestCheckCommentsForErrors(t *testing.T) {
+	for _, comments := range []string{"", CommentsOff, CommentsErrorsOnly, CommentsAlways} {
+		if err := validateSettings(&AtcSettings{Comments: comments}); err != nil {
+			t.Errorf("comments %q: unexpected error %v", comments, err)
+		}
+	}
+	expected := `error config file .atc.yaml: comments isn't "off", "errors_only" or "always"`
+	if err := validateSettings(&AtcSettings{Comments: "errors"}); fmt.Sprint(err) != expected {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+}
+
+func TestCheckTriggerForErrors(t *testing.T) {
+	for _, trigger := range []string{"", TriggerPush, TriggerPRMerge} {
+		if err := validateSettings(&AtcSettings{Trigger: trigger}); err != nil {
+			t.Errorf("trigger %q: unexpected error %v", trigger, err)
+		}
+	}
+	expected := `error config file .atc.yaml: trigger isn't "push" or "pr_merge"`
+	if err := validateSettings(&AtcSettings{Trigger: "pull_request"}); fmt.Sprint(err) != expected {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+}
+
+func TestCheckCustomFilesForErrors(t *testing.T) {
+	valid := []CustomFile{
+		{Path: "VERSION.txt", RegexStr: "v(.+)"},
+		{Path: "build/info.ini", RegexStr: `release = (?P<version>\S+)`},
+	}
+	if err := validateSettings(&AtcSettings{CustomFiles: valid}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	var tests = []struct {
+		file     CustomFile
+		expected string
+	}{
+		{CustomFile{RegexStr: "v(.+)"}, "error config file .atc.yaml: custom_files[0] needs path and regexstr"},
+		{CustomFile{Path: "VERSION.txt"}, "error config file .atc.yaml: custom_files[0] needs path and regexstr"},
+		{CustomFile{Path: "VERSION.txt", RegexStr: "v(.+"}, "error config file .atc.yaml: custom_files[0] has wrong regexstr: error parsing regexp: missing closing ): `v(.+`"},
+		{CustomFile{Path: "VERSION.txt", RegexStr: "v.+"}, "error config file .atc.yaml: custom_files[0] regexstr doesn't have a group"},
+	}
+	for _, test := range tests {
+		err := validateSettings(&AtcSettings{CustomFiles: []CustomFile{test.file}})
+		if fmt.Sprint(err) != test.expected {
+			t.Errorf("expected %q, got %v", test.expected, err)
+		}
+	}
+}
+
+func TestCheckNotificationsForErrors(t *testing.T) {
+	valid := []NotificationTarget{
+		{Type: NotificationTypeSlack, URL: "https://hooks.slack.com/services/T/B/X"},
+		{Type: NotificationTypeDiscord, URL: "https://discord.com/api/webhooks/1/x"},
+		{Type: NotificationTypeWebhook, URL: "https://example.com/atc"},
+	}
+	if err := validateSettings(&AtcSettings{Notifications: valid}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	var tests = []struct {
+		target   NotificationTarget
+		expected string
+	}{
+		{NotificationTarget{Type: "teams", URL: "https://example.com"}, `error config file .atc.yaml: notifications[0] type isn't "slack", "discord" or "webhook"`},
+		{NotificationTarget{Type: NotificationTypeSlack}, `error config file .atc.yaml: notifications[0] doesn't have url`},
+	}
+	for _, test := range tests {
+		err := validateSettings(&AtcSettings{Notifications: []NotificationTarget{test.target}})
+		if fmt.Sprint(err) != test.expected {
+			t.Errorf("expected %q, got %v", test.expected, err)
+		}
+	}
+}
+
+func TestCheckVersionCheckForErrors(t *testing.T) {
+	for _, check := range []string{"", VersionCheckChanged, VersionCheckSemver} {
+		if err := validateSettings(&AtcSettings{VersionCheck: check}); err != nil {
+			t.Errorf("version_check %q: unexpected error %v", check, err)
+		}
+	}
+	expected := `error config file .atc.yaml: version_check isn't "changed" or "semver"`
+	if err := validateSettings(&AtcSettings{VersionCheck: "greater"}); fmt.Sprint(err) != expected {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+}
+
+func TestCheckVersionBumpPathsForErrors(t *testing.T) {
+	if err := validateSettings(&AtcSettings{VersionBumpPaths: []string{"src/**", "*.go"}}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	expected := `error config file .atc.yaml: wrong version_bump_paths pattern "src/[": syntax error in pattern`
+	if err := validateSettings(&AtcSettings{VersionBumpPaths: []string{"src/["}}); fmt.Sprint(err) != expected {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+}
+
+func TestVersionCode(t *testing.T) {
+	settings, err := GetAtcSetting(&provider.MockContentProvider{Content: "path: app/build.gradle\nversion_code: true"})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if settings.VersionField != VersionFieldVersionCode {
+		t.Errorf("expected version_field %q, got %q", VersionFieldVersionCode, settings.VersionField)
+	}
+	unchanged := &AtcSettings{Path: "app/build.gradle", VersionCode: true}
+	if err := validateSettings(unchanged); err != nil || unchanged.VersionField != "" {
+		t.Errorf("validateSettings changed version_field to %q, error %v", unchanged.VersionField, err)
+	}
+	expected := `error config file .atc.yaml: version_code can't be used with version_field "versionName"`
+	if err := validateSettings(&AtcSettings{VersionCode: true, VersionField: VersionFieldVersionName}); fmt.Sprint(err) != expected {
+		t.Errorf("expected: %s, got: %v", expected, err)
+	}
+}
+
+func TestCheckTaggerForErrors(t *testing.T) {
+	var tests = []struct {
+		name, email string
+		expected    string
+	}{
+		{"", "", fmt.Sprint(nil)},
+		{"Release Bot", "release-bot@example.com", fmt.Sprint(nil)},
+		{"Release Bot", "", fmt.Sprint(nil)},
+		{"", "release-bot@example.com", fmt.Sprint(nil)},
+		{"Release Bot", "release-bot", `error config file .atc.yaml: tagger_email "release-bot" isn't an email address`},
+	}
+	for _, test := range tests {
+		if err := validateSettings(&AtcSettings{TaggerName: test.name, TaggerEmail: test.email}); fmt.Sprint(err) != test.expected {
+			t.Errorf("tagger %q <%s>: expected %s, got %v", test.name, test.email, test.expected, err)
+		}
+	}
+}
+
+func TestCheckTagAnnotatorLoginForErrors(t *testing.T) {
+	for _, login := range []string{"", "release-bot", "a", "Svc2", strings.Repeat("a", 39)} {
+		if err := validateSettings(&AtcSettings{TagAnnotatorLogin: login}); err != nil {
+			t.Errorf("tag_annotator_login %q: unexpected error %v", login, err)
+		}
+	}
+	for _, login := range []string{"-bot", "bot-", "release--bot", "atc[bot]", "release_bot", strings.Repeat("a", 40)} {
+		expected := fmt.Sprintf("error config file .atc.yaml: tag_annotator_login %q isn't a GitHub login", login)
+		if err := validateSettings(&AtcSettings{TagAnnotatorLogin: login}); fmt.Sprint(err) != expected {
+			t.Errorf("expected: %s, got: %v", expected, err)
+		}
+	}
+	expected := "error config file .atc.yaml: tag_annotator_login can't be used with tagger_name and tagger_email"
+	settings := &AtcSettings{TagAnnotatorLogin: "release-bot", TaggerName: "Release Bot", TaggerEmail: "release-bot@example.com"}
+	if err := validateSettings(settings); fmt.Sprint(err) != expected {
+		t.Errorf("expected: %s, got: %v", expected, err)
+	}
+}
+
+func TestCheckTagRefFormatForErrors(t *testing.T) {
+	var tests = []struct {
+		format           string
+		expectedErrorStr string
+	}{
+		{"", fmt.Sprint(nil)},
+		{"refs/tags/%s", fmt.Sprint(nil)},
+		{"refs/environments/prod/%s", fmt.Sprint(nil)},
+		{"refs/environments/prod", `error config file .atc.yaml: tag_ref_format needs exactly one "%s" and no other verbs`},
+		{"refs/%s/%s", `error config file .atc.yaml: tag_ref_format needs exactly one "%s" and no other verbs`},
+		{"refs/%d/%s", `error config file .atc.yaml: tag_ref_format needs exactly one "%s" and no other verbs`},
+		{"tags/%s", `error config file .atc.yaml: tag_ref_format has to start with "refs/"`},
+	}
+	for _, test := range tests {
+		if err := validateSettings(&AtcSettings{TagRefFormat: test.format}); fmt.Sprint(err) != test.expectedErrorStr {
+			t.Errorf("tag_ref_format %q\nexpected: %s, got: %s", test.format, test.expectedErrorStr, err)
+		}
+	}
+}
+
+func TestTargetSettings(t *testing.T) {
+	settings := &AtcSettings{Path: "pom.xml", Template: "v{{.Version}}"}
+	if targets := settings.TargetSettings(); len(targets) != 1 || targets[0] != settings {
+		t.Errorf("expected only the settings, got %+v", targets)
+	}
+
+	settings = &AtcSettings{Template: "v{{.Version}}", Behavior: BehaviorBefore, Targets: []Target{
+		{Path: "services/a/pom.xml", TagPrefix: "service-a/"},
+		{Path: "services/b/package.json", Template: "{{.Version}}"},
+	}}
+	targets := settings.TargetSettings()
+	if len(targets) != 2 {
+		t.Fatalf("expected 2 targets, got %d", len(targets))
+	}
+	if targets[0].Path != "services/a/pom.xml" || targets[0].Template != "service-a/v{{.Version}}" || targets[0].Behavior != BehaviorBefore {
+		t.Errorf("wrong first target %+v", targets[0])
+	}
+	if targets[1].Path != "services/b/package.json" || targets[1].Template != "{{.Version}}" || targets[1].Targets != nil {
+		t.Errorf("wrong second target %+v", targets[1])
+	}
+}
+
+func TestCheckTargetsForErrors(t *testing.T) {
+	var tests = []struct {
+		settings         AtcSettings
+		expectedErrorStr string
+	}{
+		{AtcSettings{Targets: []Target{{Path: "a/pom.xml"}, {Path: "b/pom.xml", TagPrefix: "b/"}}}, fmt.Sprint(nil)},
+		{AtcSettings{Path: "pom.xml", Targets: []Target{{Path: "a/pom.xml"}}}, `error config file .atc.yaml: path can't be used with targets`},
+		{AtcSettings{Targets: []Target{{Path: "a/pom.xml"}, {TagPrefix: "b/"}}}, `error config file .atc.yaml: targets[1] needs path`},
+		{AtcSettings{NPMWorkspaces: true}, fmt.Sprint(nil)},
+		{AtcSettings{Path: "package.json", NPMWorkspaces: true}, `error config file .atc.yaml: path can't be used with npm_workspaces`},
+		{AtcSettings{Targets: []Target{{Path: "a/pom.xml", Template: "a"}}},
+			`error config file .atc.yaml: template doesn't contain "{{.Version}}" (targets[0])`},
+	}
+	for _, test := range tests {
+		if err := validateSettings(&test.settings); fmt.Sprint(err) != test.expectedErrorStr {
+			t.Errorf("settings %+v\nexpected: %s, got: %s", test.settings, test.expectedErrorStr, err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package settings
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/smartforce-io/atc/logger"
+)
+
+const (
+	topicPrefix     = "atc:"
+	topicDashPrefix = "atc-"
+)
+
+// topicFields are the settings a repository topic can override.
+var topicFields = map[string]func(settings *AtcSettings, value string) error{
+	"path":          func(s *AtcSettings, v string) error { s.Path = v; return nil },
+	"behavior":      func(s *AtcSettings, v string) error { s.Behavior = v; return nil },
+	"template":      func(s *AtcSettings, v string) error { s.Template = v; return nil },
+	"branch":        func(s *AtcSettings, v string) error { s.Branch = v; return nil },
+	"regexstr":      func(s *AtcSettings, v string) error { s.RegexStr = v; return nil },
+	"version_field": func(s *AtcSettings, v string) error { s.VersionField = v; return nil },
+	"prerelease": func(s *AtcSettings, v string) error {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("prerelease isn't true or false: %q", v)
+		}
+		s.PreRelease = b
+		return nil
+	},
+}
+
+// parseTopic splits an "atc:key:value" or "atc-key-value" topic. GitHub only
+// allows lowercase letters, digits and hyphens in topics, so the second form
+// is the one that can be set on github.com; its keys use "-" instead of "_".
+func parseTopic(topic string) (key, value string, ok bool) {
+	if rest := strings.TrimPrefix(topic, topicPrefix); rest != topic {
+		key, value, ok = strings.Cut(rest, ":")
+		return key, value, ok && value != ""
+	}
+	if rest := strings.TrimPrefix(topic, topicDashPrefix); rest != topic {
+		for name := range topicFields {
+			prefix := strings.ReplaceAll(name, "_", "-") + "-"
+			if strings.HasPrefix(rest, prefix) && len(rest) > len(prefix) {
+				return name, rest[len(prefix):], true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// ApplyTopics overrides settings with the repository topics prefixed with
+// "atc:" or "atc-", e.g. "atc:template:v{{.Version}}" or "atc-branch-develop",
+// and validates the result. Other topics are ignored.
+func (settings *AtcSettings) ApplyTopics(topics []string) error {
+	applied := false
+	for _, topic := range topics {
+		key, value, ok := parseTopic(topic)
+		if !ok {
+			if strings.HasPrefix(topic, topicPrefix) {
+				logger.Warnf("ignore repository topic %q: not \"atc:key:value\"", topic)
+			}
+			continue
+		}
+		set, found := topicFields[key]
+		if !found {
+			logger.Warnf("ignore repository topic %q: %q can't be set by a topic", topic, key)
+			continue
+		}
+		if err := set(settings, value); err != nil {
+			return fmt.Errorf("error repository topic %q: %v", topic, err)
+		}
+		applied = true
+	}
+	if !applied {
+		return nil
+	}
+	return validateSettings(settings)
+}
@@ -0,0 +1,35 @@
+package settings
+
+import "testing"
+
+func TestApplyTopics(t *testing.T) {
+	settings := &AtcSettings{Path: "pom.xml", Behavior: BehaviorAfter, Template: "v{{.Version}}", Branch: "main"}
+	topics := []string{"golang", "atc:template:release-{{.Version}}", "atc-branch-develop", "atc-version-field-versionName", "atc-prerelease-true", "atc:unknown:x"}
+	if err := settings.ApplyTopics(topics); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if settings.Template != "release-{{.Version}}" {
+		t.Errorf("template %q, expected %q", settings.Template, "release-{{.Version}}")
+	}
+	if settings.Branch != "develop" {
+		t.Errorf("branch %q, expected %q", settings.Branch, "develop")
+	}
+	if settings.VersionField != VersionFieldVersionName {
+		t.Errorf("version_field %q, expected %q", settings.VersionField, VersionFieldVersionName)
+	}
+	if !settings.PreRelease {
+		t.Error("prerelease isn't set")
+	}
+	if settings.Path != "pom.xml" || settings.Behavior != BehaviorAfter {
+		t.Errorf("unexpected override: %+v", settings)
+	}
+}
+
+func TestApplyTopicsErrors(t *testing.T) {
+	for _, topic := range []string{"atc:template:v", "atc:behavior:sometimes", "atc-prerelease-maybe"} {
+		settings := &AtcSettings{Behavior: BehaviorAfter, Template: "v{{.Version}}"}
+		if err := settings.ApplyTopics([]string{topic}); err == nil {
+			t.Errorf("expected an error for topic %q", topic)
+		}
+	}
+}
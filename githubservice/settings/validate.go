@@ -0,0 +1,94 @@
+package settings
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Diagnostic is a problem found in a .atc.yaml. Line is 0 when it isn't
+// known.
+type Diagnostic struct {
+	Line     int
+	Severity string
+	Message  string
+}
+
+var (
+	yamlLineRegex   = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+	unknownKeyRegex = regexp.MustCompile(`^field (\S+) not found in type \S+$`)
+	errorPrefixes   = []string{"error config file .atc.yaml: ", "error config file .atc.yaml; "}
+	settingKeyWord  = regexp.MustCompile(`[a-z][a-z0-9_]*`)
+)
+
+// Validate checks content the way GetAtcSetting does and returns the
+// settings, nil if content can't be parsed, with the problems found. Unknown
+// keys are warnings, ATC ignores them.
+func Validate(content []byte) (*AtcSettings, []Diagnostic) {
+	settings := &AtcSettings{}
+	if err := unmarshal(content, settings); err != nil {
+		return nil, yamlDiagnostics(err, SeverityError)
+	}
+	var diagnostics []Diagnostic
+	if err := yaml.UnmarshalStrict(content, &AtcSettings{}); err != nil {
+		diagnostics = append(diagnostics, yamlDiagnostics(err, SeverityWarning)...)
+	}
+	if err := validateSettings(settings); err == nil {
+		settings.normalize()
+	} else {
+		message := err.Error()
+		for _, prefix := range errorPrefixes {
+			message = strings.TrimPrefix(message, prefix)
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			Line:     KeyLine(content, message),
+			Severity: SeverityError,
+			Message:  message,
+		})
+	}
+	return settings, diagnostics
+}
+
+// yamlDiagnostics splits a yaml error into diagnostics with their lines.
+func yamlDiagnostics(err error, severity string) []Diagnostic {
+	messages := []string{err.Error()}
+	if typeError, ok := err.(*yaml.TypeError); ok {
+		messages = typeError.Errors
+	}
+	diagnostics := make([]Diagnostic, 0, len(messages))
+	for _, message := range messages {
+		diagnostic := Diagnostic{Severity: severity, Message: message}
+		if match := yamlLineRegex.FindStringSubmatch(message); match != nil {
+			diagnostic.Line, _ = strconv.Atoi(match[1])
+			diagnostic.Message = match[2]
+		}
+		if match := unknownKeyRegex.FindStringSubmatch(diagnostic.Message); match != nil {
+			diagnostic.Message = fmt.Sprintf("unknown key %q", match[1])
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	return diagnostics
+}
+
+// KeyLine returns the line of the first key of content named in message,
+// e.g. "trigger" for `trigger isn't "push" or "pr_merge"`, or 0.
+func KeyLine(content []byte, message string) int {
+	lines := strings.Split(string(content), "\n")
+	for _, word := range settingKeyWord.FindAllString(message, -1) {
+		key := regexp.MustCompile(`^\s*(?:-\s+)?` + regexp.QuoteMeta(word) + `\s*:`)
+		for i, line := range lines {
+			if key.MatchString(line) {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
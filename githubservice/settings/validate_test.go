@@ -0,0 +1,35 @@
+package settings
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	var tests = []struct {
+		content     string
+		expected    []Diagnostic
+		parseFailed bool
+	}{
+		{"path: pom.xml\ntemplate: v{{.Version}}\n", nil, false},
+		{"path: pom.xml\ntrigger: pull_request\n",
+			[]Diagnostic{{2, SeverityError, `trigger isn't "push" or "pr_merge"`}}, false},
+		{"path: pom.xml\ntagprefix: v\ntemplate: \"{{.Version}}\"\n",
+			[]Diagnostic{{2, SeverityWarning, `unknown key "tagprefix"`}}, false},
+		{"path: pom.xml\ntargets:\n  - path: a/pom.xml\n  - tag_prefix: b/\n",
+			[]Diagnostic{{1, SeverityError, "path can't be used with targets"}}, false},
+		{"targets:\n  - path: a/pom.xml\n  - tag_prefix: b/\n",
+			[]Diagnostic{{1, SeverityError, "targets[1] needs path"}}, false},
+		{"path: pom.xml\n  template: [\n",
+			[]Diagnostic{{2, SeverityError, "mapping values are not allowed in this context"}}, true},
+	}
+	for _, test := range tests {
+		settings, diagnostics := Validate([]byte(test.content))
+		if !reflect.DeepEqual(diagnostics, test.expected) {
+			t.Errorf("content %q:\nexpected %+v\ngot      %+v", test.content, test.expected, diagnostics)
+		}
+		if (settings == nil) != test.parseFailed {
+			t.Errorf("content %q: expected parse failure %v, got settings %+v", test.content, test.parseFailed, settings)
+		}
+	}
+}
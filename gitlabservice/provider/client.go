@@ -0,0 +1,138 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	ghprovider "github.com/smartforce-io/atc/githubservice/provider"
+)
+
+const DefaultBaseURL = "https://gitlab.com/api/v4"
+
+// Client is a minimal GitLab REST API v4 client covering what ATC needs:
+// reading files, reading commits and creating tags.
+type Client struct {
+	BaseURL string
+	Token   string
+	// JobToken marks Token as a CI_JOB_TOKEN, which is sent in a different header.
+	JobToken   bool
+	HTTPClient *http.Client
+}
+
+type Commit struct {
+	ID        string   `json:"id"`
+	ParentIDs []string `json:"parent_ids"`
+}
+
+type Tag struct {
+	Name   string `json:"name"`
+	Target string `json:"target"`
+}
+
+func NewClient(baseURL, token string, jobToken bool) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{
+		BaseURL:    baseURL,
+		Token:      token,
+		JobToken:   jobToken,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (c *Client) do(method, path string, query url.Values, body interface{}) ([]byte, error) {
+	u := c.BaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, u, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.JobToken {
+		req.Header.Set("JOB-TOKEN", c.Token)
+	} else {
+		req.Header.Set("PRIVATE-TOKEN", c.Token)
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%w: %s %s: %d %s", ghprovider.ErrHttpStatusCode, method, path, resp.StatusCode, respBody)
+	}
+	return respBody, nil
+}
+
+func projectPath(project string) string {
+	return "/projects/" + url.PathEscape(project)
+}
+
+// GetFile returns the raw content of path at ref.
+func (c *Client) GetFile(project, path, ref string) (string, error) {
+	query := url.Values{}
+	if ref != "" {
+		query.Set("ref", ref)
+	} else {
+		query.Set("ref", "HEAD")
+	}
+	body, err := c.do(http.MethodGet, projectPath(project)+"/repository/files/"+url.PathEscape(path)+"/raw", query, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+func (c *Client) GetCommit(project, sha string) (*Commit, error) {
+	body, err := c.do(http.MethodGet, projectPath(project)+"/repository/commits/"+url.PathEscape(sha), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	commit := &Commit{}
+	if err := json.Unmarshal(body, commit); err != nil {
+		return nil, err
+	}
+	return commit, nil
+}
+
+// CreateTag creates an annotated tag when message isn't empty, otherwise a lightweight one.
+func (c *Client) CreateTag(project, name, ref, message string) (*Tag, error) {
+	req := map[string]string{
+		"tag_name": name,
+		"ref":      ref,
+	}
+	if message != "" {
+		req["message"] = message
+	}
+	body, err := c.do(http.MethodPost, projectPath(project)+"/repository/tags", nil, req)
+	if err != nil {
+		return nil, err
+	}
+	tag := &Tag{}
+	if err := json.Unmarshal(body, tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
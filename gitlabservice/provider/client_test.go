@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	ghprovider "github.com/smartforce-io/atc/githubservice/provider"
+)
+
+func TestGlContentProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("JOB-TOKEN") != "job" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.EscapedPath() != "/projects/group%2Fatc/repository/files/app%2Fpom.xml/raw" || r.URL.Query().Get("ref") != "abc" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("<version>5</version>"))
+	}))
+	defer server.Close()
+
+	cp := &GlContentProvider{Client: NewClient(server.URL, "job", true), Project: "group/atc", Ref: "abc"}
+	content, err := cp.GetContents("app/pom.xml")
+	if err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if content != "<version>5</version>" {
+		t.Errorf("wrong content! Got %q", content)
+	}
+
+	_, err = cp.GetContents("pom.xml")
+	if !errors.Is(err, ghprovider.ErrHttpStatusCode) {
+		t.Errorf("err:%v  !=  ErrHttpStatusCode", err)
+	}
+}
+
+func TestCreateTag(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("PRIVATE-TOKEN") != "token" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"name": "v5", "target": "abc"}`))
+	}))
+	defer server.Close()
+
+	tag, err := NewClient(server.URL, "token", false).CreateTag("group/atc", "v5", "abc", "v5")
+	if err != nil {
+		t.Errorf("Unexpected error %v", err)
+		return
+	}
+	if tag.Name != "v5" || got["tag_name"] != "v5" || got["ref"] != "abc" || got["message"] != "v5" {
+		t.Errorf("wrong tag request: %v, response: %v", got, tag)
+	}
+}
+
+func TestNewClientTimeout(t *testing.T) {
+	client := NewClient("", "token", false)
+	if client.BaseURL != DefaultBaseURL || client.HTTPClient.Timeout != 10*time.Second {
+		t.Errorf("expected %s with a 10s timeout, got %s with %s", DefaultBaseURL, client.BaseURL, client.HTTPClient.Timeout)
+	}
+}
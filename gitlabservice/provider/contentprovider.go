@@ -0,0 +1,14 @@
+package provider
+
+// GlContentProvider reads files of a GitLab project at Ref and satisfies
+// the githubservice provider.ContentProvider interface, so all version
+// fetchers work with GitLab as well.
+type GlContentProvider struct {
+	Client  *Client
+	Project string
+	Ref     string
+}
+
+func (glcp *GlContentProvider) GetContents(path string) (string, error) {
+	return glcp.Client.GetFile(glcp.Project, path, glcp.Ref)
+}
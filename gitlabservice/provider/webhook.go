@@ -0,0 +1,16 @@
+package provider
+
+// PushEvent is the part of a GitLab "Push Hook" payload ATC needs.
+type PushEvent struct {
+	ObjectKind   string  `json:"object_kind"`
+	Ref          string  `json:"ref"`
+	Before       string  `json:"before"`
+	After        string  `json:"after"`
+	UserUsername string  `json:"user_username"`
+	Project      Project `json:"project"`
+}
+
+type Project struct {
+	PathWithNamespace string `json:"path_with_namespace"`
+	DefaultBranch     string `json:"default_branch"`
+}
@@ -0,0 +1,169 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+)
+
+type LogLevel int32
+
+const (
+	Debug LogLevel = iota
+	Info
+	Warn
+	Error
+)
+
+var levelNames = map[LogLevel]string{
+	Debug: "DEBUG",
+	Info:  "INFO",
+	Warn:  "WARN",
+	Error: "ERROR",
+}
+
+var slogLevels = map[LogLevel]slog.Level{
+	Debug: slog.LevelDebug,
+	Info:  slog.LevelInfo,
+	Warn:  slog.LevelWarn,
+	Error: slog.LevelError,
+}
+
+var level = int32(Info)
+
+var jsonOutput atomic.Bool
+
+func (l LogLevel) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("LogLevel(%d)", int32(l))
+}
+
+// SetLogLevel hides messages below l. The default level is Info.
+func SetLogLevel(l LogLevel) {
+	atomic.StoreInt32(&level, int32(l))
+}
+
+// ParseLogLevel returns the level named "debug", "info", "warn" or "error".
+func ParseLogLevel(name string) (LogLevel, error) {
+	for l, levelName := range levelNames {
+		if strings.EqualFold(name, levelName) {
+			return l, nil
+		}
+	}
+	return Info, fmt.Errorf("unknown log level %q", name)
+}
+
+// SetJSONOutput writes the messages as JSON objects with the time, level,
+// message and fields instead of text lines.
+func SetJSONOutput(enabled bool) {
+	jsonOutput.Store(enabled)
+}
+
+// Enabled reports whether messages of level l are written.
+func Enabled(l LogLevel) bool {
+	return int32(l) >= atomic.LoadInt32(&level)
+}
+
+func output(l LogLevel, attrs []slog.Attr, format string, v ...interface{}) {
+	if !Enabled(l) {
+		return
+	}
+	msg := fmt.Sprintf(format, v...)
+	if jsonOutput.Load() {
+		slog.New(slog.NewJSONHandler(log.Writer(), &slog.HandlerOptions{Level: slog.LevelDebug})).
+			LogAttrs(context.Background(), slogLevels[l], msg, attrs...)
+		return
+	}
+	var sb strings.Builder
+	sb.WriteString(l.String() + ": " + msg)
+	for _, attr := range attrs {
+		sb.WriteString(" " + attr.String())
+	}
+	log.Output(3, sb.String())
+}
+
+func Debugf(format string, v ...interface{}) {
+	output(Debug, nil, format, v...)
+}
+
+func Infof(format string, v ...interface{}) {
+	output(Info, nil, format, v...)
+}
+
+func Warnf(format string, v ...interface{}) {
+	output(Warn, nil, format, v...)
+}
+
+func Errorf(format string, v ...interface{}) {
+	output(Error, nil, format, v...)
+}
+
+// Logger writes messages with fields, e.g. the repository and delivery ID
+// of a webhook, so the messages of one request can be told apart. The nil
+// Logger has no fields.
+type Logger struct {
+	attrs []slog.Attr
+}
+
+// With returns a Logger with the key-value pairs in args as fields.
+func With(args ...interface{}) *Logger {
+	return (*Logger)(nil).With(args...)
+}
+
+// With returns a Logger with the fields of l and the key-value pairs in
+// args.
+func (l *Logger) With(args ...interface{}) *Logger {
+	var attrs []slog.Attr
+	if l != nil {
+		attrs = append(attrs, l.attrs...)
+	}
+	record := slog.Record{}
+	record.Add(args...)
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr)
+		return true
+	})
+	return &Logger{attrs: attrs}
+}
+
+func (l *Logger) fields() []slog.Attr {
+	if l == nil {
+		return nil
+	}
+	return l.attrs
+}
+
+func (l *Logger) Debugf(format string, v ...interface{}) {
+	output(Debug, l.fields(), format, v...)
+}
+
+func (l *Logger) Infof(format string, v ...interface{}) {
+	output(Info, l.fields(), format, v...)
+}
+
+func (l *Logger) Warnf(format string, v ...interface{}) {
+	output(Warn, l.fields(), format, v...)
+}
+
+func (l *Logger) Errorf(format string, v ...interface{}) {
+	output(Error, l.fields(), format, v...)
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying l.
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the Logger of ctx, a Logger without fields if ctx
+// has none.
+func FromContext(ctx context.Context) *Logger {
+	l, _ := ctx.Value(contextKey{}).(*Logger)
+	return l
+}
@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLogLevel(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+	defer SetLogLevel(Info)
+
+	var tests = []struct {
+		level    LogLevel
+		expected []string
+	}{
+		{Debug, []string{"DEBUG: d", "INFO: i", "WARN: w", "ERROR: e"}},
+		{Info, []string{"INFO: i", "WARN: w", "ERROR: e"}},
+		{Error, []string{"ERROR: e"}},
+	}
+	for _, test := range tests {
+		buf.Reset()
+		SetLogLevel(test.level)
+		Debugf("d")
+		Infof("i")
+		Warnf("w")
+		Errorf("e")
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != len(test.expected) {
+			t.Fatalf("level %v: expected %d lines, got %q", test.level, len(test.expected), lines)
+		}
+		for i, line := range lines {
+			if !strings.HasSuffix(line, test.expected[i]) {
+				t.Errorf("level %v: expected %q, got %q", test.level, test.expected[i], line)
+			}
+		}
+	}
+}
+
+func TestParseLogLevel(t *testing.T) {
+	var tests = []struct {
+		name    string
+		level   LogLevel
+		invalid bool
+	}{
+		{"debug", Debug, false},
+		{"INFO", Info, false},
+		{"Warn", Warn, false},
+		{"error", Error, false},
+		{"verbose", Info, true},
+	}
+	for _, test := range tests {
+		level, err := ParseLogLevel(test.name)
+		if (err != nil) != test.invalid || level != test.level {
+			t.Errorf("%q: expected %v, got %v, %v", test.name, test.level, level, err)
+		}
+	}
+}
+
+func TestLoggerFields(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	l := With("delivery_id", "d1").With("repo", "o/r")
+	l.Infof("tagged %s", "v1")
+	if line := strings.TrimSpace(buf.String()); !strings.HasSuffix(line, "INFO: tagged v1 delivery_id=d1 repo=o/r") {
+		t.Errorf("wrong text line %q", line)
+	}
+
+	buf.Reset()
+	SetJSONOutput(true)
+	defer SetJSONOutput(false)
+	l.Warnf("tagged %s", "v1")
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("wrong json line %q: %v", buf.String(), err)
+	}
+	if entry["level"] != "WARN" || entry["msg"] != "tagged v1" || entry["delivery_id"] != "d1" || entry["repo"] != "o/r" {
+		t.Errorf("wrong json entry %v", entry)
+	}
+}
+
+func TestLoggerContext(t *testing.T) {
+	if l := FromContext(context.Background()); l != nil {
+		t.Errorf("expected no logger, got %v", l)
+	}
+	l := With("sha", "abc")
+	if got := FromContext(NewContext(context.Background(), l)); got != l {
+		t.Errorf("expected %v, got %v", l, got)
+	}
+	// the nil Logger has no fields
+	var nilLogger *Logger
+	if got := nilLogger.With("a", 1); len(got.attrs) != 1 {
+		t.Errorf("expected 1 field, got %v", got.attrs)
+	}
+}
@@ -1,17 +1,39 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/smartforce-io/atc/apiserver"
+	"github.com/smartforce-io/atc/dedup"
+	"github.com/smartforce-io/atc/envvars"
+	"github.com/smartforce-io/atc/githubservice/provider"
 	"github.com/smartforce-io/atc/githubservice/push"
+	"github.com/smartforce-io/atc/logger"
 )
 
 func main() {
-	log.Println("Automated Tag Creator")
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		os.Exit(validate(os.Args[2:], os.Stdout, os.Stderr))
+	}
+	if name := os.Getenv(envvars.LogLevel); name != "" {
+		level, err := logger.ParseLogLevel(name)
+		if err != nil {
+			log.Fatalf("error %s: %v", envvars.LogLevel, err)
+		}
+		logger.SetLogLevel(level)
+	}
+	logger.SetJSONOutput(strings.EqualFold(os.Getenv(envvars.LogFormat), "json"))
+	logger.Infof("Automated Tag Creator")
 	mode := os.Getenv("CI_MODE")
 	switch {
+	case mode == "scheduled":
+		err := push.CIScheduledAction(context.Background(), os.Getenv("SCHEDULED_TEMPLATE"))
+		if err != nil {
+			log.Fatalf("error creating scheduled tag %v", err)
+		}
 	case mode != "":
 		err := push.CIActionPush()
 		if err != nil {
@@ -22,6 +44,26 @@ func main() {
 		if port == "" {
 			port = "8080"
 		}
-		apiserver.Instance().Start(":" + port)
+		var store dedup.Store = dedup.NewMemoryStore(dedup.DefaultCapacity, dedup.DefaultTTL)
+		if redisURL := os.Getenv(envvars.RedisURL); redisURL != "" {
+			redisStore, err := dedup.NewRedisStore(redisURL, dedup.DefaultTTL)
+			if err != nil {
+				log.Fatalf("error %s: %v", envvars.RedisURL, err)
+			}
+			store = redisStore
+		}
+		push.Dedup = store
+		retryQueue, err := push.NewTagRetryQueue(os.Getenv(envvars.RetryQueueFile), &provider.GithubClientProvider{})
+		if err != nil {
+			log.Fatalf("error %s: %v", envvars.RetryQueueFile, err)
+		}
+		push.TagRetryQueue = retryQueue
+		go retryQueue.Run(context.Background())
+		apiserver.Instance().Start(apiserver.ServerConfig{
+			Addr:          ":" + port,
+			WebhookSecret: os.Getenv(envvars.WebhookSecret),
+			TLSCertFile:   os.Getenv(envvars.TLSCertFile),
+			TLSKeyFile:    os.Getenv(envvars.TLSKeyFile),
+		})
 	}
 }
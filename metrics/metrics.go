@@ -0,0 +1,22 @@
+// Package metrics keeps the ATC metrics and serves them to Prometheus.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// VersionFetchDuration is the time a fetcher takes to read a version.
+var VersionFetchDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "atc_version_fetch_duration_seconds",
+	Help:    "Time taken to fetch a version from a repository file.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"fetcher_type"})
+
+// Handler serves the metrics of the default Prometheus registry.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	VersionFetchDuration.WithLabelValues("pom.xml").Observe(0.05)
+	VersionFetchDuration.WithLabelValues("pom.xml").Observe(0.5)
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+
+	for _, expected := range []string{
+		"# TYPE atc_version_fetch_duration_seconds histogram",
+		`atc_version_fetch_duration_seconds_bucket{fetcher_type="pom.xml",le="0.05"} 1`,
+		`atc_version_fetch_duration_seconds_bucket{fetcher_type="pom.xml",le="+Inf"} 2`,
+		`atc_version_fetch_duration_seconds_count{fetcher_type="pom.xml"} 2`,
+	} {
+		if !strings.Contains(string(body), expected) {
+			t.Errorf("expected %q in:\n%s", expected, body)
+		}
+	}
+}
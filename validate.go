@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/smartforce-io/atc/githubservice/push"
+	"github.com/smartforce-io/atc/githubservice/settings"
+)
+
+// validate runs `atc validate [--config .atc.yaml]`, printing the problems
+// of the config to stdout. It returns the exit code, 1 if there are errors.
+func validate(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	configPath := flags.String("config", ".atc.yaml", "path of the config file")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	content, err := os.ReadFile(*configPath)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	code := 0
+	diagnostics := push.ValidateConfig(content)
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Line == 0 {
+			fmt.Fprintf(stdout, "%s: %s: %s\n", *configPath, diagnostic.Severity, diagnostic.Message)
+		} else {
+			fmt.Fprintf(stdout, "%s:%d: %s: %s\n", *configPath, diagnostic.Line, diagnostic.Severity, diagnostic.Message)
+		}
+		if diagnostic.Severity == settings.SeverityError {
+			code = 1
+		}
+	}
+	if len(diagnostics) == 0 {
+		fmt.Fprintf(stdout, "%s is valid\n", *configPath)
+	}
+	return code
+}